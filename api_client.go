@@ -3,20 +3,32 @@ package identitymind
 import (
 	"bytes"
 	"compress/gzip"
+	"context"
 	"encoding/base64"
 	"encoding/json"
 	"fmt"
 	"io"
+	"io/ioutil"
 	"mime/multipart"
+	"net"
 	"net/http"
 	"net/url"
 	"strings"
 	"time"
 
+	"github.com/kthomas/go-logger"
 	"github.com/vincent-petithory/dataurl"
 )
 
 const defaultContentType = "application/json"
+const defaultRequestTimeout = time.Second * 30
+
+// defaultHTTPClient is shared by all IdentityMindAPIClient instances which have not been
+// configured with an HTTPClient or Transport, allowing keep-alive connections to be reused;
+// it sets no Timeout, as requests are bounded by the context deadline derived from the client Timeout
+var defaultHTTPClient = &http.Client{
+	Transport: newDefaultTransport(),
+}
 
 // IdentityMindAPIClient is a generic base class for calling the identitymind API
 type IdentityMindAPIClient struct {
@@ -26,33 +38,42 @@ type IdentityMindAPIClient struct {
 	Token    *string
 	Username *string
 	Password *string
+
+	// HTTPClient, when non-nil, is used to send all requests; it takes precedence over Transport
+	HTTPClient *http.Client
+
+	// Transport, when non-nil and HTTPClient is nil, is used to send all requests, i.e. to
+	// configure proxies, custom TLS roots or mutual TLS
+	Transport http.RoundTripper
+
+	// Timeout, when positive, bounds the total duration of each API invocation, including retries;
+	// NewClient defaults it to 30 seconds
+	Timeout time.Duration
+
+	// RetryPolicy, when non-nil, governs the retry of requests which fail transiently
+	RetryPolicy *RetryPolicy
+
+	// Logger, when non-nil, is used in place of the package-level logger
+	Logger *logger.Logger
+
+	// TravelRulePolicy, when non-nil, is checked before reporting transfers and withdrawals via ReportTransfer
+	TravelRulePolicy *TravelRulePolicy
 }
 
 // NewIdentityMindAPIClient initializes an IdentityMindAPIClient using the environment-configured API
 // user and token to construct an HTTP basic authorization header for access to the IdentityMind API.
+// As before the introduction of NewClient, each invocation is bounded to 30 seconds and failed requests
+// are not retried; use NewClient with WithEnvironmentVariables to opt in to retries.
 func NewIdentityMindAPIClient() (*IdentityMindAPIClient, error) {
-	apiURL, err := url.Parse(identitymindAPIBaseURL)
-	if err != nil {
-		log.Warningf("Failed to parse identitymind API base url; %s", err.Error())
-		return nil, err
-	}
-
-	return &IdentityMindAPIClient{
-		Host:     apiURL.Host,
-		Scheme:   apiURL.Scheme,
-		Path:     "",
-		Username: stringOrNil(identitymindAPIUser),
-		Password: stringOrNil(identitymindAPIToken),
-	}, nil
+	return NewClient(
+		WithBaseURL(identitymindAPIBaseURL),
+		WithBasicAuth(identitymindAPIUser, identitymindAPIToken),
+		WithRetryPolicy(nil),
+	)
 }
 
-func (i *IdentityMindAPIClient) sendRequest(method, urlString, contentType string, params map[string]interface{}, response interface{}) (status int, err error) {
-	client := &http.Client{
-		Transport: &http.Transport{
-			DisableKeepAlives: true,
-		},
-		Timeout: time.Second * 30,
-	}
+func (i *IdentityMindAPIClient) sendRequest(ctx context.Context, method, urlString, contentType string, params map[string]interface{}, response interface{}) (status int, err error) {
+	log := i.logger()
 
 	mthd := strings.ToUpper(method)
 	reqURL, err := url.Parse(urlString)
@@ -71,18 +92,9 @@ func (i *IdentityMindAPIClient) sendRequest(method, urlString, contentType strin
 		reqURL.RawQuery = q.Encode()
 	}
 
-	headers := map[string][]string{
-		"Accept-Encoding": {"gzip, deflate"},
-		"Accept-Language": {"en-us"},
-		"Accept":          {"application/json"},
-	}
-	if i.Username != nil && i.Password != nil {
-		headers["Authorization"] = []string{buildBasicAuthorizationHeader(*i.Username, *i.Password)}
-	} else if i.Token != nil {
-		headers["Authorization"] = []string{fmt.Sprintf("Bearer %s", *i.Token)}
-	}
+	headers := i.buildHeaders()
 
-	var req *http.Request
+	var newBody func() (io.Reader, error)
 
 	if mthd == "POST" || mthd == "PUT" {
 		var payload []byte
@@ -128,27 +140,62 @@ func (i *IdentityMindAPIClient) sendRequest(method, urlString, contentType strin
 			}
 
 			payload = []byte(body.Bytes())
+			contentType = writer.FormDataContentType()
 		}
 
-		req, _ = http.NewRequest(method, urlString, bytes.NewReader(payload))
+		newBody = func() (io.Reader, error) {
+			return bytes.NewReader(payload), nil
+		}
 		headers["Content-Type"] = []string{contentType}
-	} else {
-		req = &http.Request{
-			URL:    reqURL,
-			Method: mthd,
+	}
+
+	return i.roundTrip(ctx, mthd, reqURL, headers, newBody, true, response)
+}
+
+// sendStream constructs and sends an API request whose body is read from the given reader exactly
+// once, i.e. as it is being written by another goroutine; such requests are never retried
+func (i *IdentityMindAPIClient) sendStream(ctx context.Context, method, urlString, contentType string, body io.Reader, response interface{}) (status int, err error) {
+	log := i.logger()
+
+	mthd := strings.ToUpper(method)
+	reqURL, err := url.Parse(urlString)
+	if err != nil {
+		log.Warningf("Failed to parse URL for identitymind API (%s %s) invocation; %s", method, urlString, err.Error())
+		return -1, err
+	}
+
+	headers := i.buildHeaders()
+	headers["Content-Type"] = []string{contentType}
+
+	consumed := false
+	newBody := func() (io.Reader, error) {
+		if consumed {
+			return nil, fmt.Errorf("Failed to replay streamed identitymind API (%s %s) request body", method, urlString)
 		}
+		consumed = true
+		return body, nil
 	}
 
-	req.Header = headers
+	return i.roundTrip(ctx, mthd, reqURL, headers, newBody, false, response)
+}
+
+// roundTrip executes the request and unmarshals the response; retries are only attempted when replayable is true
+func (i *IdentityMindAPIClient) roundTrip(ctx context.Context, method string, reqURL *url.URL, headers http.Header, newBody func() (io.Reader, error), replayable bool, response interface{}) (status int, err error) {
+	log := i.logger()
+	urlString := reqURL.String()
 
-	resp, err := client.Do(req)
-	if resp != nil && resp.Body != nil {
-		defer resp.Body.Close()
+	if i.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, i.Timeout)
+		defer cancel()
 	}
+
+	resp, err := i.execute(ctx, method, reqURL, headers, newBody, replayable)
 	if err != nil {
-		log.Warningf("Failed to invoke identitymind API (%s %s) method: %s; %s", method, urlString, err.Error())
+		log.Warningf("Failed to invoke identitymind API (%s %s) method; %s", method, urlString, err.Error())
 		return 0, err
 	}
+	defer resp.Body.Close()
 
 	log.Debugf("Received %v response for identitymind API (%s %s) invocation", resp.StatusCode, method, urlString)
 
@@ -156,13 +203,31 @@ func (i *IdentityMindAPIClient) sendRequest(method, urlString, contentType strin
 	switch resp.Header.Get("Content-Encoding") {
 	case "gzip":
 		reader, err = gzip.NewReader(resp.Body)
+		if err != nil {
+			return resp.StatusCode, fmt.Errorf("Failed to decompress identitymind API (%s %s) response; %s", method, urlString, err.Error())
+		}
 		defer reader.Close()
 	default:
 		reader = resp.Body
 	}
 
 	buf := new(bytes.Buffer)
-	buf.ReadFrom(reader)
+	_, err = buf.ReadFrom(reader)
+	if err != nil {
+		return resp.StatusCode, fmt.Errorf("Failed to read identitymind API (%s %s) response; %s", method, urlString, err.Error())
+	}
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		apiErr := newAPIError(method, strings.TrimPrefix(reqURL.Path, "/"), resp.StatusCode, buf.Bytes())
+		log.Warningf("identitymind API (%s %s) invocation failed; %s", method, urlString, apiErr.Error())
+		return resp.StatusCode, apiErr
+	}
+
+	if buf.Len() == 0 {
+		log.Debugf("Invocation of identitymind API (%s %s) succeeded (empty response)", method, urlString)
+		return resp.StatusCode, nil
+	}
+
 	err = json.Unmarshal(buf.Bytes(), &response)
 	if err != nil {
 		return resp.StatusCode, fmt.Errorf("Failed to unmarshal identitymind API (%s %s) response: %s; %s", method, urlString, buf.Bytes(), err.Error())
@@ -172,40 +237,184 @@ func (i *IdentityMindAPIClient) sendRequest(method, urlString, contentType strin
 	return resp.StatusCode, nil
 }
 
+// execute sends the request described by the given method, URL, headers and body, retrying transient
+// failures of replayable requests in accordance with the client retry policy; the caller must close the
+// returned response body
+func (i *IdentityMindAPIClient) execute(ctx context.Context, method string, reqURL *url.URL, headers http.Header, newBody func() (io.Reader, error), replayable bool) (*http.Response, error) {
+	log := i.logger()
+	client := i.httpClient()
+
+	idempotencyKey := IdempotencyKeyFromContext(ctx)
+	if idempotencyKey != "" {
+		headers.Set(idempotencyKeyHeader, idempotencyKey)
+	}
+
+	policy := i.RetryPolicy
+	attempts := 1
+	if policy != nil && replayable && (isSafeMethod(method) || idempotencyKey != "") {
+		attempts = policy.maxAttempts()
+	}
+
+	for attempt := 1; ; attempt++ {
+		var body io.Reader
+		if newBody != nil {
+			var err error
+			body, err = newBody()
+			if err != nil {
+				return nil, err
+			}
+		}
+
+		req, err := http.NewRequestWithContext(ctx, method, reqURL.String(), body)
+		if err != nil {
+			return nil, err
+		}
+		req.Header = headers.Clone()
+
+		resp, err := client.Do(req)
+		if attempt >= attempts {
+			return resp, err
+		}
+
+		var wait time.Duration
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil, err
+			}
+			wait = policy.backoff(attempt)
+			log.Debugf("Retrying identitymind API (%s %s) invocation in %v after attempt %d failed; %s", method, reqURL.String(), wait, attempt, err.Error())
+		} else if policy.isRetryableStatus(resp.StatusCode) {
+			wait = policy.backoff(attempt)
+			if retryAfter, ok := parseRetryAfter(resp.Header.Get("Retry-After"), time.Now()); ok {
+				if policy.MaxRetryAfter > 0 && retryAfter > policy.MaxRetryAfter {
+					return resp, nil
+				}
+				wait = retryAfter
+			}
+			log.Debugf("Retrying identitymind API (%s %s) invocation in %v after attempt %d returned %d", method, reqURL.String(), wait, attempt, resp.StatusCode)
+		} else {
+			return resp, nil
+		}
+
+		if deadline, ok := ctx.Deadline(); ok && time.Now().Add(wait).After(deadline) {
+			return resp, err
+		}
+
+		if resp != nil {
+			io.Copy(ioutil.Discard, resp.Body)
+			resp.Body.Close()
+		}
+
+		timer := time.NewTimer(wait)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return nil, ctx.Err()
+		case <-timer.C:
+		}
+	}
+}
+
 // Get constructs and synchronously sends an API GET request
 func (i *IdentityMindAPIClient) Get(uri string, params map[string]interface{}, response interface{}) (status int, err error) {
+	return i.GetWithContext(context.Background(), uri, params, response)
+}
+
+// GetWithContext constructs and synchronously sends an API GET request bound to the given context
+func (i *IdentityMindAPIClient) GetWithContext(ctx context.Context, uri string, params map[string]interface{}, response interface{}) (status int, err error) {
 	url := i.buildURL(uri)
-	return i.sendRequest("GET", url, defaultContentType, params, response)
+	return i.sendRequest(ctx, "GET", url, defaultContentType, params, response)
 }
 
 // Post constructs and synchronously sends an API POST request
 func (i *IdentityMindAPIClient) Post(uri string, params map[string]interface{}, response interface{}) (status int, err error) {
+	return i.PostWithContext(context.Background(), uri, params, response)
+}
+
+// PostWithContext constructs and synchronously sends an API POST request bound to the given context
+func (i *IdentityMindAPIClient) PostWithContext(ctx context.Context, uri string, params map[string]interface{}, response interface{}) (status int, err error) {
 	url := i.buildURL(uri)
-	return i.sendRequest("POST", url, defaultContentType, params, response)
+	return i.sendRequest(ctx, "POST", url, defaultContentType, params, response)
 }
 
-// PostWWWFormURLEncoded constructs and synchronously sends an API POST request using
+// PostWWWFormURLEncoded constructs and synchronously sends an API POST request using application/x-www-form-urlencoded as the content-type
 func (i *IdentityMindAPIClient) PostWWWFormURLEncoded(uri string, params map[string]interface{}, response interface{}) (status int, err error) {
+	return i.PostWWWFormURLEncodedWithContext(context.Background(), uri, params, response)
+}
+
+// PostWWWFormURLEncodedWithContext constructs and synchronously sends an API POST request bound to the given context using application/x-www-form-urlencoded as the content-type
+func (i *IdentityMindAPIClient) PostWWWFormURLEncodedWithContext(ctx context.Context, uri string, params map[string]interface{}, response interface{}) (status int, err error) {
 	url := i.buildURL(uri)
-	return i.sendRequest("POST", url, "application/x-www-form-urlencoded", params, response)
+	return i.sendRequest(ctx, "POST", url, "application/x-www-form-urlencoded", params, response)
 }
 
 // PostMultipartFormData constructs and synchronously sends an API POST request using multipart/form-data as the content-type
 func (i *IdentityMindAPIClient) PostMultipartFormData(uri string, params map[string]interface{}, response interface{}) (status int, err error) {
+	return i.PostMultipartFormDataWithContext(context.Background(), uri, params, response)
+}
+
+// PostMultipartFormDataWithContext constructs and synchronously sends an API POST request bound to the given context using multipart/form-data as the content-type
+func (i *IdentityMindAPIClient) PostMultipartFormDataWithContext(ctx context.Context, uri string, params map[string]interface{}, response interface{}) (status int, err error) {
 	url := i.buildURL(uri)
-	return i.sendRequest("POST", url, "multipart/form-data", params, response)
+	return i.sendRequest(ctx, "POST", url, "multipart/form-data", params, response)
 }
 
 // Put constructs and synchronously sends an API PUT request
 func (i *IdentityMindAPIClient) Put(uri string, params map[string]interface{}, response interface{}) (status int, err error) {
+	return i.PutWithContext(context.Background(), uri, params, response)
+}
+
+// PutWithContext constructs and synchronously sends an API PUT request bound to the given context
+func (i *IdentityMindAPIClient) PutWithContext(ctx context.Context, uri string, params map[string]interface{}, response interface{}) (status int, err error) {
 	url := i.buildURL(uri)
-	return i.sendRequest("PUT", url, defaultContentType, params, response)
+	return i.sendRequest(ctx, "PUT", url, defaultContentType, params, response)
 }
 
 // Delete constructs and synchronously sends an API DELETE request
 func (i *IdentityMindAPIClient) Delete(uri string) (status int, err error) {
+	return i.DeleteWithContext(context.Background(), uri)
+}
+
+// DeleteWithContext constructs and synchronously sends an API DELETE request bound to the given context
+func (i *IdentityMindAPIClient) DeleteWithContext(ctx context.Context, uri string) (status int, err error) {
 	url := i.buildURL(uri)
-	return i.sendRequest("DELETE", url, defaultContentType, nil, nil)
+	return i.sendRequest(ctx, "DELETE", url, defaultContentType, nil, nil)
+}
+
+// buildHeaders returns the headers common to all requests, including authorization
+func (i *IdentityMindAPIClient) buildHeaders() http.Header {
+	headers := http.Header{
+		"Accept-Encoding": {"gzip, deflate"},
+		"Accept-Language": {"en-us"},
+		"Accept":          {"application/json"},
+	}
+	if i.Username != nil && i.Password != nil {
+		headers["Authorization"] = []string{buildBasicAuthorizationHeader(*i.Username, *i.Password)}
+	} else if i.Token != nil {
+		headers["Authorization"] = []string{fmt.Sprintf("Bearer %s", *i.Token)}
+	}
+	return headers
+}
+
+// logger returns the logger used on behalf of the API client
+func (i *IdentityMindAPIClient) logger() *logger.Logger {
+	if i.Logger != nil {
+		return i.Logger
+	}
+	return log
+}
+
+// httpClient returns the HTTP client used to send requests on behalf of the API client
+func (i *IdentityMindAPIClient) httpClient() *http.Client {
+	if i.HTTPClient != nil {
+		return i.HTTPClient
+	}
+	if i.Transport != nil {
+		return &http.Client{
+			Transport: i.Transport,
+		}
+	}
+	return defaultHTTPClient
 }
 
 func (i *IdentityMindAPIClient) buildURL(uri string) string {
@@ -218,6 +427,22 @@ func (i *IdentityMindAPIClient) buildURL(uri string) string {
 	return fmt.Sprintf("%s://%s%s/%s", i.Scheme, i.Host, path, uri)
 }
 
+func newDefaultTransport() *http.Transport {
+	return &http.Transport{
+		Proxy: http.ProxyFromEnvironment,
+		DialContext: (&net.Dialer{
+			Timeout:   30 * time.Second,
+			KeepAlive: 30 * time.Second,
+		}).DialContext,
+		ForceAttemptHTTP2:     true,
+		MaxIdleConns:          100,
+		MaxIdleConnsPerHost:   10,
+		IdleConnTimeout:       90 * time.Second,
+		TLSHandshakeTimeout:   10 * time.Second,
+		ExpectContinueTimeout: 1 * time.Second,
+	}
+}
+
 func buildBasicAuthorizationHeader(username, password string) string {
 	auth := fmt.Sprintf("%s:%s", username, password)
 	return fmt.Sprintf("Basic %s", base64.StdEncoding.EncodeToString([]byte(auth)))
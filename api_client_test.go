@@ -0,0 +1,159 @@
+package identitymind
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// newTestClient returns a client of a server which serves requests using the given handler; the
+// caller should close the returned server when finished
+func newTestClient(t *testing.T, handler http.HandlerFunc, opts ...Option) (*IdentityMindAPIClient, *httptest.Server) {
+	srv := httptest.NewServer(handler)
+	client, err := NewClient(append([]Option{WithBaseURL(srv.URL)}, opts...)...)
+	if err != nil {
+		srv.Close()
+		t.Fatalf("failed to initialize client; %s", err.Error())
+	}
+	return client, srv
+}
+
+func TestContextCancellation(t *testing.T) {
+	tests := []struct {
+		name     string
+		ctx      func() (context.Context, context.CancelFunc)
+		expected error
+		sent     bool
+	}{
+		{
+			name: "cancelled before the request is sent",
+			ctx: func() (context.Context, context.CancelFunc) {
+				ctx, cancel := context.WithCancel(context.Background())
+				cancel()
+				return ctx, cancel
+			},
+			expected: context.Canceled,
+		},
+		{
+			name: "cancelled while awaiting the response",
+			ctx: func() (context.Context, context.CancelFunc) {
+				ctx, cancel := context.WithCancel(context.Background())
+				time.AfterFunc(50*time.Millisecond, cancel)
+				return ctx, cancel
+			},
+			expected: context.Canceled,
+			sent:     true,
+		},
+		{
+			name: "deadline exceeded while awaiting the response",
+			ctx: func() (context.Context, context.CancelFunc) {
+				return context.WithTimeout(context.Background(), 50*time.Millisecond)
+			},
+			expected: context.DeadlineExceeded,
+			sent:     true,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var requests int32
+			release := make(chan struct{})
+			client, srv := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+				atomic.AddInt32(&requests, 1)
+				select {
+				case <-r.Context().Done():
+				case <-release:
+				}
+			})
+			defer srv.Close()
+			defer close(release)
+
+			ctx, cancel := test.ctx()
+			defer cancel()
+			_, err := client.GetApplicationWithContext(ctx, "app-1")
+			if !errors.Is(err, test.expected) {
+				t.Fatalf("expected %v; got %v", test.expected, err)
+			}
+			if sent := atomic.LoadInt32(&requests) > 0; sent != test.sent {
+				t.Errorf("expected request sent: %v; got %v", test.sent, sent)
+			}
+		})
+	}
+}
+
+// countingTransport counts the requests it forwards to the default transport
+type countingTransport struct {
+	requests int32
+}
+
+func (c *countingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	atomic.AddInt32(&c.requests, 1)
+	return http.DefaultTransport.RoundTrip(req)
+}
+
+func TestTransportInjection(t *testing.T) {
+	tests := []struct {
+		name        string
+		opts        func(transport, clientTransport *countingTransport) []Option
+		transport   int32
+		httpClient  int32
+		usesDefault bool
+	}{
+		{
+			name:        "default",
+			opts:        func(transport, clientTransport *countingTransport) []Option { return nil },
+			usesDefault: true,
+		},
+		{
+			name: "transport",
+			opts: func(transport, clientTransport *countingTransport) []Option {
+				return []Option{WithTransport(transport)}
+			},
+			transport: 1,
+		},
+		{
+			name: "http client takes precedence over transport",
+			opts: func(transport, clientTransport *countingTransport) []Option {
+				return []Option{WithTransport(transport), WithHTTPClient(&http.Client{Transport: clientTransport})}
+			},
+			httpClient: 1,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			transport := &countingTransport{}
+			clientTransport := &countingTransport{}
+			client, srv := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+				w.Write([]byte(`{"tid":"app-1"}`))
+			}, test.opts(transport, clientTransport)...)
+			defer srv.Close()
+
+			_, err := client.GetApplication("app-1")
+			if err != nil {
+				t.Fatal(err)
+			}
+			if transport.requests != test.transport || clientTransport.requests != test.httpClient {
+				t.Errorf("expected %d transport and %d http client requests; got %d and %d", test.transport, test.httpClient, transport.requests, clientTransport.requests)
+			}
+			if usesDefault := client.httpClient() == defaultHTTPClient; usesDefault != test.usesDefault {
+				t.Errorf("expected the shared default http client: %v; got %v", test.usesDefault, usesDefault)
+			}
+		})
+	}
+}
+
+func TestNewIdentityMindAPIClientDoesNotRetry(t *testing.T) {
+	client, err := NewIdentityMindAPIClient()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if client.RetryPolicy != nil {
+		t.Errorf("expected no retry policy; got %v", client.RetryPolicy)
+	}
+	if client.Timeout != defaultRequestTimeout {
+		t.Errorf("expected a %v timeout; got %v", defaultRequestTimeout, client.Timeout)
+	}
+}
@@ -1,43 +1,340 @@
 package identitymind
 
-import "fmt"
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+)
+
+// CaseStatus is the workflow status of a case. The published case reference does not enumerate case states;
+// the CaseStatus constants and the transitions between them are defined by this package, and statuses it
+// does not recognize are not validated
+type CaseStatus string
+
+// CaseStatusOpen is a case which has not yet been picked up
+const CaseStatusOpen CaseStatus = "open"
+
+// CaseStatusInProgress is a case which is being investigated
+const CaseStatusInProgress CaseStatus = "in_progress"
+
+// CaseStatusPendingInformation is a case awaiting further information, i.e. from the customer
+const CaseStatusPendingInformation CaseStatus = "pending_information"
+
+// CaseStatusEscalated is a case which was escalated, i.e. to the compliance officer
+const CaseStatusEscalated CaseStatus = "escalated"
+
+// CaseStatusClosed is a case which was resolved and closed
+const CaseStatusClosed CaseStatus = "closed"
+
+// caseTransitions enumerates the statuses to which a case may move from each status; closed cases
+// may only be reopened
+var caseTransitions = map[CaseStatus][]CaseStatus{
+	CaseStatusOpen:               {CaseStatusInProgress, CaseStatusEscalated, CaseStatusClosed},
+	CaseStatusInProgress:         {CaseStatusOpen, CaseStatusPendingInformation, CaseStatusEscalated, CaseStatusClosed},
+	CaseStatusPendingInformation: {CaseStatusInProgress, CaseStatusEscalated, CaseStatusClosed},
+	CaseStatusEscalated:          {CaseStatusInProgress, CaseStatusClosed},
+	CaseStatusClosed:             {CaseStatusOpen},
+}
+
+// CanTransitionTo returns true if a case may move from the status to the given status
+func (s CaseStatus) CanTransitionTo(to CaseStatus) bool {
+	for _, status := range caseTransitions[s] {
+		if status == to {
+			return true
+		}
+	}
+	return false
+}
+
+// IsClosed returns true if the status is CaseStatusClosed
+func (s CaseStatus) IsClosed() bool {
+	return s == CaseStatusClosed
+}
+
+// ValidateCaseTransition returns an error if a case may not move from the given status to the given status
+func ValidateCaseTransition(from, to CaseStatus) error {
+	if _, ok := caseTransitions[to]; !ok {
+		return fmt.Errorf("invalid case status: %s", to)
+	}
+	if !from.CanTransitionTo(to) {
+		return fmt.Errorf("case may not transition from %s to %s", from, to)
+	}
+	return nil
+}
+
+// CasePriority is the priority of a case; the CasePriority constants are defined by this package rather
+// than the published case reference
+type CasePriority string
+
+// CasePriorityLow is a low priority case
+const CasePriorityLow CasePriority = "low"
+
+// CasePriorityMedium is a medium priority case
+const CasePriorityMedium CasePriority = "medium"
+
+// CasePriorityHigh is a high priority case
+const CasePriorityHigh CasePriority = "high"
+
+// CasePriorityCritical is a critical priority case, i.e. a suspected sanctions match
+const CasePriorityCritical CasePriority = "critical"
+
+// Case represents a identitymind case; see https://edoc.identitymind.com/reference#createcase. The published
+// reference does not describe the case representation, so only the case identifier (caseId) is decoded into
+// a typed field; the complete response is retained in Raw
+type Case struct {
+	ID  *string                // identifier of the case (caseId)
+	Raw map[string]interface{} // decoded response, including keys not described by the published reference
+}
+
+// UnmarshalJSON implements json.Unmarshaler
+func (c *Case) UnmarshalJSON(data []byte) error {
+	var raw map[string]interface{}
+	err := json.Unmarshal(data, &raw)
+	if err != nil {
+		return err
+	}
+	c.ID = nil
+	c.Raw = raw
+	if id, ok := raw["caseId"].(string); ok && id != "" {
+		c.ID = &id
+	}
+	return nil
+}
+
+// MarshalJSON implements json.Marshaler; the case is encoded as the response from which it was decoded
+func (c *Case) MarshalJSON() ([]byte, error) {
+	raw := map[string]interface{}{}
+	for key, val := range c.Raw {
+		raw[key] = val
+	}
+	if c.ID != nil {
+		raw["caseId"] = *c.ID
+	}
+	return json.Marshal(raw)
+}
+
+// CaseRequest represents a request to create a case
+type CaseRequest struct {
+	Title          string       `json:"title"` // required
+	Description    string       `json:"description,omitempty"`
+	Priority       CasePriority `json:"priority,omitempty"`       // one of the CasePriority constants
+	Assignee       string       `json:"assignee,omitempty"`       // user to whom the case is assigned
+	ApplicationIDs []string     `json:"applicationIds,omitempty"` // KYC and KYB applications to link
+	TransactionIDs []string     `json:"tids,omitempty"`           // transactions to link
+	Note           string       `json:"note,omitempty"`           // initial note
+	NoteAuthor     string       `json:"noteAuthor,omitempty"`     // author of the initial note
+}
+
+// CaseUpdate represents changes to a case; empty fields are left unchanged
+type CaseUpdate struct {
+	Status         CaseStatus   `json:"state,omitempty"` // new status; use ResolveCase to close
+	CurrentStatus  CaseStatus   `json:"-"`               // status of the case as known to the caller; when provided, the new status must be reachable from it
+	Title          string       `json:"title,omitempty"`
+	Description    string       `json:"description,omitempty"`
+	Priority       CasePriority `json:"priority,omitempty"`
+	Assignee       string       `json:"assignee,omitempty"`
+	ApplicationIDs []string     `json:"applicationIds,omitempty"` // replaces the linked applications
+	TransactionIDs []string     `json:"tids,omitempty"`           // replaces the linked transactions
+	Note           string       `json:"note,omitempty"`           // note to record
+	NoteAuthor     string       `json:"noteAuthor,omitempty"`     // author of the note
+}
+
+// CaseClosure represents the resolution of a case
+type CaseClosure struct {
+	Resolution    string     `json:"resolution"`           // resolution of the case, i.e. no action required or SAR filed; required
+	Note          string     `json:"note,omitempty"`       // note to record
+	NoteAuthor    string     `json:"noteAuthor,omitempty"` // author of the note
+	CurrentStatus CaseStatus `json:"-"`                    // status of the case as known to the caller; when provided, the case must not already be closed
+}
+
+// Validate returns an error if the case request is missing required fields
+func (r *CaseRequest) Validate() error {
+	if r.Title == "" {
+		return fmt.Errorf("title is required")
+	}
+	return validateCasePriority(r.Priority)
+}
+
+// Validate returns an error if the case update is malformed, or if it changes the status of the case and the
+// new status is not reachable from the current status provided by the caller
+func (u *CaseUpdate) Validate() error {
+	if u.Status != "" {
+		if _, ok := caseTransitions[u.Status]; !ok {
+			return fmt.Errorf("invalid case status: %s", u.Status)
+		}
+		if u.Status.IsClosed() {
+			return fmt.Errorf("cases are closed using ResolveCase")
+		}
+		if err := validateCurrentCaseStatus(u.CurrentStatus, u.Status); err != nil {
+			return err
+		}
+	}
+	return validateCasePriority(u.Priority)
+}
+
+// Validate returns an error if the case closure is missing its resolution, or if the current status provided
+// by the caller is closed
+func (c *CaseClosure) Validate() error {
+	if c.Resolution == "" {
+		return fmt.Errorf("resolution is required")
+	}
+	return validateCurrentCaseStatus(c.CurrentStatus, CaseStatusClosed)
+}
+
+// validateCurrentCaseStatus validates the transition from the given current status, unless it is empty or
+// not recognized
+func validateCurrentCaseStatus(from, to CaseStatus) error {
+	if _, ok := caseTransitions[from]; !ok {
+		return nil
+	}
+	return ValidateCaseTransition(from, to)
+}
+
+func validateCasePriority(priority CasePriority) error {
+	switch priority {
+	case "", CasePriorityLow, CasePriorityMedium, CasePriorityHigh, CasePriorityCritical:
+		return nil
+	}
+	return fmt.Errorf("invalid case priority: %s", priority)
+}
 
 // GetCase see https://edoc.identitymind.com/reference#update
-func (i *IdentityMindAPIClient) GetCase(caseID string) (interface{}, error) {
-	var resp map[string]interface{}
-	status, err := i.Get(fmt.Sprintf("im/admin/jax/case/%s", caseID), map[string]interface{}{}, &resp)
+func (i *IdentityMindAPIClient) GetCase(caseID string) (*Case, error) {
+	return i.GetCaseWithContext(context.Background(), caseID)
+}
+
+// GetCaseWithContext see https://edoc.identitymind.com/reference#update
+func (i *IdentityMindAPIClient) GetCaseWithContext(ctx context.Context, caseID string) (*Case, error) {
+	if caseID == "" {
+		return nil, fmt.Errorf("Failed to retrieve case; case id is required")
+	}
+	var resp *Case
+	status, err := i.GetWithContext(ctx, fmt.Sprintf("im/admin/jax/case/%s", caseID), map[string]interface{}{}, &resp)
 	if err != nil {
-		return nil, fmt.Errorf("Failed to create case via identitymind API; status: %d; %s", status, err.Error())
+		return nil, fmt.Errorf("Failed to retrieve case via identitymind API; status: %d; %w", status, err)
 	}
 	return resp, nil
 }
 
 // CreateCase see https://edoc.identitymind.com/reference#createcase
-func (i *IdentityMindAPIClient) CreateCase(params map[string]interface{}) (interface{}, error) {
-	var resp map[string]interface{}
-	status, err := i.Post("im/admin/jax/case", params, &resp)
+func (i *IdentityMindAPIClient) CreateCase(params map[string]interface{}) (*Case, error) {
+	return i.CreateCaseWithContext(context.Background(), params)
+}
+
+// CreateCaseWithContext see https://edoc.identitymind.com/reference#createcase
+func (i *IdentityMindAPIClient) CreateCaseWithContext(ctx context.Context, params map[string]interface{}) (*Case, error) {
+	var resp *Case
+	status, err := i.PostWithContext(ctx, "im/admin/jax/case", params, &resp)
 	if err != nil {
-		return nil, fmt.Errorf("Failed to create case via identitymind API; status: %d; %s", status, err.Error())
+		return nil, fmt.Errorf("Failed to create case via identitymind API; status: %d; %w", status, err)
 	}
 	return resp, nil
 }
 
+// OpenCase creates a case from the given typed request; see https://edoc.identitymind.com/reference#createcase
+func (i *IdentityMindAPIClient) OpenCase(request *CaseRequest) (*Case, error) {
+	return i.OpenCaseWithContext(context.Background(), request)
+}
+
+// OpenCaseWithContext creates a case from the given typed request; see https://edoc.identitymind.com/reference#createcase
+func (i *IdentityMindAPIClient) OpenCaseWithContext(ctx context.Context, request *CaseRequest) (*Case, error) {
+	if request == nil {
+		return nil, fmt.Errorf("Failed to create case; request is required")
+	}
+	if err := request.Validate(); err != nil {
+		return nil, fmt.Errorf("Failed to create case; %s", err.Error())
+	}
+	params, err := marshalParams(request)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to marshal case request; %s", err.Error())
+	}
+	return i.CreateCaseWithContext(ctx, params)
+}
+
 // CloseCase see https://edoc.identitymind.com/reference#closecase
-func (i *IdentityMindAPIClient) CloseCase(caseID string, params map[string]interface{}) (interface{}, error) {
-	var resp map[string]interface{}
-	status, err := i.Post("im/admin/jax/case/close", params, &resp)
+func (i *IdentityMindAPIClient) CloseCase(caseID string, params map[string]interface{}) (*Case, error) {
+	return i.CloseCaseWithContext(context.Background(), caseID, params)
+}
+
+// CloseCaseWithContext see https://edoc.identitymind.com/reference#closecase
+func (i *IdentityMindAPIClient) CloseCaseWithContext(ctx context.Context, caseID string, params map[string]interface{}) (*Case, error) {
+	if caseID == "" {
+		return nil, fmt.Errorf("Failed to close case; case id is required")
+	}
+	closeParams := map[string]interface{}{}
+	for key, val := range params {
+		closeParams[key] = val
+	}
+	closeParams["caseId"] = caseID
+
+	var resp *Case
+	status, err := i.PostWithContext(ctx, "im/admin/jax/case/close", closeParams, &resp)
 	if err != nil {
-		return nil, fmt.Errorf("Failed to close case via identitymind API; status: %d; %s", status, err.Error())
+		return nil, fmt.Errorf("Failed to close case via identitymind API; status: %d; %w", status, err)
 	}
 	return resp, nil
 }
 
+// ResolveCase closes the given case, recording its resolution; an error is returned without closing the case
+// if the closure records that it is already closed; see https://edoc.identitymind.com/reference#closecase
+func (i *IdentityMindAPIClient) ResolveCase(caseID string, closure *CaseClosure) (*Case, error) {
+	return i.ResolveCaseWithContext(context.Background(), caseID, closure)
+}
+
+// ResolveCaseWithContext closes the given case, recording its resolution; an error is returned without closing
+// the case if the closure records that it is already closed; see https://edoc.identitymind.com/reference#closecase
+func (i *IdentityMindAPIClient) ResolveCaseWithContext(ctx context.Context, caseID string, closure *CaseClosure) (*Case, error) {
+	if closure == nil {
+		return nil, fmt.Errorf("Failed to close case %s; closure is required", caseID)
+	}
+	if err := closure.Validate(); err != nil {
+		return nil, fmt.Errorf("Failed to close case %s; %s", caseID, err.Error())
+	}
+	params, err := marshalParams(closure)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to marshal case closure; %s", err.Error())
+	}
+	return i.CloseCaseWithContext(ctx, caseID, params)
+}
+
 // UpdateCase see https://edoc.identitymind.com/reference#updatecasecontent
-func (i *IdentityMindAPIClient) UpdateCase(caseID string, params map[string]interface{}) (interface{}, error) {
-	var resp map[string]interface{}
-	status, err := i.Post(fmt.Sprintf("im/admin/jax/case/%s", caseID), params, &resp)
+func (i *IdentityMindAPIClient) UpdateCase(caseID string, params map[string]interface{}) (*Case, error) {
+	return i.UpdateCaseWithContext(context.Background(), caseID, params)
+}
+
+// UpdateCaseWithContext see https://edoc.identitymind.com/reference#updatecasecontent
+func (i *IdentityMindAPIClient) UpdateCaseWithContext(ctx context.Context, caseID string, params map[string]interface{}) (*Case, error) {
+	if caseID == "" {
+		return nil, fmt.Errorf("Failed to update case; case id is required")
+	}
+	var resp *Case
+	status, err := i.PostWithContext(ctx, fmt.Sprintf("im/admin/jax/case/%s", caseID), params, &resp)
 	if err != nil {
-		return nil, fmt.Errorf("Failed to update case via identitymind API; status: %d; %s", status, err.Error())
+		return nil, fmt.Errorf("Failed to update case via identitymind API; status: %d; %w", status, err)
 	}
 	return resp, nil
 }
+
+// EditCase applies the given typed update to a case; when the update changes the status from the current
+// status provided by the caller, an error is returned without updating the case if the transition is not
+// permitted; see https://edoc.identitymind.com/reference#updatecasecontent
+func (i *IdentityMindAPIClient) EditCase(caseID string, update *CaseUpdate) (*Case, error) {
+	return i.EditCaseWithContext(context.Background(), caseID, update)
+}
+
+// EditCaseWithContext applies the given typed update to a case; when the update changes the status from the current
+// status provided by the caller, an error is returned without updating the case if the transition is not
+// permitted; see https://edoc.identitymind.com/reference#updatecasecontent
+func (i *IdentityMindAPIClient) EditCaseWithContext(ctx context.Context, caseID string, update *CaseUpdate) (*Case, error) {
+	if update == nil {
+		return nil, fmt.Errorf("Failed to update case %s; update is required", caseID)
+	}
+	if err := update.Validate(); err != nil {
+		return nil, fmt.Errorf("Failed to update case %s; %s", caseID, err.Error())
+	}
+	params, err := marshalParams(update)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to marshal case update; %s", err.Error())
+	}
+	return i.UpdateCaseWithContext(ctx, caseID, params)
+}
@@ -0,0 +1,172 @@
+package identitymind
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestGetCaseRetainsResponse(t *testing.T) {
+	body := `{"caseId":"case-1","state":"OPEN","owner":{"name":"analyst"},"appIds":["app-1"]}`
+	client, srv := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/im/admin/jax/case/case-1" {
+			t.Errorf("unexpected path %s", r.URL.Path)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(body))
+	})
+	defer srv.Close()
+
+	c, err := client.GetCase("case-1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if c.ID == nil || *c.ID != "case-1" {
+		t.Fatalf("expected the case id to be decoded; got %v", c.ID)
+	}
+	if owner, _ := c.Raw["owner"].(map[string]interface{}); owner["name"] != "analyst" {
+		t.Fatalf("expected the undescribed keys to be retained; got %v", c.Raw)
+	}
+
+	raw, err := json.Marshal(c)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var expected, actual interface{}
+	json.Unmarshal([]byte(body), &expected)
+	json.Unmarshal(raw, &actual)
+	if !reflect.DeepEqual(expected, actual) {
+		t.Fatalf("expected the case to be encoded as the response; got %s", raw)
+	}
+}
+
+func TestEditCase(t *testing.T) {
+	tests := []struct {
+		name   string
+		update *CaseUpdate
+		sent   bool
+	}{
+		{"status without current status", &CaseUpdate{Status: CaseStatusInProgress}, true},
+		{"permitted transition", &CaseUpdate{Status: CaseStatusInProgress, CurrentStatus: CaseStatusOpen}, true},
+		{"unrecognized current status", &CaseUpdate{Status: CaseStatusInProgress, CurrentStatus: "OPEN"}, true},
+		{"transition not permitted", &CaseUpdate{Status: CaseStatusPendingInformation, CurrentStatus: CaseStatusClosed}, false},
+		{"closing", &CaseUpdate{Status: CaseStatusClosed}, false},
+		{"invalid priority", &CaseUpdate{Priority: "urgent"}, false},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var requests []string
+			client, srv := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+				requests = append(requests, r.Method)
+				var params map[string]interface{}
+				json.NewDecoder(r.Body).Decode(&params)
+				if _, ok := params["CurrentStatus"]; ok {
+					t.Errorf("expected the current status not to be sent; got %v", params)
+				}
+				w.Header().Set("Content-Type", "application/json")
+				w.Write([]byte(`{"caseId":"case-1"}`))
+			})
+			defer srv.Close()
+
+			_, err := client.EditCase("case-1", test.update)
+			if test.sent {
+				if err != nil {
+					t.Fatal(err)
+				}
+				if len(requests) != 1 || requests[0] != http.MethodPost {
+					t.Fatalf("expected the case to be updated without being retrieved; got %v", requests)
+				}
+				return
+			}
+			if err == nil {
+				t.Fatal("expected the update to be rejected")
+			}
+			if len(requests) != 0 {
+				t.Fatalf("expected no request to be sent; got %v", requests)
+			}
+		})
+	}
+}
+
+func TestResolveCase(t *testing.T) {
+	tests := []struct {
+		name    string
+		closure *CaseClosure
+		sent    bool
+	}{
+		{"resolution", &CaseClosure{Resolution: "no action required"}, true},
+		{"open case", &CaseClosure{Resolution: "no action required", CurrentStatus: CaseStatusEscalated}, true},
+		{"closed case", &CaseClosure{Resolution: "no action required", CurrentStatus: CaseStatusClosed}, false},
+		{"missing resolution", &CaseClosure{}, false},
+		{"missing closure", nil, false},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var requests []string
+			client, srv := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+				requests = append(requests, r.Method+" "+r.URL.Path)
+				w.Header().Set("Content-Type", "application/json")
+				w.Write([]byte(`{"caseId":"case-1"}`))
+			})
+			defer srv.Close()
+
+			_, err := client.ResolveCase("case-1", test.closure)
+			if test.sent {
+				if err != nil {
+					t.Fatal(err)
+				}
+				if len(requests) != 1 || requests[0] != "POST /im/admin/jax/case/close" {
+					t.Fatalf("expected the case to be closed without being retrieved; got %v", requests)
+				}
+				return
+			}
+			if err == nil {
+				t.Fatal("expected the closure to be rejected")
+			}
+			if len(requests) != 0 {
+				t.Fatalf("expected no request to be sent; got %v", requests)
+			}
+		})
+	}
+}
+
+func TestCaseRequiresID(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Errorf("unexpected %s %s", r.Method, r.URL.Path)
+	}))
+	defer srv.Close()
+
+	client, err := NewClient(WithBaseURL(srv.URL))
+	if err != nil {
+		t.Fatal(err)
+	}
+	calls := map[string]func() error{
+		"GetCase": func() error {
+			_, err := client.GetCase("")
+			return err
+		},
+		"UpdateCase": func() error {
+			_, err := client.UpdateCase("", map[string]interface{}{"title": "x"})
+			return err
+		},
+		"EditCase": func() error {
+			_, err := client.EditCase("", &CaseUpdate{Title: "x"})
+			return err
+		},
+		"ResolveCase": func() error {
+			_, err := client.ResolveCase("", &CaseClosure{Resolution: "no action required"})
+			return err
+		},
+	}
+	for name, call := range calls {
+		err := call()
+		if err == nil || !strings.Contains(err.Error(), "case id is required") {
+			t.Errorf("%s: expected a missing case id to be rejected; got %v", name, err)
+		}
+	}
+}
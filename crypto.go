@@ -0,0 +1,353 @@
+package identitymind
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"math/big"
+	"strings"
+
+	"golang.org/x/crypto/sha3"
+)
+
+// Blockchain identifies the blockchain on which a digital currency transfer occurred
+type Blockchain string
+
+// BlockchainBitcoin is the bitcoin blockchain
+const BlockchainBitcoin Blockchain = "bitcoin"
+
+// BlockchainBitcoinTestnet is the bitcoin test network
+const BlockchainBitcoinTestnet Blockchain = "bitcoin-testnet"
+
+// BlockchainLitecoin is the litecoin blockchain
+const BlockchainLitecoin Blockchain = "litecoin"
+
+// BlockchainLitecoinTestnet is the litecoin test network
+const BlockchainLitecoinTestnet Blockchain = "litecoin-testnet"
+
+// BlockchainEthereum is the ethereum blockchain
+const BlockchainEthereum Blockchain = "ethereum"
+
+// BlockchainPolygon is the polygon (formerly matic) blockchain
+const BlockchainPolygon Blockchain = "polygon"
+
+// BlockchainBinanceSmartChain is the BNB smart chain
+const BlockchainBinanceSmartChain Blockchain = "bsc"
+
+// BlockchainTron is the tron blockchain
+const BlockchainTron Blockchain = "tron"
+
+// BlockchainSolana is the solana blockchain
+const BlockchainSolana Blockchain = "solana"
+
+// DigitalCurrencyTransfer holds the blockchain details of a digital currency deposit or withdrawal; the deposit
+// and withdrawal references (https://edoc.identitymind.com/reference#transferin and
+// https://edoc.identitymind.com/reference#transferout) document no fields for them, so they are validated
+// before the transaction is reported but are not submitted to identitymind
+type DigitalCurrencyTransfer struct {
+	Asset              string     `json:"-"` // asset symbol, i.e. BTC, ETH or USDT; required
+	Chain              Blockchain `json:"-"` // blockchain on which the transfer occurred; required unless implied by the asset
+	SourceAddress      string     `json:"-"` // address from which the asset was sent
+	DestinationAddress string     `json:"-"` // address to which the asset was sent
+	TxHash             string     `json:"-"` // hash of the blockchain transaction
+	Confirmations      *int       `json:"-"` // number of confirmations observed at the time of reporting
+}
+
+// DefaultBlockchain returns the blockchain native to the given asset symbol, i.e. BlockchainBitcoin for BTC,
+// or an empty blockchain for assets, such as stablecoins, which are issued on several blockchains
+func DefaultBlockchain(asset string) Blockchain {
+	switch strings.ToUpper(asset) {
+	case "BTC":
+		return BlockchainBitcoin
+	case "LTC":
+		return BlockchainLitecoin
+	case "ETH":
+		return BlockchainEthereum
+	case "MATIC", "POL":
+		return BlockchainPolygon
+	case "BNB":
+		return BlockchainBinanceSmartChain
+	case "TRX":
+		return BlockchainTron
+	case "SOL":
+		return BlockchainSolana
+	}
+	return Blockchain("")
+}
+
+// Blockchain returns the blockchain on which the transfer occurred, falling back to the blockchain native to the asset
+func (d *DigitalCurrencyTransfer) Blockchain() Blockchain {
+	if d.Chain != "" {
+		return d.Chain
+	}
+	return DefaultBlockchain(d.Asset)
+}
+
+// Validate returns an error if the transfer is missing required fields, or if an address or tx hash is malformed
+// for the blockchain; addresses and hashes on blockchains other than the Blockchain constants are not validated
+func (d *DigitalCurrencyTransfer) Validate() error {
+	if d.Asset == "" {
+		return fmt.Errorf("asset is required")
+	}
+	chain := d.Blockchain()
+	if chain == "" {
+		return fmt.Errorf("chain is required for asset %s", d.Asset)
+	}
+	if d.SourceAddress == "" && d.DestinationAddress == "" {
+		return fmt.Errorf("source or destination address is required")
+	}
+	if d.SourceAddress != "" {
+		if err := ValidateAddress(chain, d.SourceAddress); err != nil {
+			return fmt.Errorf("invalid source address; %s", err.Error())
+		}
+	}
+	if d.DestinationAddress != "" {
+		if err := ValidateAddress(chain, d.DestinationAddress); err != nil {
+			return fmt.Errorf("invalid destination address; %s", err.Error())
+		}
+	}
+	if d.TxHash != "" {
+		if err := ValidateTxHash(chain, d.TxHash); err != nil {
+			return err
+		}
+	}
+	if d.Confirmations != nil && *d.Confirmations < 0 {
+		return fmt.Errorf("confirmations must not be negative")
+	}
+	return nil
+}
+
+// ValidateAddress returns an error if the given address is malformed for the given blockchain; checksums are
+// verified where the address format includes one. Addresses on unrecognized blockchains are not validated
+func ValidateAddress(chain Blockchain, address string) error {
+	switch chain {
+	case BlockchainBitcoin:
+		return validateUTXOAddress(address, []byte{0x00, 0x05}, "bc")
+	case BlockchainBitcoinTestnet:
+		return validateUTXOAddress(address, []byte{0x6f, 0xc4}, "tb")
+	case BlockchainLitecoin:
+		return validateUTXOAddress(address, []byte{0x30, 0x32, 0x05}, "ltc")
+	case BlockchainLitecoinTestnet:
+		return validateUTXOAddress(address, []byte{0x6f, 0x3a, 0xc4}, "tltc")
+	case BlockchainEthereum, BlockchainPolygon, BlockchainBinanceSmartChain:
+		return validateEVMAddress(address)
+	case BlockchainTron:
+		payload, err := decodeBase58Check(address)
+		if err != nil || len(payload) != 21 || payload[0] != 0x41 {
+			return fmt.Errorf("%s is not a valid %s address", address, chain)
+		}
+		return nil
+	case BlockchainSolana:
+		decoded, err := decodeBase58(address)
+		if err != nil || len(decoded) != 32 {
+			return fmt.Errorf("%s is not a valid %s address", address, chain)
+		}
+		return nil
+	}
+	return nil
+}
+
+// ValidateTxHash returns an error if the given transaction hash is malformed for the given blockchain;
+// hashes on unrecognized blockchains are not validated
+func ValidateTxHash(chain Blockchain, txHash string) error {
+	valid := true
+	switch chain {
+	case BlockchainBitcoin, BlockchainBitcoinTestnet, BlockchainLitecoin, BlockchainLitecoinTestnet, BlockchainTron:
+		valid = isHex(txHash, 64)
+	case BlockchainEthereum, BlockchainPolygon, BlockchainBinanceSmartChain:
+		valid = strings.HasPrefix(txHash, "0x") && isHex(txHash[2:], 64)
+	case BlockchainSolana:
+		decoded, err := decodeBase58(txHash)
+		valid = err == nil && len(decoded) == 64
+	}
+	if !valid {
+		return fmt.Errorf("%s is not a valid %s tx hash", txHash, chain)
+	}
+	return nil
+}
+
+// validateUTXOAddress validates a base58check address having one of the given version bytes, or a segwit
+// address having the given human-readable part
+func validateUTXOAddress(address string, versions []byte, hrp string) error {
+	if strings.HasPrefix(strings.ToLower(address), hrp+"1") {
+		if err := validateSegwitAddress(address, hrp); err != nil {
+			return fmt.Errorf("%s is not a valid segwit address; %s", address, err.Error())
+		}
+		return nil
+	}
+
+	payload, err := decodeBase58Check(address)
+	if err != nil {
+		return fmt.Errorf("%s is not a valid address; %s", address, err.Error())
+	}
+	if len(payload) != 21 || bytes.IndexByte(versions, payload[0]) == -1 {
+		return fmt.Errorf("%s is not a valid address; unexpected version or length", address)
+	}
+	return nil
+}
+
+// validateEVMAddress validates a hex address, verifying the EIP-55 checksum when the address is mixed-case
+func validateEVMAddress(address string) error {
+	if !strings.HasPrefix(address, "0x") || !isHex(address[2:], 40) {
+		return fmt.Errorf("%s is not a valid address; expected 0x followed by 40 hex characters", address)
+	}
+	hexAddress := address[2:]
+	if hexAddress == strings.ToLower(hexAddress) || hexAddress == strings.ToUpper(hexAddress) {
+		return nil
+	}
+
+	keccak := sha3.NewLegacyKeccak256()
+	keccak.Write([]byte(strings.ToLower(hexAddress)))
+	hash := keccak.Sum(nil)
+	for i, c := range hexAddress {
+		if c >= '0' && c <= '9' {
+			continue
+		}
+		nibble := hash[i/2]
+		if i%2 == 0 {
+			nibble >>= 4
+		}
+		upper := c >= 'A' && c <= 'F'
+		if (nibble&0x0f >= 8) != upper {
+			return fmt.Errorf("%s is not a valid address; EIP-55 checksum mismatch", address)
+		}
+	}
+	return nil
+}
+
+const base58Alphabet = "123456789ABCDEFGHJKLMNPQRSTUVWXYZabcdefghijkmnopqrstuvwxyz"
+
+func decodeBase58(str string) ([]byte, error) {
+	if str == "" {
+		return nil, fmt.Errorf("empty base58 string")
+	}
+	value := new(big.Int)
+	radix := big.NewInt(58)
+	for _, c := range str {
+		idx := strings.IndexRune(base58Alphabet, c)
+		if idx == -1 {
+			return nil, fmt.Errorf("invalid base58 character %q", c)
+		}
+		value.Mul(value, radix)
+		value.Add(value, big.NewInt(int64(idx)))
+	}
+
+	leadingZeros := 0
+	for leadingZeros < len(str) && str[leadingZeros] == base58Alphabet[0] {
+		leadingZeros++
+	}
+	return append(make([]byte, leadingZeros), value.Bytes()...), nil
+}
+
+// decodeBase58Check decodes the given base58check string, verifying and stripping its checksum
+func decodeBase58Check(str string) ([]byte, error) {
+	decoded, err := decodeBase58(str)
+	if err != nil {
+		return nil, err
+	}
+	if len(decoded) < 5 {
+		return nil, fmt.Errorf("base58check string too short")
+	}
+	payload := decoded[:len(decoded)-4]
+	first := sha256.Sum256(payload)
+	second := sha256.Sum256(first[:])
+	if !bytes.Equal(second[:4], decoded[len(decoded)-4:]) {
+		return nil, fmt.Errorf("base58check checksum mismatch")
+	}
+	return payload, nil
+}
+
+const bech32Charset = "qpzry9x8gf2tvdw0s3jn54khce6mua7l"
+
+// validateSegwitAddress validates the given bech32 (witness version 0) or bech32m (witness version 1 and
+// later) address having the given human-readable part; see BIP-173 and BIP-350
+func validateSegwitAddress(address, hrp string) error {
+	if len(address) > 90 || (address != strings.ToLower(address) && address != strings.ToUpper(address)) {
+		return fmt.Errorf("invalid length or mixed case")
+	}
+	address = strings.ToLower(address)
+	separator := strings.LastIndexByte(address, '1')
+	if address[:separator] != hrp || len(address)-separator-1 < 7 {
+		return fmt.Errorf("invalid human-readable part or data length")
+	}
+
+	data := make([]byte, 0, len(address)-separator-1)
+	for _, c := range address[separator+1:] {
+		idx := strings.IndexRune(bech32Charset, c)
+		if idx == -1 {
+			return fmt.Errorf("invalid bech32 character %q", c)
+		}
+		data = append(data, byte(idx))
+	}
+
+	values := make([]byte, 0, len(hrp)*2+1+len(data))
+	for i := 0; i < len(hrp); i++ {
+		values = append(values, hrp[i]>>5)
+	}
+	values = append(values, 0)
+	for i := 0; i < len(hrp); i++ {
+		values = append(values, hrp[i]&31)
+	}
+	values = append(values, data...)
+	checksum := bech32Polymod(values)
+
+	version := data[0]
+	if version > 16 {
+		return fmt.Errorf("invalid witness version %d", version)
+	}
+	if (version == 0 && checksum != 1) || (version != 0 && checksum != 0x2bc830a3) {
+		return fmt.Errorf("bech32 checksum mismatch")
+	}
+
+	program, err := convertBits(data[1:len(data)-6], 5, 8)
+	if err != nil {
+		return err
+	}
+	if len(program) < 2 || len(program) > 40 || (version == 0 && len(program) != 20 && len(program) != 32) {
+		return fmt.Errorf("invalid witness program length %d", len(program))
+	}
+	return nil
+}
+
+func bech32Polymod(values []byte) uint32 {
+	generator := [5]uint32{0x3b6a57b2, 0x26508e6d, 0x1ea119fa, 0x3d4233dd, 0x2a1462b3}
+	chk := uint32(1)
+	for _, v := range values {
+		top := chk >> 25
+		chk = (chk&0x1ffffff)<<5 ^ uint32(v)
+		for i := 0; i < 5; i++ {
+			if (top>>uint(i))&1 == 1 {
+				chk ^= generator[i]
+			}
+		}
+	}
+	return chk
+}
+
+// convertBits regroups the given values from groups of from bits to groups of to bits without padding
+func convertBits(data []byte, from, to uint) ([]byte, error) {
+	acc := uint32(0)
+	count := uint(0)
+	out := make([]byte, 0, len(data)*int(from)/int(to))
+	for _, v := range data {
+		acc = acc<<from | uint32(v)
+		count += from
+		for count >= to {
+			count -= to
+			out = append(out, byte(acc>>count&(1<<to-1)))
+		}
+	}
+	if count >= from || (acc<<(to-count))&(1<<to-1) != 0 {
+		return nil, fmt.Errorf("invalid witness program padding")
+	}
+	return out, nil
+}
+
+func isHex(str string, length int) bool {
+	if len(str) != length {
+		return false
+	}
+	_, err := hex.DecodeString(str)
+	return err == nil
+}
@@ -0,0 +1,95 @@
+package identitymind
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestValidateAddress(t *testing.T) {
+	tests := []struct {
+		name    string
+		chain   Blockchain
+		address string
+		valid   bool
+	}{
+		// BIP-173
+		{"bech32 p2wpkh", BlockchainBitcoin, "BC1QW508D6QEJXTDG4Y5R3ZARVARY0C5XW7KV8F3T4", true},
+		{"bech32 testnet p2wsh", BlockchainBitcoinTestnet, "tb1qrp33g0q5c5txsp9arysrx4k6zdkfs4nce4xj0gdcccefvpysxf3q0sl5k7", true},
+		{"bech32 testnet p2wsh with leading zeros", BlockchainBitcoinTestnet, "tb1qqqqqp399et2xygdj5xreqhjjvcmzhxw4aywxecjdzew6hylgvsesrxh6hy", true},
+		{"bech32 mixed case", BlockchainBitcoinTestnet, "tb1qrp33g0q5c5txsp9arysrx4k6zdkfs4nce4xj0gdcccefvpysxf3q0sL5k7", false},
+		{"bech32 invalid v0 program length", BlockchainBitcoin, "BC1QR508D6QEJXTDG4Y5R3ZARVARYV98GJ9P", false},
+		{"bech32 empty data", BlockchainBitcoin, "bc1gmk9yu", false},
+		// BIP-350
+		{"bech32m v1", BlockchainBitcoin, "bc1pw508d6qejxtdg4y5r3zarvary0c5xw7kw508d6qejxtdg4y5r3zarvary0c5xw7kt5nd6y", true},
+		{"bech32m v16", BlockchainBitcoin, "BC1SW50QGDZ25J", true},
+		{"bech32m v2", BlockchainBitcoin, "bc1zw508d6qejxtdg4y5r3zarvaryvaxxpcs", true},
+		{"bech32m taproot", BlockchainBitcoin, "bc1p0xlxvlhemja6c4dqv22uapctqupfhlxm9h8z3k2e72q4k9hcz7vqzk5jj0", true},
+		{"bech32m testnet taproot", BlockchainBitcoinTestnet, "tb1pqqqqp399et2xygdj5xreqhjjvcmzhxw4aywxecjdzew6hylgvsesf3hn0c", true},
+		{"v1 with bech32 checksum", BlockchainBitcoin, "bc1p0xlxvlhemja6c4dqv22uapctqupfhlxm9h8z3k2e72q4k9hcz7vqh2y7hd", false},
+		{"v16 with bech32 checksum", BlockchainBitcoin, "BC1S0XLXVLHEMJA6C4DQV22UAPCTQUPFHLXM9H8Z3K2E72Q4K9HCZ7VQ54WELL", false},
+		{"v0 with bech32m checksum", BlockchainBitcoin, "bc1qw508d6qejxtdg4y5r3zarvary0c5xw7kemeawh", false},
+		{"testnet v0 with bech32m checksum", BlockchainBitcoinTestnet, "tb1q0xlxvlhemja6c4dqv22uapctqupfhlxm9h8z3k2e72q4k9hcz7vq24jc47", false},
+		// base58check
+		{"p2pkh", BlockchainBitcoin, "1A1zP1eP5QGefi2DMPTfTL5SLmv7DivfNa", true},
+		{"p2sh", BlockchainBitcoin, "3J98t1WpEZ73CNmQviecrnyiWrnqRhWNLy", true},
+		{"p2pkh checksum mismatch", BlockchainBitcoin, "1A1zP1eP5QGefi2DMPTfTL5SLmv7DivfNb", false},
+		{"testnet p2pkh", BlockchainBitcoinTestnet, "mipcBbFg9gMiCh81Kj8tqqdgoZub1ZJRfn", true},
+		// networks
+		{"testnet p2pkh on mainnet", BlockchainBitcoin, "mipcBbFg9gMiCh81Kj8tqqdgoZub1ZJRfn", false},
+		{"testnet segwit on mainnet", BlockchainBitcoin, "tb1qrp33g0q5c5txsp9arysrx4k6zdkfs4nce4xj0gdcccefvpysxf3q0sl5k7", false},
+		{"mainnet p2pkh on testnet", BlockchainBitcoinTestnet, "1A1zP1eP5QGefi2DMPTfTL5SLmv7DivfNa", false},
+		{"mainnet segwit on testnet", BlockchainBitcoinTestnet, "BC1QW508D6QEJXTDG4Y5R3ZARVARY0C5XW7KV8F3T4", false},
+		// EIP-55
+		{"eip-55 checksum", BlockchainEthereum, "0x5aAeb6053F3E94C9b9A09f33669435E7Ef1BeAed", true},
+		{"eip-55 checksum", BlockchainEthereum, "0xfB6916095ca1df60bB79Ce92cE3Ea74c37c5d359", true},
+		{"eip-55 checksum", BlockchainPolygon, "0xdbF03B407c01E7cD3CBea99509d93f8DDDC8C6FB", true},
+		{"eip-55 checksum", BlockchainBinanceSmartChain, "0xD1220A0cf47c7B9Be7A2E6BA89F429762e7b9aDb", true},
+		{"eip-55 checksum mismatch", BlockchainEthereum, "0x5aAeb6053F3E94C9b9A09f33669435E7Ef1BeAeD", false},
+		{"lowercase", BlockchainEthereum, "0x5aaeb6053f3e94c9b9a09f33669435e7ef1beaed", true},
+		{"missing prefix", BlockchainEthereum, "5aAeb6053F3E94C9b9A09f33669435E7Ef1BeAed", false},
+		{"short", BlockchainEthereum, "0x5aAeb6053F3E94C9b9A09f33669435E7Ef1BeA", false},
+		// unrecognized blockchains are not validated
+		{"unrecognized", Blockchain("dogecoin"), "not an address", true},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			err := ValidateAddress(test.chain, test.address)
+			if test.valid && err != nil {
+				t.Fatalf("expected %s to be a valid %s address; %s", test.address, test.chain, err.Error())
+			}
+			if !test.valid && err == nil {
+				t.Fatalf("expected %s not to be a valid %s address", test.address, test.chain)
+			}
+		})
+	}
+}
+
+func TestValidateTxHash(t *testing.T) {
+	hash := strings.Repeat("ab", 32)
+	tests := []struct {
+		name   string
+		chain  Blockchain
+		txHash string
+		valid  bool
+	}{
+		{"bitcoin", BlockchainBitcoin, hash, true},
+		{"bitcoin testnet", BlockchainBitcoinTestnet, hash, true},
+		{"bitcoin with prefix", BlockchainBitcoin, "0x" + hash, false},
+		{"bitcoin short", BlockchainBitcoin, hash[2:], false},
+		{"ethereum", BlockchainEthereum, "0x" + hash, true},
+		{"ethereum without prefix", BlockchainEthereum, hash, false},
+		{"ethereum non-hex", BlockchainEthereum, "0x" + strings.Repeat("zz", 32), false},
+		{"unrecognized", Blockchain("dogecoin"), "not a hash", true},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			err := ValidateTxHash(test.chain, test.txHash)
+			if test.valid && err != nil {
+				t.Fatalf("expected %s to be a valid %s tx hash; %s", test.txHash, test.chain, err.Error())
+			}
+			if !test.valid && err == nil {
+				t.Fatalf("expected %s not to be a valid %s tx hash", test.txHash, test.chain)
+			}
+		})
+	}
+}
@@ -0,0 +1,263 @@
+package identitymind
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"crypto/md5"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"hash"
+	"io"
+	"io/ioutil"
+	"mime"
+	"mime/multipart"
+	"net/http"
+	"net/textproto"
+	"net/url"
+	"strings"
+	"time"
+)
+
+const defaultDocumentContentType = "application/octet-stream"
+const defaultDocumentFilename = "document"
+
+var quoteEscaper = strings.NewReplacer("\\", "\\\\", `"`, "\\\"")
+
+// DocumentInfo describes a document attached to a identitymind application
+type DocumentInfo struct {
+	ID          *string    `json:"id"`
+	Filename    *string    `json:"name"`
+	ContentType *string    `json:"contentType"`
+	Description *string    `json:"description"`
+	UploadedAt  *time.Time `json:"uploadedAt"` // decoded from epoch milliseconds or an RFC 3339 timestamp
+	Size        *int64     `json:"size"`
+}
+
+// UnmarshalJSON implements json.Unmarshaler; identitymind reports timestamps in epoch milliseconds,
+// so uploadedAt is accepted either in that form or as an RFC 3339 timestamp
+func (d *DocumentInfo) UnmarshalJSON(data []byte) error {
+	type info DocumentInfo
+	var raw struct {
+		*info
+		UploadedAt json.RawMessage `json:"uploadedAt"`
+	}
+	raw.info = (*info)(d)
+	err := json.Unmarshal(data, &raw)
+	if err != nil {
+		return err
+	}
+	d.UploadedAt = nil
+	if len(raw.UploadedAt) == 0 || string(raw.UploadedAt) == "null" {
+		return nil
+	}
+	var millis int64
+	if err := json.Unmarshal(raw.UploadedAt, &millis); err == nil {
+		uploadedAt := time.Unix(0, millis*int64(time.Millisecond)).UTC()
+		d.UploadedAt = &uploadedAt
+		return nil
+	}
+	var uploadedAt time.Time
+	err = json.Unmarshal(raw.UploadedAt, &uploadedAt)
+	if err != nil {
+		return fmt.Errorf("Failed to parse document uploadedAt: %s; %s", string(raw.UploadedAt), err.Error())
+	}
+	d.UploadedAt = &uploadedAt
+	return nil
+}
+
+// documentList is the response of the document listing APIs; the listing is not described by the
+// published API reference, so both a bare array of documents and an object wrapping the array in
+// files are accepted
+type documentList struct {
+	Files []*DocumentInfo `json:"files"`
+}
+
+// UnmarshalJSON implements json.Unmarshaler
+func (l *documentList) UnmarshalJSON(data []byte) error {
+	trimmed := bytes.TrimSpace(data)
+	if len(trimmed) > 0 && trimmed[0] == '[' {
+		return json.Unmarshal(trimmed, &l.Files)
+	}
+	type list documentList
+	return json.Unmarshal(trimmed, (*list)(l))
+}
+
+func (l *documentList) documents() []*DocumentInfo {
+	if l == nil || l.Files == nil {
+		return make([]*DocumentInfo, 0)
+	}
+	return l.Files
+}
+
+// DocumentUpload describes a document which is streamed to identitymind as it is read, rather than
+// being buffered in memory as a data URL
+type DocumentUpload struct {
+	Reader      io.Reader // document content; read exactly once
+	Filename    string    // filename reported to identitymind; defaults to "document"
+	ContentType string    // MIME type of the document, i.e. image/jpeg or application/pdf; defaults to application/octet-stream
+	Description string    // optional description of the document
+}
+
+// postDocumentUpload streams the given document as a multipart/form-data POST request; since the body
+// is written as it is sent, the request is attempted exactly once regardless of the retry policy
+func (i *IdentityMindAPIClient) postDocumentUpload(ctx context.Context, uri string, upload *DocumentUpload, response interface{}) (status int, err error) {
+	if upload == nil || upload.Reader == nil {
+		return -1, fmt.Errorf("Failed to upload document; reader is required")
+	}
+
+	reader, writer := io.Pipe()
+	multipartWriter := multipart.NewWriter(writer)
+	go func() {
+		writer.CloseWithError(writeDocumentUpload(multipartWriter, upload))
+	}()
+
+	status, err = i.sendStream(ctx, "POST", i.buildURL(uri), multipartWriter.FormDataContentType(), reader, response)
+
+	// unblock the writer in the event the request failed before the body was fully consumed
+	reader.Close()
+	return status, err
+}
+
+func writeDocumentUpload(writer *multipart.Writer, upload *DocumentUpload) error {
+	if upload.Description != "" {
+		err := writer.WriteField("description", upload.Description)
+		if err != nil {
+			return err
+		}
+	}
+
+	filename := upload.Filename
+	if filename == "" {
+		filename = defaultDocumentFilename
+	}
+	contentType := upload.ContentType
+	if contentType == "" {
+		contentType = defaultDocumentContentType
+	}
+
+	header := make(textproto.MIMEHeader)
+	header.Set("Content-Disposition", fmt.Sprintf(`form-data; name="file"; filename="%s"`, quoteEscaper.Replace(filename)))
+	header.Set("Content-Type", contentType)
+	part, err := writer.CreatePart(header)
+	if err != nil {
+		return err
+	}
+	_, err = io.Copy(part, upload.Reader)
+	if err != nil {
+		return err
+	}
+	return writer.Close()
+}
+
+// DocumentDownload describes a document which was streamed from identitymind to an io.Writer
+type DocumentDownload struct {
+	Filename    string // filename from the Content-Disposition response header, when provided
+	ContentType string // MIME type from the Content-Type response header
+	Size        int64  // number of bytes written
+	Checksum    string // hex-encoded SHA-256 checksum of the bytes written
+}
+
+// getDocumentDownload streams the raw content of the given document to w; when the response carries a
+// Content-Length, Content-MD5 or sha-256 Digest header, the content written is verified against it
+func (i *IdentityMindAPIClient) getDocumentDownload(ctx context.Context, uri string, w io.Writer) (*DocumentDownload, int, error) {
+	log := i.logger()
+	if w == nil {
+		return nil, -1, fmt.Errorf("Failed to download document; writer is required")
+	}
+
+	urlString := i.buildURL(uri)
+	reqURL, err := url.Parse(urlString)
+	if err != nil {
+		log.Warningf("Failed to parse URL for identitymind API (GET %s) invocation; %s", urlString, err.Error())
+		return nil, -1, err
+	}
+
+	if i.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, i.Timeout)
+		defer cancel()
+	}
+
+	headers := i.buildHeaders()
+	headers.Set("Accept", "*/*")
+	resp, err := i.execute(ctx, http.MethodGet, reqURL, headers, nil, true)
+	if err != nil {
+		log.Warningf("Failed to invoke identitymind API (GET %s) method; %s", urlString, err.Error())
+		return nil, 0, err
+	}
+	defer resp.Body.Close()
+
+	log.Debugf("Received %v response for identitymind API (GET %s) invocation", resp.StatusCode, urlString)
+
+	var reader io.Reader = resp.Body
+	if resp.Header.Get("Content-Encoding") == "gzip" {
+		gzipReader, err := gzip.NewReader(resp.Body)
+		if err != nil {
+			return nil, resp.StatusCode, fmt.Errorf("Failed to decompress identitymind API (GET %s) response; %s", urlString, err.Error())
+		}
+		defer gzipReader.Close()
+		reader = gzipReader
+	}
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		body, _ := ioutil.ReadAll(reader)
+		apiErr := newAPIError(http.MethodGet, strings.TrimPrefix(reqURL.Path, "/"), resp.StatusCode, body)
+		log.Warningf("identitymind API (GET %s) invocation failed; %s", urlString, apiErr.Error())
+		return nil, resp.StatusCode, apiErr
+	}
+
+	download := &DocumentDownload{
+		ContentType: resp.Header.Get("Content-Type"),
+	}
+	if _, params, err := mime.ParseMediaType(resp.Header.Get("Content-Disposition")); err == nil {
+		download.Filename = params["filename"]
+	}
+
+	checksum := sha256.New()
+	hashes := []io.Writer{w, checksum}
+	var contentMD5 hash.Hash
+	if resp.Header.Get("Content-MD5") != "" {
+		contentMD5 = md5.New()
+		hashes = append(hashes, contentMD5)
+	}
+
+	download.Size, err = io.Copy(io.MultiWriter(hashes...), reader)
+	if err != nil {
+		return nil, resp.StatusCode, fmt.Errorf("Failed to read identitymind API (GET %s) response; %s", urlString, err.Error())
+	}
+	sum := checksum.Sum(nil)
+	download.Checksum = hex.EncodeToString(sum)
+
+	if resp.Header.Get("Content-Encoding") == "" && resp.ContentLength >= 0 && resp.ContentLength != download.Size {
+		return download, resp.StatusCode, fmt.Errorf("Failed to download document via identitymind API (GET %s); received %d of %d bytes", urlString, download.Size, resp.ContentLength)
+	}
+	if contentMD5 != nil && resp.Header.Get("Content-MD5") != base64.StdEncoding.EncodeToString(contentMD5.Sum(nil)) {
+		return download, resp.StatusCode, fmt.Errorf("Failed to download document via identitymind API (GET %s); Content-MD5 mismatch", urlString)
+	}
+	if digest, ok := parseSHA256Digest(resp.Header.Get("Digest")); ok && !bytes.Equal(digest, sum) {
+		return download, resp.StatusCode, fmt.Errorf("Failed to download document via identitymind API (GET %s); sha-256 digest mismatch", urlString)
+	}
+
+	log.Debugf("Invocation of identitymind API (GET %s) succeeded (%v-byte document)", urlString, download.Size)
+	return download, resp.StatusCode, nil
+}
+
+// parseSHA256Digest returns the sha-256 value of the given Digest header (RFC 3230), if any
+func parseSHA256Digest(header string) ([]byte, bool) {
+	for _, part := range strings.Split(header, ",") {
+		idx := strings.Index(part, "=")
+		if idx == -1 || !strings.EqualFold(strings.TrimSpace(part[:idx]), "sha-256") {
+			continue
+		}
+		digest, err := base64.StdEncoding.DecodeString(strings.TrimSpace(part[idx+1:]))
+		if err != nil {
+			return nil, false
+		}
+		return digest, true
+	}
+	return nil, false
+}
@@ -0,0 +1,228 @@
+package identitymind
+
+import (
+	"bytes"
+	"crypto/md5"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestUploadApplicationDocumentStream(t *testing.T) {
+	tests := []struct {
+		name                string
+		upload              *DocumentUpload
+		expectedFilename    string
+		expectedContentType string
+	}{
+		{"defaults", &DocumentUpload{Reader: strings.NewReader("content")}, defaultDocumentFilename, defaultDocumentContentType},
+		{"described", &DocumentUpload{Reader: strings.NewReader("content"), Filename: `pass"port.pdf`, ContentType: "application/pdf", Description: "passport"}, `pass"port.pdf`, "application/pdf"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			client, srv := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+				if r.URL.Path != "/im/account/consumer/app-1/files" {
+					t.Errorf("unexpected path %s", r.URL.Path)
+				}
+				if r.ContentLength != -1 {
+					t.Errorf("expected the upload to be streamed; got a %d-byte body", r.ContentLength)
+				}
+				file, header, err := r.FormFile("file")
+				if err != nil {
+					t.Errorf("failed to read the uploaded file; %s", err.Error())
+					return
+				}
+				content, _ := ioutil.ReadAll(file)
+				if string(content) != "content" {
+					t.Errorf("expected the document content; got %q", content)
+				}
+				if header.Filename != test.expectedFilename {
+					t.Errorf("expected filename %q; got %q", test.expectedFilename, header.Filename)
+				}
+				if contentType := header.Header.Get("Content-Type"); contentType != test.expectedContentType {
+					t.Errorf("expected content type %s; got %s", test.expectedContentType, contentType)
+				}
+				if description := r.FormValue("description"); description != test.upload.Description {
+					t.Errorf("expected description %q; got %q", test.upload.Description, description)
+				}
+				w.Write([]byte(`{"id":"doc-1"}`))
+			})
+			defer srv.Close()
+
+			resp, err := client.UploadApplicationDocumentStream("app-1", test.upload)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if id := resp.(map[string]interface{})["id"]; id != "doc-1" {
+				t.Fatalf("expected the upload response; got %v", resp)
+			}
+		})
+	}
+}
+
+// failingReader returns the given error once the given content has been read
+type failingReader struct {
+	content io.Reader
+	err     error
+}
+
+func (f *failingReader) Read(p []byte) (int, error) {
+	n, err := f.content.Read(p)
+	if err == io.EOF {
+		return n, f.err
+	}
+	return n, err
+}
+
+// endlessWriterTo writes to the multipart writer until the write fails, then reports the error
+type endlessWriterTo struct {
+	done chan error
+}
+
+func (e *endlessWriterTo) Read(p []byte) (int, error) {
+	return 0, errors.New("expected io.Copy to use WriteTo")
+}
+
+func (e *endlessWriterTo) WriteTo(w io.Writer) (int64, error) {
+	chunk := bytes.Repeat([]byte("a"), 32<<10)
+	var written int64
+	for {
+		n, err := w.Write(chunk)
+		written += int64(n)
+		if err != nil {
+			e.done <- err
+			return written, err
+		}
+	}
+}
+
+func TestUploadDocumentStreamFailures(t *testing.T) {
+	t.Run("reader_error", func(t *testing.T) {
+		readErr := errors.New("disk error")
+		client, srv := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+			ioutil.ReadAll(r.Body)
+			w.Write([]byte(`{}`))
+		})
+		defer srv.Close()
+
+		_, err := client.UploadApplicationDocumentStream("app-1", &DocumentUpload{
+			Reader: &failingReader{content: strings.NewReader("partial"), err: readErr},
+		})
+		if !errors.Is(err, readErr) {
+			t.Fatalf("expected the reader error to fail the upload; got %v", err)
+		}
+	})
+
+	t.Run("request_error", func(t *testing.T) {
+		client, srv := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusUnauthorized)
+		})
+		defer srv.Close()
+
+		reader := &endlessWriterTo{done: make(chan error, 1)}
+		_, err := client.UploadApplicationDocumentStream("app-1", &DocumentUpload{Reader: reader})
+		if err == nil {
+			t.Fatal("expected the upload to fail")
+		}
+		select {
+		case err := <-reader.done:
+			if err != io.ErrClosedPipe {
+				t.Fatalf("expected the pipe to be closed; got %v", err)
+			}
+		case <-time.After(5 * time.Second):
+			t.Fatal("expected the pipe to be closed when the request failed")
+		}
+	})
+
+	t.Run("missing_reader", func(t *testing.T) {
+		client, srv := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+			t.Error("expected no request to be sent without a reader")
+		})
+		defer srv.Close()
+
+		_, err := client.UploadApplicationDocumentStream("app-1", &DocumentUpload{})
+		if err == nil {
+			t.Fatal("expected an error uploading without a reader")
+		}
+	})
+}
+
+// contentLengthTransport overstates the Content-Length of responses of the default transport
+type contentLengthTransport struct{}
+
+func (contentLengthTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	resp, err := http.DefaultTransport.RoundTrip(req)
+	if err == nil {
+		resp.ContentLength++
+	}
+	return resp, err
+}
+
+func TestDownloadApplicationDocumentTo(t *testing.T) {
+	content := []byte("document content")
+	md5Sum := md5.Sum(content)
+	sha256Sum := sha256.Sum256(content)
+	contentMD5 := base64.StdEncoding.EncodeToString(md5Sum[:])
+	digest := "sha-256=" + base64.StdEncoding.EncodeToString(sha256Sum[:])
+	otherMD5 := md5.Sum([]byte("other"))
+	otherSHA256 := sha256.Sum256([]byte("other"))
+
+	tests := []struct {
+		name     string
+		header   http.Header
+		opts     []Option
+		expected string // substring of the expected error, if any
+	}{
+		{"unverified", http.Header{}, nil, ""},
+		{"verified", http.Header{"Content-Md5": []string{contentMD5}, "Digest": []string{"md5=" + contentMD5 + ", " + digest}}, nil, ""},
+		{"content_length_mismatch", http.Header{}, []Option{WithTransport(contentLengthTransport{})}, "received 16 of 17 bytes"},
+		{"content_md5_mismatch", http.Header{"Content-Md5": []string{base64.StdEncoding.EncodeToString(otherMD5[:])}}, nil, "Content-MD5 mismatch"},
+		{"digest_mismatch", http.Header{"Digest": []string{"SHA-256=" + base64.StdEncoding.EncodeToString(otherSHA256[:])}}, nil, "sha-256 digest mismatch"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			client, srv := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+				if r.URL.Path != "/im/account/consumer/app-1/files/doc-1" {
+					t.Errorf("unexpected path %s", r.URL.Path)
+				}
+				for key, vals := range test.header {
+					w.Header()[key] = vals
+				}
+				w.Header().Set("Content-Type", "application/pdf")
+				w.Header().Set("Content-Disposition", `attachment; filename="passport.pdf"`)
+				w.Write(content)
+			}, test.opts...)
+			defer srv.Close()
+
+			var buf bytes.Buffer
+			download, err := client.DownloadApplicationDocumentTo("app-1", "doc-1", &buf)
+			if test.expected != "" {
+				if err == nil || !strings.Contains(err.Error(), test.expected) {
+					t.Fatalf("expected an error containing %q; got %v", test.expected, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !bytes.Equal(buf.Bytes(), content) {
+				t.Fatalf("expected the document content; got %q", buf.Bytes())
+			}
+			if download.Filename != "passport.pdf" || download.ContentType != "application/pdf" || download.Size != int64(len(content)) {
+				t.Fatalf("unexpected download metadata; %+v", download)
+			}
+			if download.Checksum != hex.EncodeToString(sha256Sum[:]) {
+				t.Fatalf("expected checksum %x; got %s", sha256Sum, download.Checksum)
+			}
+		})
+	}
+}
@@ -0,0 +1,106 @@
+package identitymind
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// APIError is returned for any identitymind API invocation which results in a non-2xx response
+type APIError struct {
+	StatusCode int    // HTTP status code returned by the identitymind API
+	Method     string // HTTP method of the failed request
+	Endpoint   string // request path of the failed request, i.e. im/account/consumer
+	Code       string // identitymind error code, if one was provided in the response body
+	Message    string // identitymind error message, if one was provided in the response body
+	Body       []byte // raw response body
+}
+
+// Error implements the error interface
+func (e *APIError) Error() string {
+	msg := e.Message
+	if msg == "" {
+		msg = strings.TrimSpace(string(e.Body))
+	}
+	if msg == "" {
+		msg = http.StatusText(e.StatusCode)
+	}
+	if e.Code != "" {
+		return fmt.Sprintf("identitymind API (%s %s) returned %d; %s: %s", e.Method, e.Endpoint, e.StatusCode, e.Code, msg)
+	}
+	return fmt.Sprintf("identitymind API (%s %s) returned %d; %s", e.Method, e.Endpoint, e.StatusCode, msg)
+}
+
+// newAPIError constructs an APIError from the given response, extracting the identitymind
+// error code and message from the body when it is a recognizable JSON error payload
+func newAPIError(method, endpoint string, statusCode int, body []byte) *APIError {
+	apiErr := &APIError{
+		StatusCode: statusCode,
+		Method:     method,
+		Endpoint:   endpoint,
+		Body:       body,
+	}
+
+	var payload map[string]interface{}
+	if err := json.Unmarshal(body, &payload); err == nil {
+		apiErr.Code = firstErrorField(payload, "error_code", "errorCode", "code")
+		apiErr.Message = firstErrorField(payload, "error_message", "errorMessage", "message", "error")
+	}
+
+	return apiErr
+}
+
+func firstErrorField(payload map[string]interface{}, keys ...string) string {
+	for _, key := range keys {
+		switch val := payload[key].(type) {
+		case string:
+			if val != "" {
+				return val
+			}
+		case float64:
+			return fmt.Sprintf("%v", val)
+		}
+	}
+	return ""
+}
+
+// IsNotFound returns true if the given error is an APIError with a 404 status
+func IsNotFound(err error) bool {
+	return hasStatus(err, http.StatusNotFound)
+}
+
+// IsUnauthorized returns true if the given error is an APIError with a 401 or 403 status
+func IsUnauthorized(err error) bool {
+	return hasStatus(err, http.StatusUnauthorized, http.StatusForbidden)
+}
+
+// IsRateLimited returns true if the given error is an APIError with a 429 status
+func IsRateLimited(err error) bool {
+	return hasStatus(err, http.StatusTooManyRequests)
+}
+
+// IsValidation returns true if the given error is an APIError indicating the request was rejected as invalid
+func IsValidation(err error) bool {
+	return hasStatus(err, http.StatusBadRequest, http.StatusUnprocessableEntity)
+}
+
+// IsServerError returns true if the given error is an APIError with a 5xx status
+func IsServerError(err error) bool {
+	var apiErr *APIError
+	return errors.As(err, &apiErr) && apiErr.StatusCode >= 500
+}
+
+func hasStatus(err error, statuses ...int) bool {
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) {
+		return false
+	}
+	for _, status := range statuses {
+		if apiErr.StatusCode == status {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,73 @@
+package identitymind
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+)
+
+func TestAPIError(t *testing.T) {
+	tests := []struct {
+		name        string
+		status      int
+		body        string
+		code        string
+		message     string
+		notFound    bool
+		rateLimited bool
+		validation  bool
+		server      bool
+	}{
+		{"error_code and error_message", http.StatusBadRequest, `{"error_code":"INVALID_PARAM","error_message":"man is required"}`, "INVALID_PARAM", "man is required", false, false, true, false},
+		{"numeric code and message", http.StatusUnprocessableEntity, `{"code":42,"message":"bad"}`, "42", "bad", false, false, true, false},
+		{"error field", http.StatusNotFound, `{"error":"application not found"}`, "", "application not found", true, false, false, false},
+		{"plain text body", http.StatusTooManyRequests, `slow down`, "", "", false, true, false, false},
+		{"empty body", http.StatusServiceUnavailable, ``, "", "", false, false, false, true},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			client, srv := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(test.status)
+				fmt.Fprint(w, test.body)
+			}, WithRetryPolicy(nil))
+			defer srv.Close()
+
+			_, err := client.GetApplication("app-1")
+			if err == nil {
+				t.Fatal("expected an error")
+			}
+			var apiErr *APIError
+			if !errors.As(err, &apiErr) {
+				t.Fatalf("expected an APIError; got %v", err)
+			}
+			if apiErr.StatusCode != test.status || apiErr.Method != http.MethodGet || apiErr.Endpoint != "im/account/consumer/v2/app-1" {
+				t.Errorf("unexpected status, method or endpoint; got %d %s %s", apiErr.StatusCode, apiErr.Method, apiErr.Endpoint)
+			}
+			if apiErr.Code != test.code || apiErr.Message != test.message || string(apiErr.Body) != test.body {
+				t.Errorf("expected code %q, message %q and body %q; got %q, %q and %q", test.code, test.message, test.body, apiErr.Code, apiErr.Message, apiErr.Body)
+			}
+			if IsNotFound(err) != test.notFound || IsRateLimited(err) != test.rateLimited || IsValidation(err) != test.validation || IsServerError(err) != test.server {
+				t.Errorf("unexpected classification of %v", err)
+			}
+			if IsUnauthorized(err) {
+				t.Errorf("expected %v not to be unauthorized", err)
+			}
+			if apiErr.Error() == "" {
+				t.Errorf("expected a message")
+			}
+		})
+	}
+}
+
+func TestIsUnauthorized(t *testing.T) {
+	for _, status := range []int{http.StatusUnauthorized, http.StatusForbidden} {
+		err := fmt.Errorf("wrapped; %w", newAPIError(http.MethodGet, "im/account/consumer/app-1", status, nil))
+		if !IsUnauthorized(err) {
+			t.Errorf("expected %d to be unauthorized", status)
+		}
+	}
+	if IsUnauthorized(fmt.Errorf("not an api error")) || IsNotFound(nil) {
+		t.Errorf("expected errors other than APIError not to be classified")
+	}
+}
@@ -0,0 +1,213 @@
+package identitymind
+
+import (
+	"archive/zip"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+const documentExportManifestName = "manifest.json"
+
+// documentExportDirectory is the directory, relative to the export directory or archive root, into which
+// documents are written; documents are namespaced so their names cannot collide with the manifest
+const documentExportDirectory = "documents"
+
+// ExportedDocument describes a single document written by a DocumentExporter
+type ExportedDocument struct {
+	Info     *DocumentInfo     `json:"info"`     // document as listed by identitymind
+	Path     string            `json:"path"`     // slash-separated path of the exported file, relative to the export directory or archive root
+	Download *DocumentDownload `json:"download"` // metadata, including the checksum, of the downloaded content
+}
+
+// DocumentExporter downloads every document attached to an application, i.e. to assemble compliance
+// evidence; documents are written beneath documents/ and, alongside them, a manifest.json listing each
+// exported document and its checksum is written
+type DocumentExporter struct {
+	List     func(ctx context.Context, applicationID string) ([]*DocumentInfo, error)
+	Download func(ctx context.Context, applicationID, documentID string, w io.Writer) (*DocumentDownload, error)
+}
+
+// NewApplicationDocumentExporter initializes a DocumentExporter for consumer KYC applications
+func NewApplicationDocumentExporter(client KYC) *DocumentExporter {
+	return &DocumentExporter{
+		List:     client.ListApplicationDocumentsWithContext,
+		Download: client.DownloadApplicationDocumentToWithContext,
+	}
+}
+
+// NewBusinessApplicationDocumentExporter initializes a DocumentExporter for KYB applications
+func NewBusinessApplicationDocumentExporter(client KYB) *DocumentExporter {
+	return &DocumentExporter{
+		List:     client.ListBusinessApplicationDocumentsWithContext,
+		Download: client.DownloadBusinessApplicationDocumentToWithContext,
+	}
+}
+
+// NewMerchantApplicationDocumentExporter initializes a DocumentExporter for merchant KYC applications
+func NewMerchantApplicationDocumentExporter(client Merchants) *DocumentExporter {
+	return &DocumentExporter{
+		List:     client.ListMerchantApplicationDocumentsWithContext,
+		Download: client.DownloadMerchantApplicationDocumentToWithContext,
+	}
+}
+
+// NewMerchantBusinessApplicationDocumentExporter initializes a DocumentExporter for merchant KYB applications
+func NewMerchantBusinessApplicationDocumentExporter(client Merchants) *DocumentExporter {
+	return &DocumentExporter{
+		List:     client.ListMerchantBusinessApplicationDocumentsWithContext,
+		Download: client.DownloadMerchantBusinessApplicationDocumentToWithContext,
+	}
+}
+
+// ExportToDirectory downloads every document of the given application into dir, which is created if necessary
+func (e *DocumentExporter) ExportToDirectory(applicationID, dir string) ([]*ExportedDocument, error) {
+	return e.ExportToDirectoryWithContext(context.Background(), applicationID, dir)
+}
+
+// ExportToDirectoryWithContext downloads every document of the given application into dir, which is created
+// if necessary; since documents contain personal data, files are only readable by the current user
+func (e *DocumentExporter) ExportToDirectoryWithContext(ctx context.Context, applicationID, dir string) ([]*ExportedDocument, error) {
+	err := os.MkdirAll(filepath.Join(dir, documentExportDirectory), 0700)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to create document export directory %s; %s", dir, err.Error())
+	}
+
+	return e.export(ctx, applicationID, func(name string, modified time.Time, write func(io.Writer) error) error {
+		target := filepath.Join(dir, filepath.FromSlash(name))
+		file, err := os.OpenFile(target, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, 0600)
+		if err != nil {
+			return err
+		}
+		err = write(file)
+		if closeErr := file.Close(); err == nil {
+			err = closeErr
+		}
+		if err != nil {
+			os.Remove(target)
+			return err
+		}
+		if !modified.IsZero() {
+			os.Chtimes(target, modified, modified)
+		}
+		return nil
+	})
+}
+
+// ExportToZip downloads every document of the given application into a zip archive written to w
+func (e *DocumentExporter) ExportToZip(applicationID string, w io.Writer) ([]*ExportedDocument, error) {
+	return e.ExportToZipWithContext(context.Background(), applicationID, w)
+}
+
+// ExportToZipWithContext downloads every document of the given application into a zip archive written to w;
+// the archive is incomplete if an error is returned
+func (e *DocumentExporter) ExportToZipWithContext(ctx context.Context, applicationID string, w io.Writer) ([]*ExportedDocument, error) {
+	archive := zip.NewWriter(w)
+	exported, err := e.export(ctx, applicationID, func(name string, modified time.Time, write func(io.Writer) error) error {
+		header := &zip.FileHeader{
+			Name:   name,
+			Method: zip.Deflate,
+		}
+		if !modified.IsZero() {
+			header.Modified = modified
+		}
+		entry, err := archive.CreateHeader(header)
+		if err != nil {
+			return err
+		}
+		return write(entry)
+	})
+	if err != nil {
+		return exported, err
+	}
+
+	err = archive.Close()
+	if err != nil {
+		return exported, fmt.Errorf("Failed to write document export archive; %s", err.Error())
+	}
+	return exported, nil
+}
+
+// export lists and downloads each document of the application using create, followed by the manifest
+func (e *DocumentExporter) export(ctx context.Context, applicationID string, create func(name string, modified time.Time, write func(io.Writer) error) error) ([]*ExportedDocument, error) {
+	docs, err := e.List(ctx, applicationID)
+	if err != nil {
+		return nil, err
+	}
+
+	exported := make([]*ExportedDocument, 0, len(docs))
+	for _, doc := range docs {
+		if doc == nil || doc.ID == nil {
+			continue
+		}
+
+		modified := time.Time{}
+		if doc.UploadedAt != nil {
+			modified = *doc.UploadedAt
+		}
+
+		name := path.Join(documentExportDirectory, exportedDocumentName(doc))
+		var download *DocumentDownload
+		err := create(name, modified, func(w io.Writer) error {
+			var err error
+			download, err = e.Download(ctx, applicationID, *doc.ID, w)
+			return err
+		})
+		if err != nil {
+			return exported, fmt.Errorf("Failed to export document %s of application %s; %w", *doc.ID, applicationID, err)
+		}
+		exported = append(exported, &ExportedDocument{
+			Info:     doc,
+			Path:     name,
+			Download: download,
+		})
+	}
+
+	err = create(documentExportManifestName, time.Now(), func(w io.Writer) error {
+		encoder := json.NewEncoder(w)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(map[string]interface{}{
+			"applicationId": applicationID,
+			"exportedAt":    time.Now().UTC(),
+			"documents":     exported,
+		})
+	})
+	if err != nil {
+		return exported, fmt.Errorf("Failed to write document export manifest for application %s; %s", applicationID, err.Error())
+	}
+	return exported, nil
+}
+
+// exportedDocumentName returns a filename, unique within the application, for the given document; the
+// document identifier is prefixed since filenames provided at upload time need not be unique
+func exportedDocumentName(doc *DocumentInfo) string {
+	name := *doc.ID
+	if doc.Filename != nil {
+		filename := strings.Map(func(r rune) rune {
+			if r == '/' || r == '\\' || r == ':' || r < 0x20 {
+				return '_'
+			}
+			return r
+		}, *doc.Filename)
+		filename = strings.Trim(filename, ". ")
+		if filename != "" {
+			name = fmt.Sprintf("%s-%s", name, filename)
+		}
+	}
+	name = strings.Map(func(r rune) rune {
+		if r == '/' || r == '\\' {
+			return '_'
+		}
+		return r
+	}, name)
+	if name == "." || name == ".." {
+		name = strings.Repeat("_", len(name))
+	}
+	return name
+}
@@ -0,0 +1,72 @@
+package identitymind
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"testing"
+	"time"
+)
+
+func TestDocumentListUnmarshalJSON(t *testing.T) {
+	uploadedAt := time.Date(2020, 1, 2, 3, 4, 5, 0, time.UTC)
+	tests := []struct {
+		name string
+		raw  string
+	}{
+		{"array with epoch milliseconds", `[{"id":"doc-1","name":"passport.png","uploadedAt":1577934245000}]`},
+		{"files with RFC 3339 timestamp", `{"files":[{"id":"doc-1","name":"passport.png","uploadedAt":"2020-01-02T03:04:05Z"}]}`},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var list *documentList
+			err := json.Unmarshal([]byte(test.raw), &list)
+			if err != nil {
+				t.Fatal(err)
+			}
+			docs := list.documents()
+			if len(docs) != 1 || *docs[0].ID != "doc-1" || *docs[0].Filename != "passport.png" {
+				t.Fatalf("expected a single document; got %v", docs)
+			}
+			if docs[0].UploadedAt == nil || !docs[0].UploadedAt.Equal(uploadedAt) {
+				t.Fatalf("expected uploadedAt %s; got %v", uploadedAt, docs[0].UploadedAt)
+			}
+		})
+	}
+}
+
+func TestDocumentExportNamespacesDocuments(t *testing.T) {
+	documentID := documentExportManifestName
+	exporter := &DocumentExporter{
+		List: func(ctx context.Context, applicationID string) ([]*DocumentInfo, error) {
+			return []*DocumentInfo{{ID: &documentID}}, nil
+		},
+		Download: func(ctx context.Context, applicationID, documentID string, w io.Writer) (*DocumentDownload, error) {
+			_, err := w.Write([]byte("document"))
+			return &DocumentDownload{Size: 8}, err
+		},
+	}
+
+	var buf bytes.Buffer
+	exported, err := exporter.ExportToZip("app-1", &buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(exported) != 1 || exported[0].Path != "documents/manifest.json" {
+		t.Fatalf("expected the document to be exported beneath documents/; got %v", exported)
+	}
+
+	archive, err := zip.NewReader(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	names := make([]string, 0)
+	for _, file := range archive.File {
+		names = append(names, file.Name)
+	}
+	if len(names) != 2 || names[0] != "documents/manifest.json" || names[1] != documentExportManifestName {
+		t.Fatalf("expected the document and the manifest; got %v", names)
+	}
+}
@@ -8,7 +8,7 @@ import (
 	"github.com/kthomas/go-logger"
 )
 
-const identitymindDefaultEnvironment = "sandbox" // use 'edna' for production; see https://edoc.identitymind.com/reference#section-integration-environments
+const identitymindDefaultEnvironment = EnvironmentSandbox // use 'edna' for production; see https://edoc.identitymind.com/reference#section-integration-environments
 
 var (
 	log           *logger.Logger
@@ -5,4 +5,5 @@ go 1.13
 require (
 	github.com/kthomas/go-logger v0.0.0-20200602072946-d7d72dfc2531
 	github.com/vincent-petithory/dataurl v0.0.0-20191104211930-d1553a71de50
+	golang.org/x/crypto v0.0.0-20200622213623-75b288015ac9
 )
@@ -0,0 +1,239 @@
+package identitymindfake
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	identitymind "github.com/kthomas/identitymind-golang"
+	"github.com/kthomas/identitymind-golang/internal/apiparams"
+)
+
+// GetCase implements identitymind.Cases
+func (f *Fake) GetCase(caseID string) (*identitymind.Case, error) {
+	return f.GetCaseWithContext(context.Background(), caseID)
+}
+
+// GetCaseWithContext implements identitymind.Cases
+func (f *Fake) GetCaseWithContext(ctx context.Context, caseID string) (*identitymind.Case, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	c, ok := f.cases[caseID]
+	if !ok {
+		return nil, notFound("case", caseID)
+	}
+	return toCase(c)
+}
+
+// CreateCase implements identitymind.Cases
+func (f *Fake) CreateCase(params map[string]interface{}) (*identitymind.Case, error) {
+	return f.CreateCaseWithContext(context.Background(), params)
+}
+
+// CreateCaseWithContext implements identitymind.Cases
+func (f *Fake) CreateCaseWithContext(ctx context.Context, params map[string]interface{}) (*identitymind.Case, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	now := time.Now().UTC()
+	c := &Case{
+		ID:        f.nextID("case"),
+		Params:    map[string]interface{}{},
+		Status:    identitymind.CaseStatusOpen,
+		CreatedAt: now,
+	}
+	f.applyCaseParams(c, params, now)
+	f.cases[c.ID] = c
+	f.caseOrder = append(f.caseOrder, c.ID)
+	return toCase(c)
+}
+
+// OpenCase implements identitymind.Cases
+func (f *Fake) OpenCase(request *identitymind.CaseRequest) (*identitymind.Case, error) {
+	return f.OpenCaseWithContext(context.Background(), request)
+}
+
+// OpenCaseWithContext implements identitymind.Cases
+func (f *Fake) OpenCaseWithContext(ctx context.Context, request *identitymind.CaseRequest) (*identitymind.Case, error) {
+	if request == nil {
+		return nil, fmt.Errorf("Failed to create case; request is required")
+	}
+	if err := request.Validate(); err != nil {
+		return nil, fmt.Errorf("Failed to create case; %s", err.Error())
+	}
+	params, err := apiparams.Marshal(request)
+	if err != nil {
+		return nil, err
+	}
+	return f.CreateCaseWithContext(ctx, params)
+}
+
+// CloseCase implements identitymind.Cases
+func (f *Fake) CloseCase(caseID string, params map[string]interface{}) (*identitymind.Case, error) {
+	return f.CloseCaseWithContext(context.Background(), caseID, params)
+}
+
+// CloseCaseWithContext implements identitymind.Cases
+func (f *Fake) CloseCaseWithContext(ctx context.Context, caseID string, params map[string]interface{}) (*identitymind.Case, error) {
+	return f.updateCase(ctx, caseID, params, true)
+}
+
+// ResolveCase implements identitymind.Cases
+func (f *Fake) ResolveCase(caseID string, closure *identitymind.CaseClosure) (*identitymind.Case, error) {
+	return f.ResolveCaseWithContext(context.Background(), caseID, closure)
+}
+
+// ResolveCaseWithContext implements identitymind.Cases
+func (f *Fake) ResolveCaseWithContext(ctx context.Context, caseID string, closure *identitymind.CaseClosure) (*identitymind.Case, error) {
+	if closure == nil {
+		return nil, fmt.Errorf("Failed to close case %s; closure is required", caseID)
+	}
+	if err := closure.Validate(); err != nil {
+		return nil, fmt.Errorf("Failed to close case %s; %s", caseID, err.Error())
+	}
+	params, err := apiparams.Marshal(closure)
+	if err != nil {
+		return nil, err
+	}
+	return f.updateCase(ctx, caseID, params, true)
+}
+
+// UpdateCase implements identitymind.Cases
+func (f *Fake) UpdateCase(caseID string, params map[string]interface{}) (*identitymind.Case, error) {
+	return f.UpdateCaseWithContext(context.Background(), caseID, params)
+}
+
+// UpdateCaseWithContext implements identitymind.Cases
+func (f *Fake) UpdateCaseWithContext(ctx context.Context, caseID string, params map[string]interface{}) (*identitymind.Case, error) {
+	return f.updateCase(ctx, caseID, params, false)
+}
+
+// EditCase implements identitymind.Cases
+func (f *Fake) EditCase(caseID string, update *identitymind.CaseUpdate) (*identitymind.Case, error) {
+	return f.EditCaseWithContext(context.Background(), caseID, update)
+}
+
+// EditCaseWithContext implements identitymind.Cases
+func (f *Fake) EditCaseWithContext(ctx context.Context, caseID string, update *identitymind.CaseUpdate) (*identitymind.Case, error) {
+	if update == nil {
+		return nil, fmt.Errorf("Failed to update case %s; update is required", caseID)
+	}
+	if err := update.Validate(); err != nil {
+		return nil, fmt.Errorf("Failed to update case %s; %s", caseID, err.Error())
+	}
+	params, err := apiparams.Marshal(update)
+	if err != nil {
+		return nil, err
+	}
+	return f.updateCase(ctx, caseID, params, false)
+}
+
+func (f *Fake) updateCase(ctx context.Context, caseID string, params map[string]interface{}, closed bool) (*identitymind.Case, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	c, ok := f.cases[caseID]
+	if !ok {
+		return nil, notFound("case", caseID)
+	}
+
+	status := c.Status
+	if state, _ := params["state"].(string); state != "" {
+		status = identitymind.CaseStatus(state)
+	}
+	if closed {
+		status = identitymind.CaseStatusClosed
+	}
+	if status != c.Status {
+		err := identitymind.ValidateCaseTransition(c.Status, status)
+		if err != nil {
+			return nil, &identitymind.APIError{
+				StatusCode: http.StatusConflict,
+				Method:     http.MethodPost,
+				Endpoint:   fmt.Sprintf("case/%s", caseID),
+				Message:    err.Error(),
+			}
+		}
+	} else if closed {
+		return nil, &identitymind.APIError{
+			StatusCode: http.StatusConflict,
+			Method:     http.MethodPost,
+			Endpoint:   fmt.Sprintf("case/%s", caseID),
+			Message:    fmt.Sprintf("case already closed: %s", caseID),
+		}
+	}
+
+	now := time.Now().UTC()
+	f.applyCaseParams(c, params, now)
+	if status != c.Status {
+		c.Status = status
+		c.Closed = status.IsClosed()
+		c.ClosedAt = nil
+		if c.Closed {
+			c.ClosedAt = &now
+		}
+	}
+	return toCase(c)
+}
+
+// applyCaseParams merges the given params into the case, recording any note; the status is
+// managed by the caller. The caller must hold the mutex
+func (f *Fake) applyCaseParams(c *Case, params map[string]interface{}, now time.Time) {
+	for key, val := range params {
+		switch key {
+		case "caseId", "state", "note", "noteAuthor", "notes", "createdAt", "updatedAt", "closedAt":
+			continue
+		}
+		c.Params[key] = val
+	}
+	if text, _ := params["note"].(string); text != "" {
+		author, _ := params["noteAuthor"].(string)
+		note := &CaseNote{
+			ID:        f.nextID("note"),
+			Author:    author,
+			Text:      text,
+			CreatedAt: now,
+		}
+		c.Notes = append(c.Notes, note)
+	}
+	c.UpdatedAt = now
+}
+
+func caseParams(c *Case) map[string]interface{} {
+	params := apiparams.Copy(c.Params)
+	params["caseId"] = c.ID
+	params["state"] = string(c.Status)
+	params["notes"] = append([]*CaseNote{}, c.Notes...)
+	params["createdAt"] = c.CreatedAt
+	params["updatedAt"] = c.UpdatedAt
+	if c.ClosedAt != nil {
+		params["closedAt"] = *c.ClosedAt
+	}
+	return params
+}
+
+// toCase returns the typed representation of the case, as returned by the API
+func toCase(c *Case) (*identitymind.Case, error) {
+	raw, err := json.Marshal(caseParams(c))
+	if err != nil {
+		return nil, err
+	}
+	var resp *identitymind.Case
+	err = json.Unmarshal(raw, &resp)
+	if err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
@@ -0,0 +1,554 @@
+// Package identitymindfake provides an in-memory implementation of the identitymind.Client
+// interface, with scriptable outcomes, for use in unit tests of code which depends on the
+// identitymind API.
+package identitymindfake
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"sync"
+	"time"
+
+	identitymind "github.com/kthomas/identitymind-golang"
+	"github.com/kthomas/identitymind-golang/internal/apiparams"
+	"github.com/kthomas/identitymind-golang/internal/testdouble"
+)
+
+// Outcome is the scripted result of an application submission or transaction evaluation
+type Outcome struct {
+	State identitymind.ApplicationState // resulting state, when Err is nil
+	Err   error                         // error returned in place of a result, when non-nil
+}
+
+// Accept results in an accepted application or transaction
+var Accept = Outcome{State: identitymind.ApplicationStateAccepted}
+
+// Reject results in a rejected application or transaction
+var Reject = Outcome{State: identitymind.ApplicationStateRejected}
+
+// Review results in an application or transaction which is under review
+var Review = Outcome{State: identitymind.ApplicationStateUnderReview}
+
+// Fail results in the given error being returned
+func Fail(err error) Outcome {
+	return Outcome{Err: err}
+}
+
+// ApplicationKind distinguishes the kinds of applications tracked by the fake
+type ApplicationKind string
+
+// ApplicationKindConsumer is a consumer KYC application
+const ApplicationKindConsumer ApplicationKind = "consumer"
+
+// ApplicationKindBusiness is a KYB application
+const ApplicationKindBusiness ApplicationKind = "business"
+
+// ApplicationKindMerchant is a merchant KYC application
+const ApplicationKindMerchant ApplicationKind = "merchant"
+
+// ApplicationKindMerchantBusiness is a merchant KYB application
+const ApplicationKindMerchantBusiness ApplicationKind = "merchant_business"
+
+// Application is an application submitted to the fake
+type Application struct {
+	ID         string
+	Kind       ApplicationKind
+	MerchantID string
+	Params     map[string]interface{}
+	State      identitymind.ApplicationState
+	Feedback   []map[string]interface{} // params of each approve, reject or undecide call
+	Responses  []map[string]interface{} // params of each quiz response
+}
+
+// Document is a document or verification image uploaded to the fake
+type Document = testdouble.Document
+
+// Case is a case created via the fake
+type Case = testdouble.Case
+
+// CaseNote is a note recorded against a case created via the fake
+type CaseNote = testdouble.CaseNote
+
+// Transaction is a transaction evaluated or reported via the fake
+type Transaction = testdouble.Transaction
+
+// TransactionFeedback is feedback provided for a transaction via the fake
+type TransactionFeedback = testdouble.TransactionFeedback
+
+// Fake is an in-memory identitymind.Client; the zero value is not usable, use New
+type Fake struct {
+	// DefaultOutcome is applied to submissions and evaluations for which no outcome has been scripted
+	DefaultOutcome Outcome
+
+	// TravelRulePolicy, when non-nil, is checked before reporting transfers and withdrawals, as by the client
+	TravelRulePolicy *identitymind.TravelRulePolicy
+
+	mutex        sync.Mutex
+	seq          int
+	queue        []Outcome
+	accounts     map[string]Outcome
+	applications map[string]*Application
+	appOrder     []string
+	documents    map[string][]*Document
+	merchants    map[string]map[string]interface{}
+	cases        map[string]*Case
+	caseOrder    []string
+	transactions []*Transaction
+	fraudReports []map[string]interface{}
+	changed      chan struct{}
+}
+
+var _ identitymind.Client = (*Fake)(nil)
+
+// New initializes a Fake which accepts everything unless otherwise scripted
+func New() *Fake {
+	return &Fake{
+		DefaultOutcome: Accept,
+		accounts:       map[string]Outcome{},
+		applications:   map[string]*Application{},
+		documents:      map[string][]*Document{},
+		merchants:      map[string]map[string]interface{}{},
+		cases:          map[string]*Case{},
+		changed:        make(chan struct{}),
+	}
+}
+
+// Enqueue scripts the outcomes of the next submissions and evaluations, in order
+func (f *Fake) Enqueue(outcomes ...Outcome) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	f.queue = append(f.queue, outcomes...)
+}
+
+// SetAccountOutcome scripts the outcome of every submission for the given account name (man);
+// account outcomes take precedence over enqueued outcomes
+func (f *Fake) SetAccountOutcome(accountName string, outcome Outcome) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	f.accounts[accountName] = outcome
+}
+
+// Decide simulates a reviewer accepting or rejecting the given application, which must be under review
+func (f *Fake) Decide(applicationID string, state identitymind.ApplicationState) error {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	app, ok := f.applications[applicationID]
+	if !ok {
+		return notFound("application", applicationID)
+	}
+	err := testdouble.ValidateDecision(app.State, state)
+	if err != nil {
+		return fmt.Errorf("Failed to decide application %s; %s", applicationID, err.Error())
+	}
+	f.setState(app, state)
+	return nil
+}
+
+// Applications returns a snapshot of every submitted application, in order of submission
+func (f *Fake) Applications() []*Application {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	apps := make([]*Application, 0, len(f.appOrder))
+	for _, id := range f.appOrder {
+		apps = append(apps, copyApplication(f.applications[id]))
+	}
+	return apps
+}
+
+// Application returns a snapshot of the given application
+func (f *Fake) Application(applicationID string) (*Application, bool) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	app, ok := f.applications[applicationID]
+	if !ok {
+		return nil, false
+	}
+	return copyApplication(app), true
+}
+
+// Documents returns the documents and verification images uploaded for the given application
+func (f *Fake) Documents(applicationID string) []*Document {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	docs := make([]*Document, len(f.documents[applicationID]))
+	copy(docs, f.documents[applicationID])
+	return docs
+}
+
+// Cases returns the cases created via the fake
+func (f *Fake) Cases() []*Case {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	cases := make([]*Case, 0, len(f.caseOrder))
+	for _, id := range f.caseOrder {
+		c := *f.cases[id]
+		c.Params = apiparams.Copy(c.Params)
+		c.Notes = append([]*CaseNote{}, c.Notes...)
+		cases = append(cases, &c)
+	}
+	return cases
+}
+
+// Transactions returns the transactions evaluated or reported via the fake, in order
+func (f *Fake) Transactions() []*Transaction {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	txs := make([]*Transaction, 0, len(f.transactions))
+	for _, tx := range f.transactions {
+		t := *tx
+		t.Feedback = append([]*TransactionFeedback{}, tx.Feedback...)
+		txs = append(txs, &t)
+	}
+	return txs
+}
+
+// FraudReports returns the params of each fraud event reported via the fake
+func (f *Fake) FraudReports() []map[string]interface{} {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	reports := make([]map[string]interface{}, len(f.fraudReports))
+	copy(reports, f.fraudReports)
+	return reports
+}
+
+// nextOutcome returns the scripted outcome for the given account name; the caller must hold the mutex
+func (f *Fake) nextOutcome(accountName string) Outcome {
+	if outcome, ok := f.accounts[accountName]; ok && accountName != "" {
+		return outcome
+	}
+	if len(f.queue) > 0 {
+		outcome := f.queue[0]
+		f.queue = f.queue[1:]
+		return outcome
+	}
+	return f.DefaultOutcome
+}
+
+// nextID returns a new unique identifier; the caller must hold the mutex
+func (f *Fake) nextID(prefix string) string {
+	f.seq++
+	return fmt.Sprintf("fake-%s-%d", prefix, f.seq)
+}
+
+// setState updates the state of the application and wakes any waiters; the caller must hold the mutex
+func (f *Fake) setState(app *Application, state identitymind.ApplicationState) {
+	app.State = state
+	close(f.changed)
+	f.changed = make(chan struct{})
+}
+
+func (f *Fake) submit(ctx context.Context, kind ApplicationKind, merchantID string, params map[string]interface{}) (*Application, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	accountName, _ := params["man"].(string)
+	outcome := f.nextOutcome(accountName)
+	if outcome.Err != nil {
+		return nil, outcome.Err
+	}
+
+	id, _ := params["tid"].(string)
+	if id == "" {
+		id = f.nextID("app")
+	}
+	app := &Application{
+		ID:         id,
+		Kind:       kind,
+		MerchantID: merchantID,
+		Params:     apiparams.Copy(params),
+	}
+	if _, exists := f.applications[id]; !exists {
+		f.appOrder = append(f.appOrder, id)
+	}
+	f.applications[id] = app
+	f.setState(app, outcome.State)
+	return copyApplication(app), nil
+}
+
+func (f *Fake) get(ctx context.Context, applicationID string) (*Application, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	app, ok := f.applications[applicationID]
+	if !ok {
+		return nil, notFound("application", applicationID)
+	}
+	return copyApplication(app), nil
+}
+
+func (f *Fake) feedback(ctx context.Context, applicationID string, state identitymind.ApplicationState, params map[string]interface{}) (interface{}, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	app, ok := f.applications[applicationID]
+	if !ok {
+		return nil, notFound("application", applicationID)
+	}
+	app.Feedback = append(app.Feedback, apiparams.Copy(params))
+	f.setState(app, state)
+	return evaluationParams(app), nil
+}
+
+func (f *Fake) respond(ctx context.Context, applicationID string, params map[string]interface{}) (interface{}, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	app, ok := f.applications[applicationID]
+	if !ok {
+		return nil, notFound("application", applicationID)
+	}
+	app.Responses = append(app.Responses, apiparams.Copy(params))
+	return evaluationParams(app), nil
+}
+
+func (f *Fake) upload(ctx context.Context, applicationID string, verificationImage bool, params map[string]interface{}) (interface{}, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	if _, ok := f.applications[applicationID]; !ok {
+		return nil, notFound("application", applicationID)
+	}
+	doc := &Document{
+		ID:                f.nextID("doc"),
+		ApplicationID:     applicationID,
+		VerificationImage: verificationImage,
+		Params:            apiparams.Copy(params),
+		UploadedAt:        time.Now(),
+	}
+	f.documents[applicationID] = append(f.documents[applicationID], doc)
+	return map[string]interface{}{"id": doc.ID}, nil
+}
+
+func (f *Fake) uploadStream(ctx context.Context, applicationID string, verificationImage bool, upload *identitymind.DocumentUpload) (interface{}, error) {
+	if upload == nil || upload.Reader == nil {
+		return nil, fmt.Errorf("Failed to upload document; reader is required")
+	}
+	data, err := ioutil.ReadAll(upload.Reader)
+	if err != nil {
+		return nil, err
+	}
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	if _, ok := f.applications[applicationID]; !ok {
+		return nil, notFound("application", applicationID)
+	}
+	doc := &Document{
+		ID:                f.nextID("doc"),
+		ApplicationID:     applicationID,
+		VerificationImage: verificationImage,
+		Params:            map[string]interface{}{},
+		Filename:          upload.Filename,
+		ContentType:       upload.ContentType,
+		Description:       upload.Description,
+		Data:              data,
+		UploadedAt:        time.Now(),
+	}
+	if doc.Description != "" {
+		doc.Params["description"] = doc.Description
+	}
+	f.documents[applicationID] = append(f.documents[applicationID], doc)
+	return map[string]interface{}{"id": doc.ID}, nil
+}
+
+func (f *Fake) listDocuments(ctx context.Context, applicationID string) ([]*identitymind.DocumentInfo, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	if _, ok := f.applications[applicationID]; !ok {
+		return nil, notFound("application", applicationID)
+	}
+	docs := make([]*identitymind.DocumentInfo, 0)
+	for _, doc := range f.documents[applicationID] {
+		docs = append(docs, documentInfo(doc))
+	}
+	return docs, nil
+}
+
+func (f *Fake) downloadDocument(ctx context.Context, applicationID, documentID string) (interface{}, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	for _, doc := range f.documents[applicationID] {
+		if doc.ID == documentID {
+			return documentParams(doc), nil
+		}
+	}
+	return nil, notFound("document", documentID)
+}
+
+func (f *Fake) downloadDocumentTo(ctx context.Context, applicationID, documentID string, w io.Writer) (*identitymind.DocumentDownload, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	f.mutex.Lock()
+	var doc *Document
+	for _, candidate := range f.documents[applicationID] {
+		if candidate.ID == documentID {
+			doc = candidate
+			break
+		}
+	}
+	f.mutex.Unlock()
+	if doc == nil {
+		return nil, notFound("document", documentID)
+	}
+
+	size, err := w.Write(doc.Data)
+	if err != nil {
+		return nil, err
+	}
+	checksum := sha256.Sum256(doc.Data)
+	return &identitymind.DocumentDownload{
+		Filename:    doc.Filename,
+		ContentType: doc.ContentType,
+		Size:        int64(size),
+		Checksum:    hex.EncodeToString(checksum[:]),
+	}, nil
+}
+
+// waitForDecision blocks until the application is decided, invoking notify each time a new state is observed
+func (f *Fake) waitForDecision(ctx context.Context, applicationID string, notify func(identitymind.StateTransition, *Application)) (*Application, error) {
+	state := identitymind.ApplicationState("")
+	var last *Application
+
+	for {
+		f.mutex.Lock()
+		app, ok := f.applications[applicationID]
+		if !ok {
+			f.mutex.Unlock()
+			return nil, notFound("application", applicationID)
+		}
+		current := copyApplication(app)
+		changed := f.changed
+		f.mutex.Unlock()
+
+		last = current
+		if next := current.State; next != state {
+			notify(identitymind.StateTransition{From: state, To: next}, current)
+			state = next
+		}
+		if state.IsTerminal() {
+			return last, nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return last, fmt.Errorf("Failed to wait for identitymind application decision; last observed state: %s; %w", state, ctx.Err())
+		case <-changed:
+		}
+	}
+}
+
+func notFound(resource, id string) error {
+	return &identitymind.APIError{
+		StatusCode: http.StatusNotFound,
+		Method:     http.MethodGet,
+		Endpoint:   fmt.Sprintf("%s/%s", resource, id),
+		Message:    fmt.Sprintf("%s not found: %s", resource, id),
+	}
+}
+
+func evaluation(app *Application) *identitymind.KYCApplication {
+	id := app.ID
+	state := app.State
+	result := state.PolicyResult()
+	return &identitymind.KYCApplication{
+		MTID:  &id,
+		TID:   &id,
+		State: &state,
+		FRP:   &result,
+		RES:   &result,
+	}
+}
+
+func businessEvaluation(app *Application) *identitymind.BusinessApplication {
+	id := app.ID
+	state := app.State
+	result := state.PolicyResult()
+	return &identitymind.BusinessApplication{
+		MTID:  &id,
+		TID:   &id,
+		State: &state,
+		FRP:   &result,
+		RES:   &result,
+	}
+}
+
+func evaluationParams(app *Application) map[string]interface{} {
+	return map[string]interface{}{
+		"mtid":  app.ID,
+		"tid":   app.ID,
+		"state": string(app.State),
+		"frp":   string(app.State.PolicyResult()),
+		"res":   string(app.State.PolicyResult()),
+	}
+}
+
+func documentInfo(doc *Document) *identitymind.DocumentInfo {
+	id := doc.ID
+	filename := doc.Filename
+	contentType := doc.ContentType
+	description := doc.Description
+	uploadedAt := doc.UploadedAt
+	size := int64(len(doc.Data))
+	return &identitymind.DocumentInfo{
+		ID:          &id,
+		Filename:    &filename,
+		ContentType: &contentType,
+		Description: &description,
+		UploadedAt:  &uploadedAt,
+		Size:        &size,
+	}
+}
+
+func documentParams(doc *Document) map[string]interface{} {
+	params := apiparams.Copy(doc.Params)
+	params["id"] = doc.ID
+	if doc.Filename != "" {
+		params["name"] = doc.Filename
+	}
+	if doc.ContentType != "" {
+		params["contentType"] = doc.ContentType
+	}
+	return params
+}
+
+func copyApplication(app *Application) *Application {
+	cpy := *app
+	cpy.Params = apiparams.Copy(app.Params)
+	cpy.Feedback = append([]map[string]interface{}{}, app.Feedback...)
+	cpy.Responses = append([]map[string]interface{}{}, app.Responses...)
+	return &cpy
+}
@@ -0,0 +1,114 @@
+package identitymindfake
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+
+	identitymind "github.com/kthomas/identitymind-golang"
+)
+
+func TestFakeScriptedOutcomes(t *testing.T) {
+	errUnavailable := errors.New("unavailable")
+	tests := []struct {
+		name     string
+		script   func(f *Fake)
+		expected identitymind.ApplicationState
+		err      error
+	}{
+		{"default", func(f *Fake) {}, identitymind.ApplicationStateAccepted, nil},
+		{"default outcome", func(f *Fake) { f.DefaultOutcome = Review }, identitymind.ApplicationStateUnderReview, nil},
+		{"enqueued", func(f *Fake) { f.Enqueue(Reject) }, identitymind.ApplicationStateRejected, nil},
+		{"account outcome", func(f *Fake) {
+			f.Enqueue(Reject)
+			f.SetAccountOutcome("alice", Review)
+		}, identitymind.ApplicationStateUnderReview, nil},
+		{"failure", func(f *Fake) { f.Enqueue(Fail(errUnavailable)) }, "", errUnavailable},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			f := New()
+			test.script(f)
+			app, err := f.SubmitConsumerApplication(&identitymind.ConsumerApplicationRequest{AccountName: "alice"})
+			if err != test.err {
+				t.Fatalf("expected error %v; got %v", test.err, err)
+			}
+			if err != nil {
+				return
+			}
+			if app.CurrentState() != test.expected {
+				t.Fatalf("expected state %s; got %s", test.expected, app.CurrentState())
+			}
+			if app.FRP == nil || *app.FRP != test.expected.PolicyResult() {
+				t.Fatalf("expected policy result %s; got %v", test.expected.PolicyResult(), app.FRP)
+			}
+		})
+	}
+}
+
+func TestFakeDecide(t *testing.T) {
+	f := New()
+	f.Enqueue(Review)
+	app, err := f.SubmitApplication(map[string]interface{}{"man": "alice"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = f.Decide(*app.MTID, identitymind.ApplicationStateAccepted)
+	if err != nil {
+		t.Fatal(err)
+	}
+	app, err = f.GetApplication(*app.MTID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !app.IsAccepted() {
+		t.Fatalf("expected the application to be accepted; got %s", app.CurrentState())
+	}
+
+	err = f.Decide(*app.MTID, identitymind.ApplicationStateRejected)
+	if err == nil {
+		t.Fatal("expected deciding an application which is not under review to fail")
+	}
+
+	err = f.Decide("missing", identitymind.ApplicationStateAccepted)
+	if !identitymind.IsNotFound(err) {
+		t.Fatalf("expected a not found error; got %v", err)
+	}
+}
+
+func TestFakeRecordsTypedRequestsAsClientParams(t *testing.T) {
+	f := New()
+	_, err := f.EvaluatePayment(&identitymind.PaymentTransaction{
+		TransactionRequest: identitymind.TransactionRequest{
+			AccountName: "alice",
+			Amount:      "12345678901234567.89",
+			Currency:    "USD",
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	txs := f.Transactions()
+	if len(txs) != 1 {
+		t.Fatalf("expected 1 transaction; got %d", len(txs))
+	}
+	if amount, _ := txs[0].Params["amt"].(string); amount != "12345678901234567.89" {
+		t.Fatalf("expected the amount to be recorded unchanged; got %v", txs[0].Params["amt"])
+	}
+	if _, ok := txs[0].Params["profile"]; ok {
+		t.Fatal("expected omitted fields not to be recorded")
+	}
+}
+
+func TestPolicyResultOfUnknownState(t *testing.T) {
+	f := New()
+	f.Enqueue(Outcome{State: identitymind.ApplicationState("X")})
+	result, err := f.EvaluateFraud(map[string]interface{}{"man": "alice", "amt": json.Number("1")})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.FRP == nil || *result.FRP != identitymind.PolicyResult("") {
+		t.Fatalf("expected an empty policy result for an unknown state; got %v", result.FRP)
+	}
+}
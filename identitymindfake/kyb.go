@@ -0,0 +1,186 @@
+package identitymindfake
+
+import (
+	"context"
+	"fmt"
+	"io"
+
+	identitymind "github.com/kthomas/identitymind-golang"
+	"github.com/kthomas/identitymind-golang/internal/apiparams"
+)
+
+// GetBusinessApplication implements identitymind.KYB
+func (f *Fake) GetBusinessApplication(applicationID string) (*identitymind.BusinessApplication, error) {
+	return f.GetBusinessApplicationWithContext(context.Background(), applicationID)
+}
+
+// GetBusinessApplicationWithContext implements identitymind.KYB
+func (f *Fake) GetBusinessApplicationWithContext(ctx context.Context, applicationID string) (*identitymind.BusinessApplication, error) {
+	app, err := f.get(ctx, applicationID)
+	if err != nil {
+		return nil, err
+	}
+	return businessEvaluation(app), nil
+}
+
+// ReevaluateBusinessApplication implements identitymind.KYB
+func (f *Fake) ReevaluateBusinessApplication(applicationID string) (*identitymind.BusinessApplication, error) {
+	return f.ReevaluateBusinessApplicationWithContext(context.Background(), applicationID)
+}
+
+// ReevaluateBusinessApplicationWithContext implements identitymind.KYB
+func (f *Fake) ReevaluateBusinessApplicationWithContext(ctx context.Context, applicationID string) (*identitymind.BusinessApplication, error) {
+	app, err := f.get(ctx, applicationID)
+	if err != nil {
+		return nil, err
+	}
+	return businessEvaluation(app), nil
+}
+
+// SubmitBusinessApplication implements identitymind.KYB
+func (f *Fake) SubmitBusinessApplication(params map[string]interface{}) (*identitymind.BusinessApplication, error) {
+	return f.SubmitBusinessApplicationWithContext(context.Background(), params)
+}
+
+// SubmitBusinessApplicationWithContext implements identitymind.KYB
+func (f *Fake) SubmitBusinessApplicationWithContext(ctx context.Context, params map[string]interface{}) (*identitymind.BusinessApplication, error) {
+	app, err := f.submit(ctx, ApplicationKindBusiness, "", params)
+	if err != nil {
+		return nil, err
+	}
+	return businessEvaluation(app), nil
+}
+
+// SubmitKYBApplication implements identitymind.KYB
+func (f *Fake) SubmitKYBApplication(application *identitymind.BusinessApplicationRequest) (*identitymind.BusinessApplication, error) {
+	return f.SubmitKYBApplicationWithContext(context.Background(), application)
+}
+
+// SubmitKYBApplicationWithContext implements identitymind.KYB
+func (f *Fake) SubmitKYBApplicationWithContext(ctx context.Context, application *identitymind.BusinessApplicationRequest) (*identitymind.BusinessApplication, error) {
+	if application == nil {
+		return nil, fmt.Errorf("Failed to submit KYB application; application is required")
+	}
+	if err := application.Validate(); err != nil {
+		return nil, fmt.Errorf("Failed to submit KYB application; %s", err.Error())
+	}
+	params, err := apiparams.Marshal(application)
+	if err != nil {
+		return nil, err
+	}
+	return f.SubmitBusinessApplicationWithContext(ctx, params)
+}
+
+// ListBusinessApplicationDocuments implements identitymind.KYB
+func (f *Fake) ListBusinessApplicationDocuments(applicationID string) ([]*identitymind.DocumentInfo, error) {
+	return f.ListBusinessApplicationDocumentsWithContext(context.Background(), applicationID)
+}
+
+// ListBusinessApplicationDocumentsWithContext implements identitymind.KYB
+func (f *Fake) ListBusinessApplicationDocumentsWithContext(ctx context.Context, applicationID string) ([]*identitymind.DocumentInfo, error) {
+	return f.listDocuments(ctx, applicationID)
+}
+
+// DownloadBusinessApplicationDocument implements identitymind.KYB
+func (f *Fake) DownloadBusinessApplicationDocument(applicationID, documentID string) (interface{}, error) {
+	return f.DownloadBusinessApplicationDocumentWithContext(context.Background(), applicationID, documentID)
+}
+
+// DownloadBusinessApplicationDocumentWithContext implements identitymind.KYB
+func (f *Fake) DownloadBusinessApplicationDocumentWithContext(ctx context.Context, applicationID, documentID string) (interface{}, error) {
+	return f.downloadDocument(ctx, applicationID, documentID)
+}
+
+// DownloadBusinessApplicationDocumentTo implements identitymind.KYB
+func (f *Fake) DownloadBusinessApplicationDocumentTo(applicationID, documentID string, w io.Writer) (*identitymind.DocumentDownload, error) {
+	return f.DownloadBusinessApplicationDocumentToWithContext(context.Background(), applicationID, documentID, w)
+}
+
+// DownloadBusinessApplicationDocumentToWithContext implements identitymind.KYB
+func (f *Fake) DownloadBusinessApplicationDocumentToWithContext(ctx context.Context, applicationID, documentID string, w io.Writer) (*identitymind.DocumentDownload, error) {
+	return f.downloadDocumentTo(ctx, applicationID, documentID, w)
+}
+
+// UploadBusinessApplicationDocument implements identitymind.KYB
+func (f *Fake) UploadBusinessApplicationDocument(applicationID string, params map[string]interface{}) (interface{}, error) {
+	return f.UploadBusinessApplicationDocumentWithContext(context.Background(), applicationID, params)
+}
+
+// UploadBusinessApplicationDocumentWithContext implements identitymind.KYB
+func (f *Fake) UploadBusinessApplicationDocumentWithContext(ctx context.Context, applicationID string, params map[string]interface{}) (interface{}, error) {
+	return f.upload(ctx, applicationID, false, params)
+}
+
+// UploadBusinessApplicationDocumentStream implements identitymind.KYB
+func (f *Fake) UploadBusinessApplicationDocumentStream(applicationID string, upload *identitymind.DocumentUpload) (interface{}, error) {
+	return f.UploadBusinessApplicationDocumentStreamWithContext(context.Background(), applicationID, upload)
+}
+
+// UploadBusinessApplicationDocumentStreamWithContext implements identitymind.KYB
+func (f *Fake) UploadBusinessApplicationDocumentStreamWithContext(ctx context.Context, applicationID string, upload *identitymind.DocumentUpload) (interface{}, error) {
+	return f.uploadStream(ctx, applicationID, false, upload)
+}
+
+// UploadBusinessApplicationDocumentVerificationImage implements identitymind.KYB
+func (f *Fake) UploadBusinessApplicationDocumentVerificationImage(applicationID string, params map[string]interface{}) (interface{}, error) {
+	return f.UploadBusinessApplicationDocumentVerificationImageWithContext(context.Background(), applicationID, params)
+}
+
+// UploadBusinessApplicationDocumentVerificationImageWithContext implements identitymind.KYB
+func (f *Fake) UploadBusinessApplicationDocumentVerificationImageWithContext(ctx context.Context, applicationID string, params map[string]interface{}) (interface{}, error) {
+	return f.upload(ctx, applicationID, true, params)
+}
+
+// UploadBusinessApplicationDocumentVerificationImageStream implements identitymind.KYB
+func (f *Fake) UploadBusinessApplicationDocumentVerificationImageStream(applicationID string, upload *identitymind.DocumentUpload) (interface{}, error) {
+	return f.UploadBusinessApplicationDocumentVerificationImageStreamWithContext(context.Background(), applicationID, upload)
+}
+
+// UploadBusinessApplicationDocumentVerificationImageStreamWithContext implements identitymind.KYB
+func (f *Fake) UploadBusinessApplicationDocumentVerificationImageStreamWithContext(ctx context.Context, applicationID string, upload *identitymind.DocumentUpload) (interface{}, error) {
+	return f.uploadStream(ctx, applicationID, true, upload)
+}
+
+// ApproveBusinessApplication implements identitymind.KYB
+func (f *Fake) ApproveBusinessApplication(applicationID string, params map[string]interface{}) (interface{}, error) {
+	return f.ApproveBusinessApplicationWithContext(context.Background(), applicationID, params)
+}
+
+// ApproveBusinessApplicationWithContext implements identitymind.KYB
+func (f *Fake) ApproveBusinessApplicationWithContext(ctx context.Context, applicationID string, params map[string]interface{}) (interface{}, error) {
+	return f.feedback(ctx, applicationID, identitymind.ApplicationStateAccepted, params)
+}
+
+// RejectBusinessApplication implements identitymind.KYB
+func (f *Fake) RejectBusinessApplication(applicationID string, params map[string]interface{}) (interface{}, error) {
+	return f.RejectBusinessApplicationWithContext(context.Background(), applicationID, params)
+}
+
+// RejectBusinessApplicationWithContext implements identitymind.KYB
+func (f *Fake) RejectBusinessApplicationWithContext(ctx context.Context, applicationID string, params map[string]interface{}) (interface{}, error) {
+	return f.feedback(ctx, applicationID, identitymind.ApplicationStateRejected, params)
+}
+
+// UndecideBusinessApplication implements identitymind.KYB
+func (f *Fake) UndecideBusinessApplication(applicationID string, params map[string]interface{}) (interface{}, error) {
+	return f.UndecideBusinessApplicationWithContext(context.Background(), applicationID, params)
+}
+
+// UndecideBusinessApplicationWithContext implements identitymind.KYB
+func (f *Fake) UndecideBusinessApplicationWithContext(ctx context.Context, applicationID string, params map[string]interface{}) (interface{}, error) {
+	return f.feedback(ctx, applicationID, identitymind.ApplicationStateUnderReview, params)
+}
+
+// WaitForBusinessApplicationDecision implements identitymind.KYB; it returns as soon as the application
+// is decided, i.e. via Decide, without polling
+func (f *Fake) WaitForBusinessApplicationDecision(ctx context.Context, applicationID string, opts *identitymind.PollOptions) (*identitymind.BusinessApplication, error) {
+	app, err := f.waitForDecision(ctx, applicationID, func(transition identitymind.StateTransition, app *Application) {
+		if opts != nil && opts.OnBusinessTransition != nil {
+			opts.OnBusinessTransition(transition, businessEvaluation(app))
+		}
+	})
+	if app == nil {
+		return nil, err
+	}
+	return businessEvaluation(app), err
+}
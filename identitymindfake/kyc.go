@@ -0,0 +1,179 @@
+package identitymindfake
+
+import (
+	"context"
+	"fmt"
+	"io"
+
+	identitymind "github.com/kthomas/identitymind-golang"
+	"github.com/kthomas/identitymind-golang/internal/apiparams"
+)
+
+// GetApplication implements identitymind.KYC
+func (f *Fake) GetApplication(applicationID string) (*identitymind.KYCApplication, error) {
+	return f.GetApplicationWithContext(context.Background(), applicationID)
+}
+
+// GetApplicationWithContext implements identitymind.KYC
+func (f *Fake) GetApplicationWithContext(ctx context.Context, applicationID string) (*identitymind.KYCApplication, error) {
+	app, err := f.get(ctx, applicationID)
+	if err != nil {
+		return nil, err
+	}
+	return evaluation(app), nil
+}
+
+// SubmitApplication implements identitymind.KYC
+func (f *Fake) SubmitApplication(params map[string]interface{}) (*identitymind.KYCApplication, error) {
+	return f.SubmitApplicationWithContext(context.Background(), params)
+}
+
+// SubmitApplicationWithContext implements identitymind.KYC
+func (f *Fake) SubmitApplicationWithContext(ctx context.Context, params map[string]interface{}) (*identitymind.KYCApplication, error) {
+	app, err := f.submit(ctx, ApplicationKindConsumer, "", params)
+	if err != nil {
+		return nil, err
+	}
+	return evaluation(app), nil
+}
+
+// SubmitConsumerApplication implements identitymind.KYC
+func (f *Fake) SubmitConsumerApplication(application *identitymind.ConsumerApplicationRequest) (*identitymind.KYCApplication, error) {
+	return f.SubmitConsumerApplicationWithContext(context.Background(), application)
+}
+
+// SubmitConsumerApplicationWithContext implements identitymind.KYC
+func (f *Fake) SubmitConsumerApplicationWithContext(ctx context.Context, application *identitymind.ConsumerApplicationRequest) (*identitymind.KYCApplication, error) {
+	if application == nil || application.AccountName == "" {
+		return nil, fmt.Errorf("Failed to submit consumer KYC application; account name (man) is required")
+	}
+	params, err := apiparams.Marshal(application)
+	if err != nil {
+		return nil, err
+	}
+	return f.SubmitApplicationWithContext(ctx, params)
+}
+
+// ProvideApplicationResponse implements identitymind.KYC
+func (f *Fake) ProvideApplicationResponse(applicationID string, params map[string]interface{}) (interface{}, error) {
+	return f.ProvideApplicationResponseWithContext(context.Background(), applicationID, params)
+}
+
+// ProvideApplicationResponseWithContext implements identitymind.KYC
+func (f *Fake) ProvideApplicationResponseWithContext(ctx context.Context, applicationID string, params map[string]interface{}) (interface{}, error) {
+	return f.respond(ctx, applicationID, params)
+}
+
+// ListApplicationDocuments implements identitymind.KYC
+func (f *Fake) ListApplicationDocuments(applicationID string) ([]*identitymind.DocumentInfo, error) {
+	return f.ListApplicationDocumentsWithContext(context.Background(), applicationID)
+}
+
+// ListApplicationDocumentsWithContext implements identitymind.KYC
+func (f *Fake) ListApplicationDocumentsWithContext(ctx context.Context, applicationID string) ([]*identitymind.DocumentInfo, error) {
+	return f.listDocuments(ctx, applicationID)
+}
+
+// DownloadApplicationDocument implements identitymind.KYC
+func (f *Fake) DownloadApplicationDocument(applicationID, documentID string) (interface{}, error) {
+	return f.DownloadApplicationDocumentWithContext(context.Background(), applicationID, documentID)
+}
+
+// DownloadApplicationDocumentWithContext implements identitymind.KYC
+func (f *Fake) DownloadApplicationDocumentWithContext(ctx context.Context, applicationID, documentID string) (interface{}, error) {
+	return f.downloadDocument(ctx, applicationID, documentID)
+}
+
+// DownloadApplicationDocumentTo implements identitymind.KYC
+func (f *Fake) DownloadApplicationDocumentTo(applicationID, documentID string, w io.Writer) (*identitymind.DocumentDownload, error) {
+	return f.DownloadApplicationDocumentToWithContext(context.Background(), applicationID, documentID, w)
+}
+
+// DownloadApplicationDocumentToWithContext implements identitymind.KYC
+func (f *Fake) DownloadApplicationDocumentToWithContext(ctx context.Context, applicationID, documentID string, w io.Writer) (*identitymind.DocumentDownload, error) {
+	return f.downloadDocumentTo(ctx, applicationID, documentID, w)
+}
+
+// UploadApplicationDocument implements identitymind.KYC
+func (f *Fake) UploadApplicationDocument(applicationID string, params map[string]interface{}) (interface{}, error) {
+	return f.UploadApplicationDocumentWithContext(context.Background(), applicationID, params)
+}
+
+// UploadApplicationDocumentWithContext implements identitymind.KYC
+func (f *Fake) UploadApplicationDocumentWithContext(ctx context.Context, applicationID string, params map[string]interface{}) (interface{}, error) {
+	return f.upload(ctx, applicationID, false, params)
+}
+
+// UploadApplicationDocumentStream implements identitymind.KYC
+func (f *Fake) UploadApplicationDocumentStream(applicationID string, upload *identitymind.DocumentUpload) (interface{}, error) {
+	return f.UploadApplicationDocumentStreamWithContext(context.Background(), applicationID, upload)
+}
+
+// UploadApplicationDocumentStreamWithContext implements identitymind.KYC
+func (f *Fake) UploadApplicationDocumentStreamWithContext(ctx context.Context, applicationID string, upload *identitymind.DocumentUpload) (interface{}, error) {
+	return f.uploadStream(ctx, applicationID, false, upload)
+}
+
+// UploadApplicationDocumentVerificationImage implements identitymind.KYC
+func (f *Fake) UploadApplicationDocumentVerificationImage(applicationID string, params map[string]interface{}) (interface{}, error) {
+	return f.UploadApplicationDocumentVerificationImageWithContext(context.Background(), applicationID, params)
+}
+
+// UploadApplicationDocumentVerificationImageWithContext implements identitymind.KYC
+func (f *Fake) UploadApplicationDocumentVerificationImageWithContext(ctx context.Context, applicationID string, params map[string]interface{}) (interface{}, error) {
+	return f.upload(ctx, applicationID, true, params)
+}
+
+// UploadApplicationDocumentVerificationImageStream implements identitymind.KYC
+func (f *Fake) UploadApplicationDocumentVerificationImageStream(applicationID string, upload *identitymind.DocumentUpload) (interface{}, error) {
+	return f.UploadApplicationDocumentVerificationImageStreamWithContext(context.Background(), applicationID, upload)
+}
+
+// UploadApplicationDocumentVerificationImageStreamWithContext implements identitymind.KYC
+func (f *Fake) UploadApplicationDocumentVerificationImageStreamWithContext(ctx context.Context, applicationID string, upload *identitymind.DocumentUpload) (interface{}, error) {
+	return f.uploadStream(ctx, applicationID, true, upload)
+}
+
+// ApproveApplication implements identitymind.KYC
+func (f *Fake) ApproveApplication(applicationID string, params map[string]interface{}) (interface{}, error) {
+	return f.ApproveApplicationWithContext(context.Background(), applicationID, params)
+}
+
+// ApproveApplicationWithContext implements identitymind.KYC
+func (f *Fake) ApproveApplicationWithContext(ctx context.Context, applicationID string, params map[string]interface{}) (interface{}, error) {
+	return f.feedback(ctx, applicationID, identitymind.ApplicationStateAccepted, params)
+}
+
+// RejectApplication implements identitymind.KYC
+func (f *Fake) RejectApplication(applicationID string, params map[string]interface{}) (interface{}, error) {
+	return f.RejectApplicationWithContext(context.Background(), applicationID, params)
+}
+
+// RejectApplicationWithContext implements identitymind.KYC
+func (f *Fake) RejectApplicationWithContext(ctx context.Context, applicationID string, params map[string]interface{}) (interface{}, error) {
+	return f.feedback(ctx, applicationID, identitymind.ApplicationStateRejected, params)
+}
+
+// UndecideApplication implements identitymind.KYC
+func (f *Fake) UndecideApplication(applicationID string, params map[string]interface{}) (interface{}, error) {
+	return f.UndecideApplicationWithContext(context.Background(), applicationID, params)
+}
+
+// UndecideApplicationWithContext implements identitymind.KYC
+func (f *Fake) UndecideApplicationWithContext(ctx context.Context, applicationID string, params map[string]interface{}) (interface{}, error) {
+	return f.feedback(ctx, applicationID, identitymind.ApplicationStateUnderReview, params)
+}
+
+// WaitForApplicationDecision implements identitymind.KYC; it returns as soon as the application
+// is decided, i.e. via Decide, without polling
+func (f *Fake) WaitForApplicationDecision(ctx context.Context, applicationID string, opts *identitymind.PollOptions) (*identitymind.KYCApplication, error) {
+	app, err := f.waitForDecision(ctx, applicationID, func(transition identitymind.StateTransition, app *Application) {
+		if opts != nil && opts.OnTransition != nil {
+			opts.OnTransition(transition, evaluation(app))
+		}
+	})
+	if app == nil {
+		return nil, err
+	}
+	return evaluation(app), err
+}
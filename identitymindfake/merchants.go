@@ -0,0 +1,440 @@
+package identitymindfake
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+
+	identitymind "github.com/kthomas/identitymind-golang"
+	"github.com/kthomas/identitymind-golang/internal/apiparams"
+	"github.com/kthomas/identitymind-golang/internal/testdouble"
+)
+
+// CreateMerchant implements identitymind.Merchants
+func (f *Fake) CreateMerchant(params map[string]interface{}) (interface{}, error) {
+	return f.CreateMerchantWithContext(context.Background(), params)
+}
+
+// CreateMerchantWithContext implements identitymind.Merchants
+func (f *Fake) CreateMerchantWithContext(ctx context.Context, params map[string]interface{}) (interface{}, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	merchant, err := testdouble.CreateMerchant(f.merchants, params, func() string { return f.nextID("merchant") })
+	if err != nil {
+		return nil, &identitymind.APIError{
+			StatusCode: http.StatusConflict,
+			Method:     http.MethodPost,
+			Endpoint:   "merchant",
+			Message:    err.Error(),
+		}
+	}
+	return merchant, nil
+}
+
+// GetMerchant implements identitymind.Merchants
+func (f *Fake) GetMerchant(merchantID string, params map[string]interface{}) (interface{}, error) {
+	return f.GetMerchantWithContext(context.Background(), merchantID, params)
+}
+
+// GetMerchantWithContext implements identitymind.Merchants
+func (f *Fake) GetMerchantWithContext(ctx context.Context, merchantID string, params map[string]interface{}) (interface{}, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	merchant, ok := f.merchants[merchantID]
+	if !ok {
+		return nil, notFound("merchant", merchantID)
+	}
+	return apiparams.Copy(merchant), nil
+}
+
+// UpdateMerchant implements identitymind.Merchants
+func (f *Fake) UpdateMerchant(merchantID string, params map[string]interface{}) (interface{}, error) {
+	return f.UpdateMerchantWithContext(context.Background(), merchantID, params)
+}
+
+// UpdateMerchantWithContext implements identitymind.Merchants
+func (f *Fake) UpdateMerchantWithContext(ctx context.Context, merchantID string, params map[string]interface{}) (interface{}, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	merchant, ok := f.merchants[merchantID]
+	if !ok {
+		return nil, notFound("merchant", merchantID)
+	}
+	merchant, err := testdouble.UpdateMerchant(merchant, params)
+	if err != nil {
+		return nil, &identitymind.APIError{
+			StatusCode: http.StatusBadRequest,
+			Method:     http.MethodPost,
+			Endpoint:   fmt.Sprintf("merchant/%s", merchantID),
+			Message:    err.Error(),
+		}
+	}
+	return merchant, nil
+}
+
+// GetMerchantApplication implements identitymind.Merchants
+func (f *Fake) GetMerchantApplication(applicationID string) (interface{}, error) {
+	return f.GetMerchantApplicationWithContext(context.Background(), applicationID)
+}
+
+// GetMerchantApplicationWithContext implements identitymind.Merchants
+func (f *Fake) GetMerchantApplicationWithContext(ctx context.Context, applicationID string) (interface{}, error) {
+	app, err := f.get(ctx, applicationID)
+	if err != nil {
+		return nil, err
+	}
+	return evaluationParams(app), nil
+}
+
+// SubmitMerchantApplication implements identitymind.Merchants
+func (f *Fake) SubmitMerchantApplication(params map[string]interface{}) (interface{}, error) {
+	return f.SubmitMerchantApplicationWithContext(context.Background(), params)
+}
+
+// SubmitMerchantApplicationWithContext implements identitymind.Merchants
+func (f *Fake) SubmitMerchantApplicationWithContext(ctx context.Context, params map[string]interface{}) (interface{}, error) {
+	merchantID, _ := params["m"].(string)
+	app, err := f.submit(ctx, ApplicationKindMerchant, merchantID, params)
+	if err != nil {
+		return nil, err
+	}
+	return evaluation(app), nil
+}
+
+// ListMerchantApplicationDocuments implements identitymind.Merchants
+func (f *Fake) ListMerchantApplicationDocuments(applicationID string) ([]*identitymind.DocumentInfo, error) {
+	return f.ListMerchantApplicationDocumentsWithContext(context.Background(), applicationID)
+}
+
+// ListMerchantApplicationDocumentsWithContext implements identitymind.Merchants
+func (f *Fake) ListMerchantApplicationDocumentsWithContext(ctx context.Context, applicationID string) ([]*identitymind.DocumentInfo, error) {
+	return f.listDocuments(ctx, applicationID)
+}
+
+// DownloadMerchantApplicationDocument implements identitymind.Merchants
+func (f *Fake) DownloadMerchantApplicationDocument(applicationID, documentID string) (interface{}, error) {
+	return f.DownloadMerchantApplicationDocumentWithContext(context.Background(), applicationID, documentID)
+}
+
+// DownloadMerchantApplicationDocumentWithContext implements identitymind.Merchants
+func (f *Fake) DownloadMerchantApplicationDocumentWithContext(ctx context.Context, applicationID, documentID string) (interface{}, error) {
+	return f.downloadDocument(ctx, applicationID, documentID)
+}
+
+// DownloadMerchantApplicationDocumentTo implements identitymind.Merchants
+func (f *Fake) DownloadMerchantApplicationDocumentTo(applicationID, documentID string, w io.Writer) (*identitymind.DocumentDownload, error) {
+	return f.DownloadMerchantApplicationDocumentToWithContext(context.Background(), applicationID, documentID, w)
+}
+
+// DownloadMerchantApplicationDocumentToWithContext implements identitymind.Merchants
+func (f *Fake) DownloadMerchantApplicationDocumentToWithContext(ctx context.Context, applicationID, documentID string, w io.Writer) (*identitymind.DocumentDownload, error) {
+	return f.downloadDocumentTo(ctx, applicationID, documentID, w)
+}
+
+// UploadMerchantApplicationDocument implements identitymind.Merchants
+func (f *Fake) UploadMerchantApplicationDocument(applicationID string, params map[string]interface{}) (interface{}, error) {
+	return f.UploadMerchantApplicationDocumentWithContext(context.Background(), applicationID, params)
+}
+
+// UploadMerchantApplicationDocumentWithContext implements identitymind.Merchants
+func (f *Fake) UploadMerchantApplicationDocumentWithContext(ctx context.Context, applicationID string, params map[string]interface{}) (interface{}, error) {
+	return f.upload(ctx, applicationID, false, params)
+}
+
+// UploadMerchantApplicationDocumentStream implements identitymind.Merchants
+func (f *Fake) UploadMerchantApplicationDocumentStream(applicationID string, upload *identitymind.DocumentUpload) (interface{}, error) {
+	return f.UploadMerchantApplicationDocumentStreamWithContext(context.Background(), applicationID, upload)
+}
+
+// UploadMerchantApplicationDocumentStreamWithContext implements identitymind.Merchants
+func (f *Fake) UploadMerchantApplicationDocumentStreamWithContext(ctx context.Context, applicationID string, upload *identitymind.DocumentUpload) (interface{}, error) {
+	return f.uploadStream(ctx, applicationID, false, upload)
+}
+
+// UploadMerchantApplicationDocumentVerificationImage implements identitymind.Merchants
+func (f *Fake) UploadMerchantApplicationDocumentVerificationImage(applicationID string, params map[string]interface{}) (interface{}, error) {
+	return f.UploadMerchantApplicationDocumentVerificationImageWithContext(context.Background(), applicationID, params)
+}
+
+// UploadMerchantApplicationDocumentVerificationImageWithContext implements identitymind.Merchants
+func (f *Fake) UploadMerchantApplicationDocumentVerificationImageWithContext(ctx context.Context, applicationID string, params map[string]interface{}) (interface{}, error) {
+	return f.upload(ctx, applicationID, true, params)
+}
+
+// UploadMerchantApplicationDocumentVerificationImageStream implements identitymind.Merchants
+func (f *Fake) UploadMerchantApplicationDocumentVerificationImageStream(applicationID string, upload *identitymind.DocumentUpload) (interface{}, error) {
+	return f.UploadMerchantApplicationDocumentVerificationImageStreamWithContext(context.Background(), applicationID, upload)
+}
+
+// UploadMerchantApplicationDocumentVerificationImageStreamWithContext implements identitymind.Merchants
+func (f *Fake) UploadMerchantApplicationDocumentVerificationImageStreamWithContext(ctx context.Context, applicationID string, upload *identitymind.DocumentUpload) (interface{}, error) {
+	return f.uploadStream(ctx, applicationID, true, upload)
+}
+
+// ApproveMerchantApplication implements identitymind.Merchants
+func (f *Fake) ApproveMerchantApplication(applicationID string, params map[string]interface{}) (interface{}, error) {
+	return f.ApproveMerchantApplicationWithContext(context.Background(), applicationID, params)
+}
+
+// ApproveMerchantApplicationWithContext implements identitymind.Merchants
+func (f *Fake) ApproveMerchantApplicationWithContext(ctx context.Context, applicationID string, params map[string]interface{}) (interface{}, error) {
+	return f.feedback(ctx, applicationID, identitymind.ApplicationStateAccepted, params)
+}
+
+// RejectMerchantApplication implements identitymind.Merchants
+func (f *Fake) RejectMerchantApplication(applicationID string, params map[string]interface{}) (interface{}, error) {
+	return f.RejectMerchantApplicationWithContext(context.Background(), applicationID, params)
+}
+
+// RejectMerchantApplicationWithContext implements identitymind.Merchants
+func (f *Fake) RejectMerchantApplicationWithContext(ctx context.Context, applicationID string, params map[string]interface{}) (interface{}, error) {
+	return f.feedback(ctx, applicationID, identitymind.ApplicationStateRejected, params)
+}
+
+// UndecideMerchantApplication implements identitymind.Merchants
+func (f *Fake) UndecideMerchantApplication(applicationID string, params map[string]interface{}) (interface{}, error) {
+	return f.UndecideMerchantApplicationWithContext(context.Background(), applicationID, params)
+}
+
+// UndecideMerchantApplicationWithContext implements identitymind.Merchants
+func (f *Fake) UndecideMerchantApplicationWithContext(ctx context.Context, applicationID string, params map[string]interface{}) (interface{}, error) {
+	return f.feedback(ctx, applicationID, identitymind.ApplicationStateUnderReview, params)
+}
+
+// ProvideMerchantApplicationResponse implements identitymind.Merchants
+func (f *Fake) ProvideMerchantApplicationResponse(applicationID string, params map[string]interface{}) (interface{}, error) {
+	return f.ProvideMerchantApplicationResponseWithContext(context.Background(), applicationID, params)
+}
+
+// ProvideMerchantApplicationResponseWithContext implements identitymind.Merchants
+func (f *Fake) ProvideMerchantApplicationResponseWithContext(ctx context.Context, applicationID string, params map[string]interface{}) (interface{}, error) {
+	return f.respond(ctx, applicationID, params)
+}
+
+// RejectMerchantBusinessApplication implements identitymind.Merchants
+func (f *Fake) RejectMerchantBusinessApplication(applicationID string, params map[string]interface{}) (interface{}, error) {
+	return f.RejectMerchantBusinessApplicationWithContext(context.Background(), applicationID, params)
+}
+
+// RejectMerchantBusinessApplicationWithContext implements identitymind.Merchants
+func (f *Fake) RejectMerchantBusinessApplicationWithContext(ctx context.Context, applicationID string, params map[string]interface{}) (interface{}, error) {
+	return f.feedback(ctx, applicationID, identitymind.ApplicationStateRejected, params)
+}
+
+// UndecideMerchantBusinessApplication implements identitymind.Merchants
+func (f *Fake) UndecideMerchantBusinessApplication(applicationID string, params map[string]interface{}) (interface{}, error) {
+	return f.UndecideMerchantBusinessApplicationWithContext(context.Background(), applicationID, params)
+}
+
+// UndecideMerchantBusinessApplicationWithContext implements identitymind.Merchants
+func (f *Fake) UndecideMerchantBusinessApplicationWithContext(ctx context.Context, applicationID string, params map[string]interface{}) (interface{}, error) {
+	return f.feedback(ctx, applicationID, identitymind.ApplicationStateUnderReview, params)
+}
+
+// GetMerchantBusinessApplication implements identitymind.Merchants
+func (f *Fake) GetMerchantBusinessApplication(applicationID string) (*identitymind.BusinessApplication, error) {
+	return f.GetMerchantBusinessApplicationWithContext(context.Background(), applicationID)
+}
+
+// GetMerchantBusinessApplicationWithContext implements identitymind.Merchants
+func (f *Fake) GetMerchantBusinessApplicationWithContext(ctx context.Context, applicationID string) (*identitymind.BusinessApplication, error) {
+	app, err := f.get(ctx, applicationID)
+	if err != nil {
+		return nil, err
+	}
+	return businessEvaluation(app), nil
+}
+
+// ReevaluateMerchantBusinessApplication implements identitymind.Merchants
+func (f *Fake) ReevaluateMerchantBusinessApplication(applicationID string) (*identitymind.BusinessApplication, error) {
+	return f.ReevaluateMerchantBusinessApplicationWithContext(context.Background(), applicationID)
+}
+
+// ReevaluateMerchantBusinessApplicationWithContext implements identitymind.Merchants
+func (f *Fake) ReevaluateMerchantBusinessApplicationWithContext(ctx context.Context, applicationID string) (*identitymind.BusinessApplication, error) {
+	app, err := f.get(ctx, applicationID)
+	if err != nil {
+		return nil, err
+	}
+	return businessEvaluation(app), nil
+}
+
+// SubmitMerchantBusinessApplication implements identitymind.Merchants
+func (f *Fake) SubmitMerchantBusinessApplication(merchantID string, params map[string]interface{}) (*identitymind.BusinessApplication, error) {
+	return f.SubmitMerchantBusinessApplicationWithContext(context.Background(), merchantID, params)
+}
+
+// SubmitMerchantBusinessApplicationWithContext implements identitymind.Merchants
+func (f *Fake) SubmitMerchantBusinessApplicationWithContext(ctx context.Context, merchantID string, params map[string]interface{}) (*identitymind.BusinessApplication, error) {
+	params = apiparams.Copy(params)
+	params["m"] = merchantID
+	app, err := f.submit(ctx, ApplicationKindMerchantBusiness, merchantID, params)
+	if err != nil {
+		return nil, err
+	}
+	return businessEvaluation(app), nil
+}
+
+// SubmitMerchantKYBApplication implements identitymind.Merchants
+func (f *Fake) SubmitMerchantKYBApplication(merchantID string, application *identitymind.BusinessApplicationRequest) (*identitymind.BusinessApplication, error) {
+	return f.SubmitMerchantKYBApplicationWithContext(context.Background(), merchantID, application)
+}
+
+// SubmitMerchantKYBApplicationWithContext implements identitymind.Merchants
+func (f *Fake) SubmitMerchantKYBApplicationWithContext(ctx context.Context, merchantID string, application *identitymind.BusinessApplicationRequest) (*identitymind.BusinessApplication, error) {
+	if application == nil {
+		return nil, fmt.Errorf("Failed to submit merchant KYB application; application is required")
+	}
+	if err := application.Validate(); err != nil {
+		return nil, fmt.Errorf("Failed to submit merchant KYB application; %s", err.Error())
+	}
+	params, err := apiparams.Marshal(application)
+	if err != nil {
+		return nil, err
+	}
+	return f.SubmitMerchantBusinessApplicationWithContext(ctx, merchantID, params)
+}
+
+// ListMerchantBusinessApplicationDocuments implements identitymind.Merchants
+func (f *Fake) ListMerchantBusinessApplicationDocuments(applicationID string) ([]*identitymind.DocumentInfo, error) {
+	return f.ListMerchantBusinessApplicationDocumentsWithContext(context.Background(), applicationID)
+}
+
+// ListMerchantBusinessApplicationDocumentsWithContext implements identitymind.Merchants
+func (f *Fake) ListMerchantBusinessApplicationDocumentsWithContext(ctx context.Context, applicationID string) ([]*identitymind.DocumentInfo, error) {
+	return f.listDocuments(ctx, applicationID)
+}
+
+// DownloadMerchantBusinessApplicationDocument implements identitymind.Merchants
+func (f *Fake) DownloadMerchantBusinessApplicationDocument(applicationID, documentID string) (interface{}, error) {
+	return f.DownloadMerchantBusinessApplicationDocumentWithContext(context.Background(), applicationID, documentID)
+}
+
+// DownloadMerchantBusinessApplicationDocumentWithContext implements identitymind.Merchants
+func (f *Fake) DownloadMerchantBusinessApplicationDocumentWithContext(ctx context.Context, applicationID, documentID string) (interface{}, error) {
+	return f.downloadDocument(ctx, applicationID, documentID)
+}
+
+// DownloadMerchantBusinessApplicationDocumentTo implements identitymind.Merchants
+func (f *Fake) DownloadMerchantBusinessApplicationDocumentTo(applicationID, documentID string, w io.Writer) (*identitymind.DocumentDownload, error) {
+	return f.DownloadMerchantBusinessApplicationDocumentToWithContext(context.Background(), applicationID, documentID, w)
+}
+
+// DownloadMerchantBusinessApplicationDocumentToWithContext implements identitymind.Merchants
+func (f *Fake) DownloadMerchantBusinessApplicationDocumentToWithContext(ctx context.Context, applicationID, documentID string, w io.Writer) (*identitymind.DocumentDownload, error) {
+	return f.downloadDocumentTo(ctx, applicationID, documentID, w)
+}
+
+// UploadMerchantBusinessApplicationDocument implements identitymind.Merchants
+func (f *Fake) UploadMerchantBusinessApplicationDocument(applicationID string, params map[string]interface{}) (interface{}, error) {
+	return f.UploadMerchantBusinessApplicationDocumentWithContext(context.Background(), applicationID, params)
+}
+
+// UploadMerchantBusinessApplicationDocumentWithContext implements identitymind.Merchants
+func (f *Fake) UploadMerchantBusinessApplicationDocumentWithContext(ctx context.Context, applicationID string, params map[string]interface{}) (interface{}, error) {
+	return f.upload(ctx, applicationID, false, params)
+}
+
+// UploadMerchantBusinessApplicationDocumentStream implements identitymind.Merchants
+func (f *Fake) UploadMerchantBusinessApplicationDocumentStream(applicationID string, upload *identitymind.DocumentUpload) (interface{}, error) {
+	return f.UploadMerchantBusinessApplicationDocumentStreamWithContext(context.Background(), applicationID, upload)
+}
+
+// UploadMerchantBusinessApplicationDocumentStreamWithContext implements identitymind.Merchants
+func (f *Fake) UploadMerchantBusinessApplicationDocumentStreamWithContext(ctx context.Context, applicationID string, upload *identitymind.DocumentUpload) (interface{}, error) {
+	return f.uploadStream(ctx, applicationID, false, upload)
+}
+
+// UploadMerchantBusinessApplicationDocumentVerificationImage implements identitymind.Merchants
+func (f *Fake) UploadMerchantBusinessApplicationDocumentVerificationImage(applicationID string, params map[string]interface{}) (interface{}, error) {
+	return f.UploadMerchantBusinessApplicationDocumentVerificationImageWithContext(context.Background(), applicationID, params)
+}
+
+// UploadMerchantBusinessApplicationDocumentVerificationImageWithContext implements identitymind.Merchants
+func (f *Fake) UploadMerchantBusinessApplicationDocumentVerificationImageWithContext(ctx context.Context, applicationID string, params map[string]interface{}) (interface{}, error) {
+	return f.upload(ctx, applicationID, true, params)
+}
+
+// UploadMerchantBusinessApplicationDocumentVerificationImageStream implements identitymind.Merchants
+func (f *Fake) UploadMerchantBusinessApplicationDocumentVerificationImageStream(applicationID string, upload *identitymind.DocumentUpload) (interface{}, error) {
+	return f.UploadMerchantBusinessApplicationDocumentVerificationImageStreamWithContext(context.Background(), applicationID, upload)
+}
+
+// UploadMerchantBusinessApplicationDocumentVerificationImageStreamWithContext implements identitymind.Merchants
+func (f *Fake) UploadMerchantBusinessApplicationDocumentVerificationImageStreamWithContext(ctx context.Context, applicationID string, upload *identitymind.DocumentUpload) (interface{}, error) {
+	return f.uploadStream(ctx, applicationID, true, upload)
+}
+
+// ApproveMerchantBusinessApplication implements identitymind.Merchants
+func (f *Fake) ApproveMerchantBusinessApplication(applicationID string, params map[string]interface{}) (interface{}, error) {
+	return f.ApproveMerchantBusinessApplicationWithContext(context.Background(), applicationID, params)
+}
+
+// ApproveMerchantBusinessApplicationWithContext implements identitymind.Merchants
+func (f *Fake) ApproveMerchantBusinessApplicationWithContext(ctx context.Context, applicationID string, params map[string]interface{}) (interface{}, error) {
+	return f.feedback(ctx, applicationID, identitymind.ApplicationStateAccepted, params)
+}
+
+// EvaluateMerchantFraud implements identitymind.Merchants
+func (f *Fake) EvaluateMerchantFraud(merchantID string, params map[string]interface{}) (*identitymind.TransactionResult, error) {
+	return f.EvaluateMerchantFraudWithContext(context.Background(), merchantID, params)
+}
+
+// EvaluateMerchantFraudWithContext implements identitymind.Merchants
+func (f *Fake) EvaluateMerchantFraudWithContext(ctx context.Context, merchantID string, params map[string]interface{}) (*identitymind.TransactionResult, error) {
+	return f.evaluate(ctx, "", merchantID, params)
+}
+
+// EvaluateMerchantPayment implements identitymind.Merchants
+func (f *Fake) EvaluateMerchantPayment(merchantID string, payment *identitymind.PaymentTransaction) (*identitymind.TransactionResult, error) {
+	return f.EvaluateMerchantPaymentWithContext(context.Background(), merchantID, payment)
+}
+
+// EvaluateMerchantPaymentWithContext implements identitymind.Merchants
+func (f *Fake) EvaluateMerchantPaymentWithContext(ctx context.Context, merchantID string, payment *identitymind.PaymentTransaction) (*identitymind.TransactionResult, error) {
+	params, err := paymentParams(payment)
+	if err != nil {
+		return nil, err
+	}
+	return f.evaluate(ctx, "", merchantID, params)
+}
+
+// ReportMerchantTransaction implements identitymind.Merchants
+func (f *Fake) ReportMerchantTransaction(merchantID, txType string, params map[string]interface{}) (*identitymind.TransactionResult, error) {
+	return f.ReportMerchantTransactionWithContext(context.Background(), merchantID, txType, params)
+}
+
+// ReportMerchantTransactionWithContext implements identitymind.Merchants
+func (f *Fake) ReportMerchantTransactionWithContext(ctx context.Context, merchantID, txType string, params map[string]interface{}) (*identitymind.TransactionResult, error) {
+	if !identitymind.IsValidTxType(txType) {
+		return nil, fmt.Errorf("Invalid tx type provided: %s", txType)
+	}
+	return f.evaluate(ctx, txType, merchantID, params)
+}
+
+// ReportMerchantTransfer implements identitymind.Merchants
+func (f *Fake) ReportMerchantTransfer(merchantID string, transfer identitymind.TransferRequest) (*identitymind.TransactionResult, error) {
+	return f.ReportMerchantTransferWithContext(context.Background(), merchantID, transfer)
+}
+
+// ReportMerchantTransferWithContext implements identitymind.Merchants
+func (f *Fake) ReportMerchantTransferWithContext(ctx context.Context, merchantID string, transfer identitymind.TransferRequest) (*identitymind.TransactionResult, error) {
+	params, err := transferParams(transfer, f.TravelRulePolicy)
+	if err != nil {
+		return nil, err
+	}
+	return f.evaluate(ctx, transfer.TxType(), merchantID, params)
+}
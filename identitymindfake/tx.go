@@ -0,0 +1,209 @@
+package identitymindfake
+
+import (
+	"context"
+	"fmt"
+
+	identitymind "github.com/kthomas/identitymind-golang"
+	"github.com/kthomas/identitymind-golang/internal/apiparams"
+)
+
+// EvaluateFraud implements identitymind.Transactions
+func (f *Fake) EvaluateFraud(params map[string]interface{}) (*identitymind.TransactionResult, error) {
+	return f.EvaluateFraudWithContext(context.Background(), params)
+}
+
+// EvaluateFraudWithContext implements identitymind.Transactions
+func (f *Fake) EvaluateFraudWithContext(ctx context.Context, params map[string]interface{}) (*identitymind.TransactionResult, error) {
+	return f.evaluate(ctx, "", "", params)
+}
+
+// EvaluatePayment implements identitymind.Transactions
+func (f *Fake) EvaluatePayment(payment *identitymind.PaymentTransaction) (*identitymind.TransactionResult, error) {
+	return f.EvaluatePaymentWithContext(context.Background(), payment)
+}
+
+// EvaluatePaymentWithContext implements identitymind.Transactions
+func (f *Fake) EvaluatePaymentWithContext(ctx context.Context, payment *identitymind.PaymentTransaction) (*identitymind.TransactionResult, error) {
+	params, err := paymentParams(payment)
+	if err != nil {
+		return nil, err
+	}
+	return f.evaluate(ctx, "", "", params)
+}
+
+// ReportFraud implements identitymind.Transactions
+func (f *Fake) ReportFraud(params map[string]interface{}) (interface{}, error) {
+	return f.ReportFraudWithContext(context.Background(), params)
+}
+
+// ReportFraudWithContext implements identitymind.Transactions
+func (f *Fake) ReportFraudWithContext(ctx context.Context, params map[string]interface{}) (interface{}, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	f.fraudReports = append(f.fraudReports, apiparams.Copy(params))
+	return map[string]interface{}{}, nil
+}
+
+// ReportTransaction implements identitymind.Transactions
+func (f *Fake) ReportTransaction(txType string, params map[string]interface{}) (*identitymind.TransactionResult, error) {
+	return f.ReportTransactionWithContext(context.Background(), txType, params)
+}
+
+// ReportTransactionWithContext implements identitymind.Transactions
+func (f *Fake) ReportTransactionWithContext(ctx context.Context, txType string, params map[string]interface{}) (*identitymind.TransactionResult, error) {
+	if !identitymind.IsValidTxType(txType) {
+		return nil, fmt.Errorf("Invalid tx type provided: %s", txType)
+	}
+	return f.evaluate(ctx, txType, "", params)
+}
+
+// ReportTransfer implements identitymind.Transactions
+func (f *Fake) ReportTransfer(transfer identitymind.TransferRequest) (*identitymind.TransactionResult, error) {
+	return f.ReportTransferWithContext(context.Background(), transfer)
+}
+
+// ReportTransferWithContext implements identitymind.Transactions
+func (f *Fake) ReportTransferWithContext(ctx context.Context, transfer identitymind.TransferRequest) (*identitymind.TransactionResult, error) {
+	params, err := transferParams(transfer, f.TravelRulePolicy)
+	if err != nil {
+		return nil, err
+	}
+	return f.evaluate(ctx, transfer.TxType(), "", params)
+}
+
+func (f *Fake) evaluate(ctx context.Context, txType, merchantID string, params map[string]interface{}) (*identitymind.TransactionResult, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	accountName, _ := params["man"].(string)
+	outcome := f.nextOutcome(accountName)
+	if outcome.Err != nil {
+		return nil, outcome.Err
+	}
+
+	id, _ := params["tid"].(string)
+	if id == "" {
+		id = f.nextID("tx")
+	}
+	tx := &Transaction{
+		ID:         id,
+		Type:       txType,
+		MerchantID: merchantID,
+		Params:     apiparams.Copy(params),
+		Result:     outcome.State.PolicyResult(),
+	}
+	f.transactions = append(f.transactions, tx)
+	result := tx.Result
+	return &identitymind.TransactionResult{
+		TID: &tx.ID,
+		FRP: &result,
+		RES: &result,
+	}, nil
+}
+
+// paymentParams validates the payment and converts it to params, as the client does
+func paymentParams(payment *identitymind.PaymentTransaction) (map[string]interface{}, error) {
+	if payment == nil {
+		return nil, fmt.Errorf("Failed to evaluate payment; payment is required")
+	}
+	if err := payment.Validate(); err != nil {
+		return nil, fmt.Errorf("Failed to evaluate payment; %s", err.Error())
+	}
+	return apiparams.Marshal(payment)
+}
+
+// transferParams validates the transfer, including its travel-rule information when a policy is given, and
+// converts it to params, as the client does
+func transferParams(transfer identitymind.TransferRequest, policy *identitymind.TravelRulePolicy) (map[string]interface{}, error) {
+	if transfer == nil {
+		return nil, fmt.Errorf("Failed to report transfer; transfer is required")
+	}
+	if err := transfer.Validate(); err != nil {
+		return nil, fmt.Errorf("Failed to report %s tx; %s", transfer.TxType(), err.Error())
+	}
+	if travelRuleTransfer, ok := transfer.(identitymind.TravelRuleTransfer); ok && policy != nil {
+		if err := travelRuleTransfer.ValidateTravelRule(policy); err != nil {
+			return nil, fmt.Errorf("Failed to report %s tx; %s", transfer.TxType(), err.Error())
+		}
+	}
+	return apiparams.Marshal(transfer)
+}
+
+// AcceptTransaction implements identitymind.Transactions
+func (f *Fake) AcceptTransaction(transactionID string, feedback *identitymind.TransactionFeedback) (interface{}, error) {
+	return f.AcceptTransactionWithContext(context.Background(), transactionID, feedback)
+}
+
+// AcceptTransactionWithContext implements identitymind.Transactions
+func (f *Fake) AcceptTransactionWithContext(ctx context.Context, transactionID string, feedback *identitymind.TransactionFeedback) (interface{}, error) {
+	return f.transactionFeedback(ctx, transactionID, identitymind.TransactionFeedbackTypeAccepted, feedback)
+}
+
+// RejectTransaction implements identitymind.Transactions
+func (f *Fake) RejectTransaction(transactionID string, feedback *identitymind.TransactionFeedback) (interface{}, error) {
+	return f.RejectTransactionWithContext(context.Background(), transactionID, feedback)
+}
+
+// RejectTransactionWithContext implements identitymind.Transactions
+func (f *Fake) RejectTransactionWithContext(ctx context.Context, transactionID string, feedback *identitymind.TransactionFeedback) (interface{}, error) {
+	return f.transactionFeedback(ctx, transactionID, identitymind.TransactionFeedbackTypeRejected, feedback)
+}
+
+// ReportTransactionFraud implements identitymind.Transactions
+func (f *Fake) ReportTransactionFraud(transactionID string, feedback *identitymind.TransactionFeedback) (interface{}, error) {
+	return f.ReportTransactionFraudWithContext(context.Background(), transactionID, feedback)
+}
+
+// ReportTransactionFraudWithContext implements identitymind.Transactions
+func (f *Fake) ReportTransactionFraudWithContext(ctx context.Context, transactionID string, feedback *identitymind.TransactionFeedback) (interface{}, error) {
+	return f.transactionFeedback(ctx, transactionID, identitymind.TransactionFeedbackTypeFraud, feedback)
+}
+
+// ReportTransactionChargeback implements identitymind.Transactions
+func (f *Fake) ReportTransactionChargeback(transactionID string, feedback *identitymind.TransactionFeedback) (interface{}, error) {
+	return f.ReportTransactionChargebackWithContext(context.Background(), transactionID, feedback)
+}
+
+// ReportTransactionChargebackWithContext implements identitymind.Transactions
+func (f *Fake) ReportTransactionChargebackWithContext(ctx context.Context, transactionID string, feedback *identitymind.TransactionFeedback) (interface{}, error) {
+	return f.transactionFeedback(ctx, transactionID, identitymind.TransactionFeedbackTypeChargeback, feedback)
+}
+
+func (f *Fake) transactionFeedback(ctx context.Context, transactionID string, feedbackType identitymind.TransactionFeedbackType, feedback *identitymind.TransactionFeedback) (interface{}, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	if transactionID == "" {
+		return nil, fmt.Errorf("Failed to provide %s feedback for tx; transaction id is required", feedbackType)
+	}
+	params, err := feedback.Params(feedbackType)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to provide %s feedback for tx %s; %s", feedbackType, transactionID, err.Error())
+	}
+
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	for _, tx := range f.transactions {
+		if tx.ID == transactionID {
+			tx.Feedback = append(tx.Feedback, &TransactionFeedback{
+				Type:   feedbackType,
+				Params: params,
+			})
+			return map[string]interface{}{
+				"tid":      tx.ID,
+				"feedback": string(feedbackType),
+			}, nil
+		}
+	}
+	return nil, notFound("transaction", transactionID)
+}
@@ -0,0 +1,397 @@
+package identitymindtest
+
+import (
+	"bytes"
+	"compress/gzip"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"mime"
+	"mime/multipart"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// redacted replaces scrubbed values in recorded fixtures
+const redacted = "[REDACTED]"
+
+// DefaultScrubbedFields are the identitymind request and response fields which carry credentials
+// or personally identifiable information, and are therefore redacted from recorded fixtures; generic
+// keys such as name, which also label non-sensitive values, are not scrubbed unless configured
+// using WithScrubbedFields
+var DefaultScrubbedFields = []string{
+	"man", "tea", "bfn", "bmn", "bln", "dob", "assn", "bsn", "bc", "bs", "bz", "bnbh",
+	"sfn", "sln", "ssn", "sc", "ss", "sz", "phn", "pm", "ip", "dfp",
+	"scanData", "backsideImageData", "faceImages", "password", "token",
+	"pccn", "pach", "pcct",
+}
+
+// Mode determines whether a Recorder records or replays interactions
+type Mode int
+
+const (
+	// ModeReplay serves previously recorded interactions and fails requests which were not recorded
+	ModeReplay Mode = iota
+	// ModeRecord sends requests using the underlying transport and records the interactions
+	ModeRecord
+	// ModeAuto replays if the fixture file exists and records otherwise
+	ModeAuto
+)
+
+// Interaction is a recorded request/response pair
+type Interaction struct {
+	Request  RecordedRequest  `json:"request"`
+	Response RecordedResponse `json:"response"`
+}
+
+// RecordedRequest is the scrubbed request of a recorded interaction
+type RecordedRequest struct {
+	Method      string `json:"method"`
+	Path        string `json:"path"`
+	Query       string `json:"query,omitempty"`
+	ContentType string `json:"content_type,omitempty"`
+	Body        string `json:"body,omitempty"` // normalized, scrubbed body
+}
+
+// RecordedResponse is the scrubbed response of a recorded interaction
+type RecordedResponse struct {
+	StatusCode int         `json:"status_code"`
+	Header     http.Header `json:"header,omitempty"`
+	Body       string      `json:"body,omitempty"`
+}
+
+// Cassette is the fixture file format written and read by a Recorder
+type Cassette struct {
+	Interactions []*Interaction `json:"interactions"`
+}
+
+// RecorderOption configures a Recorder
+type RecorderOption func(*Recorder)
+
+// WithRecorderTransport sends requests using the given transport when recording; defaults to http.DefaultTransport
+func WithRecorderTransport(transport http.RoundTripper) RecorderOption {
+	return func(r *Recorder) {
+		r.transport = transport
+	}
+}
+
+// WithScrubbedFields redacts the given JSON and form fields, in addition to DefaultScrubbedFields
+func WithScrubbedFields(fields ...string) RecorderOption {
+	return func(r *Recorder) {
+		for _, field := range fields {
+			r.scrubbed[field] = true
+		}
+	}
+}
+
+// Recorder is an http.RoundTripper which records identitymind API interactions to a fixture file,
+// with credentials and PII scrubbed, or replays previously recorded interactions; recorded requests
+// are matched on method, path, normalized query and normalized body. Use it with identitymind.WithTransport.
+type Recorder struct {
+	path      string
+	mode      Mode
+	transport http.RoundTripper
+	scrubbed  map[string]bool
+
+	mutex    sync.Mutex
+	cassette *Cassette
+	used     []bool
+}
+
+// NewRecorder initializes a Recorder for the fixture file at the given path
+func NewRecorder(path string, mode Mode, opts ...RecorderOption) (*Recorder, error) {
+	r := &Recorder{
+		path:      path,
+		mode:      mode,
+		transport: http.DefaultTransport,
+		scrubbed:  map[string]bool{},
+		cassette:  &Cassette{Interactions: make([]*Interaction, 0)},
+	}
+	for _, field := range DefaultScrubbedFields {
+		r.scrubbed[field] = true
+	}
+	for _, opt := range opts {
+		opt(r)
+	}
+
+	if r.mode == ModeAuto {
+		if _, err := os.Stat(path); err == nil {
+			r.mode = ModeReplay
+		} else {
+			r.mode = ModeRecord
+		}
+	}
+
+	if r.mode == ModeReplay {
+		raw, err := ioutil.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("Failed to read cassette: %s; %s", path, err.Error())
+		}
+		err = json.Unmarshal(raw, r.cassette)
+		if err != nil {
+			return nil, fmt.Errorf("Failed to parse cassette: %s; %s", path, err.Error())
+		}
+		r.used = make([]bool, len(r.cassette.Interactions))
+	}
+
+	return r, nil
+}
+
+// Mode returns the effective mode of the recorder
+func (r *Recorder) Mode() Mode {
+	return r.mode
+}
+
+// RoundTrip implements http.RoundTripper
+func (r *Recorder) RoundTrip(req *http.Request) (*http.Response, error) {
+	var body []byte
+	if req.Body != nil {
+		var err error
+		body, err = ioutil.ReadAll(req.Body)
+		req.Body.Close()
+		if err != nil {
+			return nil, err
+		}
+		req.Body = ioutil.NopCloser(bytes.NewReader(body))
+	}
+
+	recorded := RecordedRequest{
+		Method:      req.Method,
+		Path:        req.URL.Path,
+		Query:       r.scrubQuery(req.URL.RawQuery),
+		ContentType: req.Header.Get("Content-Type"),
+		Body:        r.normalize(req.Header.Get("Content-Type"), body),
+	}
+
+	if r.mode == ModeReplay {
+		return r.replay(req, &recorded)
+	}
+	return r.record(req, &recorded)
+}
+
+// Stop writes the recorded interactions to the fixture file; it is a no-op when replaying
+func (r *Recorder) Stop() error {
+	if r.mode != ModeRecord {
+		return nil
+	}
+
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	raw, err := json.MarshalIndent(r.cassette, "", "  ")
+	if err != nil {
+		return err
+	}
+	err = os.MkdirAll(filepath.Dir(r.path), 0755)
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile(r.path, raw, 0644)
+}
+
+func (r *Recorder) record(req *http.Request, recorded *RecordedRequest) (*http.Response, error) {
+	resp, err := r.transport.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	respBody, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	if resp.Header.Get("Content-Encoding") == "gzip" {
+		reader, err := gzip.NewReader(bytes.NewReader(respBody))
+		if err != nil {
+			return nil, err
+		}
+		respBody, err = ioutil.ReadAll(reader)
+		if err != nil {
+			return nil, err
+		}
+		resp.Header.Del("Content-Encoding")
+		resp.Header.Del("Content-Length")
+	}
+
+	header := resp.Header.Clone()
+	header.Del("Set-Cookie")
+	header.Del("Date")
+	header.Del("Content-Length")
+
+	r.mutex.Lock()
+	r.cassette.Interactions = append(r.cassette.Interactions, &Interaction{
+		Request: *recorded,
+		Response: RecordedResponse{
+			StatusCode: resp.StatusCode,
+			Header:     header,
+			Body:       r.scrubResponse(resp.Header.Get("Content-Type"), respBody),
+		},
+	})
+	r.mutex.Unlock()
+
+	return newResponse(req, resp.StatusCode, resp.Header, respBody), nil
+}
+
+func (r *Recorder) replay(req *http.Request, recorded *RecordedRequest) (*http.Response, error) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	match := -1
+	for idx, interaction := range r.cassette.Interactions {
+		if interaction.Request.Method != recorded.Method || interaction.Request.Path != recorded.Path || interaction.Request.Query != recorded.Query || interaction.Request.Body != recorded.Body {
+			continue
+		}
+		match = idx
+		if !r.used[idx] {
+			break
+		}
+	}
+	if match == -1 {
+		target := recorded.Path
+		if recorded.Query != "" {
+			target = fmt.Sprintf("%s?%s", recorded.Path, recorded.Query)
+		}
+		return nil, fmt.Errorf("No recorded interaction in cassette %s matches %s %s", r.path, recorded.Method, target)
+	}
+
+	r.used[match] = true
+	interaction := r.cassette.Interactions[match]
+	return newResponse(req, interaction.Response.StatusCode, interaction.Response.Header.Clone(), []byte(interaction.Response.Body)), nil
+}
+
+// normalize returns a canonical, scrubbed representation of the given request body
+func (r *Recorder) normalize(contentType string, body []byte) string {
+	if len(body) == 0 {
+		return ""
+	}
+
+	mediaType, params, _ := mime.ParseMediaType(contentType)
+	switch {
+	case mediaType == "application/json":
+		var v interface{}
+		if err := json.Unmarshal(body, &v); err == nil {
+			raw, _ := json.Marshal(r.scrub(v))
+			return string(raw)
+		}
+	case mediaType == "application/x-www-form-urlencoded":
+		if vals, err := url.ParseQuery(string(body)); err == nil {
+			r.scrubValues(vals)
+			return vals.Encode()
+		}
+	case strings.HasPrefix(mediaType, "multipart/"):
+		parts := make([]string, 0)
+		reader := multipart.NewReader(bytes.NewReader(body), params["boundary"])
+		for {
+			part, err := reader.NextPart()
+			if err != nil {
+				break
+			}
+			data, _ := ioutil.ReadAll(part)
+			digest := sha256.Sum256(data)
+			value := hex.EncodeToString(digest[:])
+			if part.FileName() == "" && !r.scrubbed[part.FormName()] {
+				value = string(data)
+			}
+			parts = append(parts, fmt.Sprintf("%s;%s;%s", part.FormName(), part.FileName(), value))
+		}
+		sort.Strings(parts)
+		return strings.Join(parts, "\n")
+	}
+
+	digest := sha256.Sum256(body)
+	return hex.EncodeToString(digest[:])
+}
+
+// scrubQuery redacts scrubbed fields in the given raw query string
+func (r *Recorder) scrubQuery(query string) string {
+	if query == "" {
+		return ""
+	}
+	vals, err := url.ParseQuery(query)
+	if err != nil {
+		digest := sha256.Sum256([]byte(query))
+		return hex.EncodeToString(digest[:])
+	}
+	r.scrubValues(vals)
+	return vals.Encode()
+}
+
+// scrubValues redacts scrubbed fields in the given form or query values
+func (r *Recorder) scrubValues(vals url.Values) {
+	for key := range vals {
+		if r.scrubbed[key] {
+			vals[key] = []string{redacted}
+		}
+	}
+}
+
+func (r *Recorder) scrubResponse(contentType string, body []byte) string {
+	mediaType, _, _ := mime.ParseMediaType(contentType)
+	if mediaType == "application/json" || mediaType == "" {
+		var v interface{}
+		if err := json.Unmarshal(body, &v); err == nil {
+			raw, _ := json.Marshal(r.scrub(v))
+			return string(raw)
+		}
+	}
+	return string(body)
+}
+
+// scrub recursively redacts scrubbed fields in the given decoded JSON value
+func (r *Recorder) scrub(v interface{}) interface{} {
+	switch val := v.(type) {
+	case map[string]interface{}:
+		for key, child := range val {
+			if r.scrubbed[key] {
+				val[key] = r.redact(child)
+			} else {
+				val[key] = r.scrub(child)
+			}
+		}
+	case []interface{}:
+		for idx, child := range val {
+			val[idx] = r.scrub(child)
+		}
+	}
+	return v
+}
+
+// redact replaces the scalar values within the given decoded JSON value; nested objects are scrubbed
+// rather than replaced, since short identitymind field codes, i.e. sc, are reused within response objects
+func (r *Recorder) redact(v interface{}) interface{} {
+	switch val := v.(type) {
+	case map[string]interface{}:
+		return r.scrub(val)
+	case []interface{}:
+		for idx, child := range val {
+			val[idx] = r.redact(child)
+		}
+		return val
+	case nil:
+		return nil
+	}
+	return redacted
+}
+
+func newResponse(req *http.Request, status int, header http.Header, body []byte) *http.Response {
+	if header == nil {
+		header = http.Header{}
+	}
+	return &http.Response{
+		Status:        fmt.Sprintf("%d %s", status, http.StatusText(status)),
+		StatusCode:    status,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        header,
+		Body:          ioutil.NopCloser(bytes.NewReader(body)),
+		ContentLength: int64(len(body)),
+		Request:       req,
+	}
+}
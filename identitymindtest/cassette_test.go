@@ -0,0 +1,158 @@
+package identitymindtest
+
+import (
+	"bytes"
+	"encoding/json"
+	"io/ioutil"
+	"mime/multipart"
+	"net/http"
+	"path/filepath"
+	"testing"
+)
+
+// newTestRecorder initializes a recording Recorder which is never stopped, so no fixture is written
+func newTestRecorder(t *testing.T) *Recorder {
+	r, err := NewRecorder(filepath.Join("testdata", "unused.json"), ModeRecord)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return r
+}
+
+func TestRecorderScrub(t *testing.T) {
+	tests := []struct {
+		name     string
+		fields   []string
+		input    string
+		expected string
+	}{
+		{"scalar", nil, `{"man":"alice","amt":"10"}`, `{"amt":"10","man":"[REDACTED]"}`},
+		{"nested", nil, `{"ednaScoreCard":{"bfn":"Alice"}}`, `{"ednaScoreCard":{"bfn":"[REDACTED]"}}`},
+		{"array", nil, `{"faceImages":["a","b"]}`, `{"faceImages":["[REDACTED]","[REDACTED]"]}`},
+		{"null", nil, `{"ssn":null}`, `{"ssn":null}`},
+		{"payment instrument", nil, `{"pccn":"4111111111111111","pach":"123","pcct":"tok"}`, `{"pach":"[REDACTED]","pccn":"[REDACTED]","pcct":"[REDACTED]"}`},
+		{"generic keys", nil, `{"name":"Acme Inc","account":"merchant","address":"1 Main St"}`, `{"account":"merchant","address":"1 Main St","name":"Acme Inc"}`},
+		{"configured field", []string{"address"}, `{"address":"1 Main St","name":"Acme Inc"}`, `{"address":"[REDACTED]","name":"Acme Inc"}`},
+		{"object value", []string{"address"}, `{"address":{"street":"1 Main St","sc":"US"}}`, `{"address":{"sc":"[REDACTED]","street":"1 Main St"}}`},
+		{"object in array value", []string{"address"}, `{"address":[{"bfn":"Alice"}]}`, `{"address":[{"bfn":"[REDACTED]"}]}`},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			r, err := NewRecorder(filepath.Join("testdata", "unused.json"), ModeRecord, WithScrubbedFields(test.fields...))
+			if err != nil {
+				t.Fatal(err)
+			}
+			var v interface{}
+			if err := json.Unmarshal([]byte(test.input), &v); err != nil {
+				t.Fatal(err)
+			}
+			raw, _ := json.Marshal(r.scrub(v))
+			if string(raw) != test.expected {
+				t.Fatalf("expected %s; got %s", test.expected, raw)
+			}
+		})
+	}
+}
+
+func TestRecorderNormalize(t *testing.T) {
+	var multipartBody bytes.Buffer
+	writer := multipart.NewWriter(&multipartBody)
+	writer.WriteField("description", "passport")
+	writer.WriteField("ssn", "123")
+	part, _ := writer.CreateFormFile("file", "passport.png")
+	part.Write([]byte("image"))
+	writer.Close()
+
+	tests := []struct {
+		name        string
+		contentType string
+		body        string
+		expected    string
+	}{
+		{"empty", "application/json", "", ""},
+		{"json", "application/json; charset=utf-8", `{"tea":"a@example.com", "amt":"10"}`, `{"amt":"10","tea":"[REDACTED]"}`},
+		{"form", "application/x-www-form-urlencoded", "man=alice&amt=10", "amt=10&man=%5BREDACTED%5D"},
+		{"multipart", writer.FormDataContentType(), multipartBody.String(), "description;;passport\n" +
+			"file;passport.png;6105d6cc76af400325e94d588ce511be5bfdbb73b437dc51eca43917d7a43e3d\n" +
+			"ssn;;a665a45920422f9d417e4867efdc4fb8a04a1f3fff1fa07e998e86f7f7a27ae3"},
+		{"opaque", "application/octet-stream", "abc", "ba7816bf8f01cfea414140de5dae2223b00361a396177a9cb410ff61f20015ad"},
+	}
+	r := newTestRecorder(t)
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			normalized := r.normalize(test.contentType, []byte(test.body))
+			if normalized != test.expected {
+				t.Fatalf("expected %q; got %q", test.expected, normalized)
+			}
+		})
+	}
+}
+
+func TestRecorderScrubsQuery(t *testing.T) {
+	r := newTestRecorder(t)
+	req, _ := http.NewRequest(http.MethodGet, "http://example.com/im/account/consumer?man=alice&limit=1", nil)
+	r.transport = roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+		return newResponse(req, http.StatusOK, http.Header{"Content-Type": []string{"application/json"}}, []byte(`{}`)), nil
+	})
+	_, err := r.RoundTrip(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if query := r.cassette.Interactions[0].Request.Query; query != "limit=1&man=%5BREDACTED%5D" {
+		t.Fatalf("expected the query to be scrubbed; got %s", query)
+	}
+}
+
+func TestRecorderReplayMatchesQuery(t *testing.T) {
+	r := &Recorder{
+		path: filepath.Join("testdata", "unused.json"),
+		mode: ModeReplay,
+		cassette: &Cassette{Interactions: []*Interaction{
+			{
+				Request:  RecordedRequest{Method: http.MethodGet, Path: "/im/account/consumer", Query: "limit=1&man=%5BREDACTED%5D"},
+				Response: RecordedResponse{StatusCode: http.StatusOK, Body: `{"page":1}`},
+			},
+			{
+				Request:  RecordedRequest{Method: http.MethodGet, Path: "/im/account/consumer", Query: "limit=1&man=%5BREDACTED%5D&page=2"},
+				Response: RecordedResponse{StatusCode: http.StatusOK, Body: `{"page":2}`},
+			},
+		}},
+		scrubbed: map[string]bool{"man": true},
+	}
+	r.used = make([]bool, len(r.cassette.Interactions))
+
+	tests := []struct {
+		name     string
+		url      string
+		expected string
+	}{
+		{"second page", "http://example.com/im/account/consumer?page=2&man=bob&limit=1", `{"page":2}`},
+		{"first page", "http://example.com/im/account/consumer?man=alice&limit=1", `{"page":1}`},
+		{"unrecorded query", "http://example.com/im/account/consumer?limit=1&man=alice&page=3", ""},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			req, _ := http.NewRequest(http.MethodGet, test.url, nil)
+			resp, err := r.RoundTrip(req)
+			if test.expected == "" {
+				if err == nil {
+					t.Fatal("expected no recorded interaction to match")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			body, _ := ioutil.ReadAll(resp.Body)
+			if string(body) != test.expected {
+				t.Fatalf("expected %s; got %s", test.expected, body)
+			}
+		})
+	}
+}
+
+type roundTripperFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripperFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
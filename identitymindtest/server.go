@@ -0,0 +1,902 @@
+// Package identitymindtest provides a local, stateful stand-in for the identitymind API, built on
+// httptest, for exercising the identitymind API client end-to-end without network access.
+package identitymindtest
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"mime"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"time"
+
+	identitymind "github.com/kthomas/identitymind-golang"
+	"github.com/kthomas/identitymind-golang/internal/apiparams"
+	"github.com/kthomas/identitymind-golang/internal/testdouble"
+)
+
+const maxUploadMemory = 32 << 20
+
+// Fault describes a failure injected into responses to matching requests
+type Fault struct {
+	Method          string      // HTTP method to match; empty matches any method
+	Path            string      // path prefix to match, without leading slash, i.e. im/account/consumer; empty matches any path
+	StatusCode      int         // status code of the injected response; zero defaults to 500
+	Body            string      // body of the injected response
+	Header          http.Header // headers of the injected response, i.e. Retry-After
+	Times           int         // number of matching requests to fail; zero fails every matching request
+	CloseConnection bool        // when true, the connection is closed without a response, simulating a connection reset
+}
+
+// statusCode returns the status code of the injected response
+func (f *Fault) statusCode() int {
+	if f.StatusCode == 0 {
+		return http.StatusInternalServerError
+	}
+	return f.StatusCode
+}
+
+// Request is a request received by the server
+type Request struct {
+	Method string
+	Path   string
+	Query  string
+	Header http.Header
+	Body   []byte
+}
+
+// Application is an application submitted to the server
+type Application struct {
+	ID         string
+	Kind       string // consumer or merchant
+	Params     map[string]interface{}
+	State      identitymind.ApplicationState
+	Feedback   []map[string]interface{}
+	Responses  []map[string]interface{}
+	Documents  []*Document
+	MerchantID string
+}
+
+// Document is a file uploaded to the server for an application
+type Document = testdouble.Document
+
+// Transaction is a transaction evaluated or reported to the server
+type Transaction = testdouble.Transaction
+
+// TransactionFeedback is feedback provided for a transaction via the server
+type TransactionFeedback = testdouble.TransactionFeedback
+
+// Case is a case created via the server
+type Case = testdouble.Case
+
+// CaseNote is a note recorded against a case created via the server
+type CaseNote = testdouble.CaseNote
+
+// Option configures a Server
+type Option func(*Server)
+
+// WithCredentials requires requests to carry HTTP basic authorization using the given API user and token
+func WithCredentials(username, password string) Option {
+	return func(s *Server) {
+		s.username = username
+		s.password = password
+	}
+}
+
+// WithLatency delays every response by the given duration
+func WithLatency(latency time.Duration) Option {
+	return func(s *Server) {
+		s.latency = latency
+	}
+}
+
+// WithDefaultState sets the state in which submitted applications and evaluated transactions
+// result, unless otherwise scripted; defaults to accepted
+func WithDefaultState(state identitymind.ApplicationState) Option {
+	return func(s *Server) {
+		s.defaultState = state
+	}
+}
+
+// Server is a stateful stand-in for the identitymind API
+type Server struct {
+	*httptest.Server
+
+	mutex         sync.Mutex
+	username      string
+	password      string
+	latency       time.Duration
+	defaultState  identitymind.ApplicationState
+	accountStates map[string]identitymind.ApplicationState
+	faults        []*Fault
+	seq           int
+	requests      []*Request
+	applications  map[string]*Application
+	appOrder      []string
+	merchants     map[string]map[string]interface{}
+	cases         map[string]*Case
+	caseOrder     []string
+	transactions  []*Transaction
+	fraudReports  []map[string]interface{}
+}
+
+// NewServer starts and returns a new Server; the caller should call Close when finished
+func NewServer(opts ...Option) *Server {
+	s := &Server{
+		defaultState:  identitymind.ApplicationStateAccepted,
+		accountStates: map[string]identitymind.ApplicationState{},
+		applications:  map[string]*Application{},
+		merchants:     map[string]map[string]interface{}{},
+		cases:         map[string]*Case{},
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
+	s.Server = httptest.NewServer(http.HandlerFunc(s.serveHTTP))
+	return s
+}
+
+// Client returns an identitymind API client configured to target the server using the server
+// credentials, if any; additional options are applied after the defaults
+func (s *Server) Client(opts ...identitymind.Option) (*identitymind.IdentityMindAPIClient, error) {
+	s.mutex.Lock()
+	defaults := []identitymind.Option{
+		identitymind.WithBaseURL(s.URL),
+		identitymind.WithHTTPClient(s.Server.Client()),
+	}
+	if s.username != "" || s.password != "" {
+		defaults = append(defaults, identitymind.WithBasicAuth(s.username, s.password))
+	}
+	s.mutex.Unlock()
+	return identitymind.NewClient(append(defaults, opts...)...)
+}
+
+// SetLatency delays every subsequent response by the given duration
+func (s *Server) SetLatency(latency time.Duration) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.latency = latency
+}
+
+// SetDefaultState sets the state in which subsequent submissions and evaluations result, unless otherwise scripted
+func (s *Server) SetDefaultState(state identitymind.ApplicationState) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.defaultState = state
+}
+
+// SetAccountState sets the state in which submissions and evaluations for the given account name (man) result
+func (s *Server) SetAccountState(accountName string, state identitymind.ApplicationState) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.accountStates[accountName] = state
+}
+
+// InjectFault fails matching requests as described by the given fault; faults are matched in the order injected
+func (s *Server) InjectFault(fault Fault) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	f := fault
+	s.faults = append(s.faults, &f)
+}
+
+// ClearFaults removes all injected faults
+func (s *Server) ClearFaults() {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.faults = nil
+}
+
+// Decide simulates a reviewer accepting or rejecting the given application, which must be under review
+func (s *Server) Decide(applicationID string, state identitymind.ApplicationState) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	app, ok := s.applications[applicationID]
+	if !ok {
+		return fmt.Errorf("application not found: %s", applicationID)
+	}
+	err := testdouble.ValidateDecision(app.State, state)
+	if err != nil {
+		return fmt.Errorf("Failed to decide application %s; %s", applicationID, err.Error())
+	}
+	app.State = state
+	return nil
+}
+
+// Requests returns the requests received by the server, in order
+func (s *Server) Requests() []*Request {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	reqs := make([]*Request, len(s.requests))
+	copy(reqs, s.requests)
+	return reqs
+}
+
+// Applications returns a snapshot of the applications submitted to the server, in order
+func (s *Server) Applications() []*Application {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	apps := make([]*Application, 0, len(s.appOrder))
+	for _, id := range s.appOrder {
+		app := *s.applications[id]
+		app.Params = apiparams.Copy(app.Params)
+		app.Documents = append([]*Document{}, app.Documents...)
+		apps = append(apps, &app)
+	}
+	return apps
+}
+
+// Cases returns a snapshot of the cases created via the server, in order
+func (s *Server) Cases() []*Case {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	cases := make([]*Case, 0, len(s.caseOrder))
+	for _, id := range s.caseOrder {
+		c := *s.cases[id]
+		c.Params = apiparams.Copy(c.Params)
+		c.Notes = append([]*CaseNote{}, c.Notes...)
+		cases = append(cases, &c)
+	}
+	return cases
+}
+
+// Transactions returns the transactions evaluated or reported to the server, in order
+func (s *Server) Transactions() []*Transaction {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	txs := make([]*Transaction, 0, len(s.transactions))
+	for _, tx := range s.transactions {
+		t := *tx
+		t.Feedback = append([]*TransactionFeedback{}, tx.Feedback...)
+		txs = append(txs, &t)
+	}
+	return txs
+}
+
+func (s *Server) serveHTTP(w http.ResponseWriter, r *http.Request) {
+	body, _ := ioutil.ReadAll(r.Body)
+	r.Body = ioutil.NopCloser(bytes.NewReader(body))
+
+	s.mutex.Lock()
+	s.requests = append(s.requests, &Request{
+		Method: r.Method,
+		Path:   r.URL.Path,
+		Query:  r.URL.RawQuery,
+		Header: r.Header.Clone(),
+		Body:   body,
+	})
+	latency := s.latency
+	fault := s.matchFault(r)
+	authorized := s.authorized(r)
+	s.mutex.Unlock()
+
+	if latency > 0 {
+		if !sleep(r.Context(), latency) {
+			return
+		}
+	}
+
+	if fault != nil {
+		if fault.CloseConnection {
+			closeConnection(w)
+			return
+		}
+		for key, vals := range fault.Header {
+			w.Header()[key] = vals
+		}
+		w.WriteHeader(fault.statusCode())
+		w.Write([]byte(fault.Body))
+		return
+	}
+
+	if !authorized {
+		writeError(w, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+
+	s.route(w, r, body)
+}
+
+// closeConnection closes the underlying connection without writing a response; when the connection
+// cannot be hijacked, i.e. over HTTP/2, the handler is aborted so the response is reset instead
+func closeConnection(w http.ResponseWriter) {
+	if hijacker, ok := w.(http.Hijacker); ok {
+		conn, _, err := hijacker.Hijack()
+		if err == nil {
+			conn.Close()
+			return
+		}
+	}
+	panic(http.ErrAbortHandler)
+}
+
+// matchFault returns the first fault matching the request, if any; the caller must hold the mutex
+func (s *Server) matchFault(r *http.Request) *Fault {
+	path := strings.TrimPrefix(r.URL.Path, "/")
+	for idx, fault := range s.faults {
+		if fault.Method != "" && !strings.EqualFold(fault.Method, r.Method) {
+			continue
+		}
+		if fault.Path != "" && !strings.HasPrefix(path, fault.Path) {
+			continue
+		}
+		if fault.Times > 0 {
+			fault.Times--
+			if fault.Times == 0 {
+				s.faults = append(s.faults[:idx], s.faults[idx+1:]...)
+			}
+		}
+		return fault
+	}
+	return nil
+}
+
+// authorized returns true if the request carries the configured credentials; the caller must hold the mutex
+func (s *Server) authorized(r *http.Request) bool {
+	if s.username == "" && s.password == "" {
+		return true
+	}
+	user, pass, ok := r.BasicAuth()
+	return ok && user == s.username && pass == s.password
+}
+
+func (s *Server) route(w http.ResponseWriter, r *http.Request, body []byte) {
+	segments := strings.Split(strings.Trim(r.URL.Path, "/"), "/")
+	if len(segments) < 2 || segments[0] != "im" {
+		writeError(w, http.StatusNotFound, "Not found")
+		return
+	}
+
+	switch {
+	case segments[1] == "account" && len(segments) >= 3 && (segments[2] == "consumer" || segments[2] == "merchant"):
+		s.routeApplication(w, r, body, segments[2], segments[3:])
+	case segments[1] == "account" && len(segments) == 3 && r.Method == http.MethodPost:
+		switch segments[2] {
+		case identitymind.IdentityMindTxTypeDeposit, identitymind.IdentityMindTxTypeWithdrawal, identitymind.IdentityMindTxTypeTransfer:
+			s.evaluateTransaction(w, segments[2], body)
+		default:
+			writeError(w, http.StatusNotFound, "Not found")
+		}
+	case segments[1] == "transaction" && len(segments) == 2 && r.Method == http.MethodPost:
+		s.evaluateTransaction(w, "", body)
+	case segments[1] == "transaction" && len(segments) == 4 && r.Method == http.MethodPost:
+		s.provideTransactionFeedback(w, segments[2], segments[3], body)
+	case segments[1] == "admin" && len(segments) >= 4 && segments[2] == "jax":
+		switch segments[3] {
+		case "case":
+			s.routeCase(w, r, body, segments[4:])
+		case "merchant":
+			s.routeMerchant(w, r, body, segments[4:])
+		case "feg":
+			s.reportFraud(w, r, body)
+		default:
+			writeError(w, http.StatusNotFound, "Not found")
+		}
+	default:
+		writeError(w, http.StatusNotFound, "Not found")
+	}
+}
+
+func (s *Server) routeApplication(w http.ResponseWriter, r *http.Request, body []byte, kind string, segments []string) {
+	if len(segments) == 2 && segments[0] == "v2" {
+		segments = segments[1:]
+	}
+
+	switch {
+	case len(segments) == 0 && r.Method == http.MethodPost:
+		s.submitApplication(w, kind, body)
+	case len(segments) == 1 && r.Method == http.MethodGet:
+		s.withApplication(w, segments[0], func(app *Application) {
+			writeJSON(w, http.StatusOK, evaluation(app))
+		})
+	case len(segments) == 1 && r.Method == http.MethodPost:
+		s.withApplication(w, segments[0], func(app *Application) {
+			writeJSON(w, http.StatusOK, evaluation(app))
+		})
+	case len(segments) == 2 && r.Method == http.MethodPost && (segments[1] == "files" || segments[1] == "dv"):
+		s.uploadDocuments(w, r, segments[0], segments[1] == "dv")
+	case len(segments) == 2 && r.Method == http.MethodPost:
+		params, err := decodeParams(body)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		switch segments[1] {
+		case "accepted", "rejected", "review":
+			state := map[string]identitymind.ApplicationState{
+				"accepted": identitymind.ApplicationStateAccepted,
+				"rejected": identitymind.ApplicationStateRejected,
+				"review":   identitymind.ApplicationStateUnderReview,
+			}[segments[1]]
+			s.withApplication(w, segments[0], func(app *Application) {
+				app.Feedback = append(app.Feedback, params)
+				app.State = state
+				writeJSON(w, http.StatusOK, evaluation(app))
+			})
+		case "quizresponse":
+			s.withApplication(w, segments[0], func(app *Application) {
+				app.Responses = append(app.Responses, params)
+				writeJSON(w, http.StatusOK, evaluation(app))
+			})
+		default:
+			writeError(w, http.StatusNotFound, "Not found")
+		}
+	case len(segments) == 2 && segments[1] == "files" && r.Method == http.MethodGet:
+		s.withApplication(w, segments[0], func(app *Application) {
+			files := make([]interface{}, 0)
+			for _, doc := range app.Documents {
+				files = append(files, documentInfo(doc))
+			}
+			writeJSON(w, http.StatusOK, files)
+		})
+	case len(segments) == 3 && segments[1] == "files" && r.Method == http.MethodGet:
+		s.withApplication(w, segments[0], func(app *Application) {
+			for _, doc := range app.Documents {
+				if doc.ID == segments[2] {
+					w.Header().Set("Content-Type", doc.ContentType)
+					w.Header().Set("Content-Disposition", mime.FormatMediaType("attachment", map[string]string{"filename": doc.Filename}))
+					w.Header().Set("Content-Length", fmt.Sprintf("%d", len(doc.Data)))
+					digest := sha256.Sum256(doc.Data)
+					w.Header().Set("Digest", fmt.Sprintf("sha-256=%s", base64.StdEncoding.EncodeToString(digest[:])))
+					w.WriteHeader(http.StatusOK)
+					w.Write(doc.Data)
+					return
+				}
+			}
+			writeError(w, http.StatusNotFound, fmt.Sprintf("document not found: %s", segments[2]))
+		})
+	default:
+		writeError(w, http.StatusNotFound, "Not found")
+	}
+}
+
+func (s *Server) submitApplication(w http.ResponseWriter, kind string, body []byte) {
+	params, err := decodeParams(body)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	accountName, _ := params["man"].(string)
+	if kind == "consumer" && accountName == "" {
+		writeError(w, http.StatusBadRequest, "man is required")
+		return
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	id, _ := params["tid"].(string)
+	if id == "" {
+		id = s.nextID("app")
+	}
+	merchantID, _ := params["m"].(string)
+	app := &Application{
+		ID:         id,
+		Kind:       kind,
+		Params:     params,
+		State:      s.stateFor(accountName),
+		MerchantID: merchantID,
+	}
+	if _, exists := s.applications[id]; !exists {
+		s.appOrder = append(s.appOrder, id)
+	}
+	s.applications[id] = app
+	writeJSON(w, http.StatusOK, evaluation(app))
+}
+
+func (s *Server) withApplication(w http.ResponseWriter, applicationID string, fn func(*Application)) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	app, ok := s.applications[applicationID]
+	if !ok {
+		writeError(w, http.StatusNotFound, fmt.Sprintf("application not found: %s", applicationID))
+		return
+	}
+	fn(app)
+}
+
+func (s *Server) uploadDocuments(w http.ResponseWriter, r *http.Request, applicationID string, verificationImage bool) {
+	err := r.ParseMultipartForm(maxUploadMemory)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	description := r.FormValue("description")
+	docs := make([]*Document, 0)
+	for field, headers := range r.MultipartForm.File {
+		for _, header := range headers {
+			file, err := header.Open()
+			if err != nil {
+				writeError(w, http.StatusBadRequest, err.Error())
+				return
+			}
+			data, err := ioutil.ReadAll(file)
+			file.Close()
+			if err != nil {
+				writeError(w, http.StatusBadRequest, err.Error())
+				return
+			}
+			contentType := header.Header.Get("Content-Type")
+			if contentType == "" {
+				contentType = http.DetectContentType(data)
+			}
+			docs = append(docs, &Document{
+				ApplicationID:     applicationID,
+				Field:             field,
+				Filename:          header.Filename,
+				ContentType:       contentType,
+				Description:       description,
+				Data:              data,
+				VerificationImage: verificationImage,
+				UploadedAt:        time.Now(),
+			})
+		}
+	}
+	if len(docs) == 0 {
+		writeError(w, http.StatusBadRequest, "no files provided")
+		return
+	}
+
+	s.withApplication(w, applicationID, func(app *Application) {
+		ids := make([]interface{}, 0)
+		for _, doc := range docs {
+			doc.ID = s.nextID("doc")
+			app.Documents = append(app.Documents, doc)
+			ids = append(ids, doc.ID)
+		}
+		writeJSON(w, http.StatusOK, map[string]interface{}{"ids": ids})
+	})
+}
+
+func (s *Server) evaluateTransaction(w http.ResponseWriter, txType string, body []byte) {
+	params, err := decodeParams(body)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	accountName, _ := params["man"].(string)
+	id, _ := params["tid"].(string)
+	if id == "" {
+		id = s.nextID("tx")
+	}
+	tx := &Transaction{
+		ID:     id,
+		Type:   txType,
+		Params: params,
+		Result: s.stateFor(accountName).PolicyResult(),
+	}
+	s.transactions = append(s.transactions, tx)
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"tid":   tx.ID,
+		"frp":   string(tx.Result),
+		"res":   string(tx.Result),
+		"rcd":   "1000",
+		"score": transactionScore(tx.Result),
+	})
+}
+
+func (s *Server) provideTransactionFeedback(w http.ResponseWriter, transactionID, feedbackType string, body []byte) {
+	switch identitymind.TransactionFeedbackType(feedbackType) {
+	case identitymind.TransactionFeedbackTypeAccepted, identitymind.TransactionFeedbackTypeRejected, identitymind.TransactionFeedbackTypeFraud, identitymind.TransactionFeedbackTypeChargeback:
+	default:
+		writeError(w, http.StatusNotFound, "Not found")
+		return
+	}
+	params, err := decodeParams(body)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	for _, tx := range s.transactions {
+		if tx.ID == transactionID {
+			tx.Feedback = append(tx.Feedback, &TransactionFeedback{
+				Type:   identitymind.TransactionFeedbackType(feedbackType),
+				Params: params,
+			})
+			writeJSON(w, http.StatusOK, map[string]interface{}{
+				"tid":      tx.ID,
+				"feedback": feedbackType,
+			})
+			return
+		}
+	}
+	writeError(w, http.StatusNotFound, fmt.Sprintf("Transaction %s not found", transactionID))
+}
+
+func (s *Server) reportFraud(w http.ResponseWriter, r *http.Request, body []byte) {
+	if r.Method != http.MethodPost {
+		writeError(w, http.StatusMethodNotAllowed, "Method not allowed")
+		return
+	}
+	params, err := decodeParams(body)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.fraudReports = append(s.fraudReports, params)
+	writeJSON(w, http.StatusOK, map[string]interface{}{})
+}
+
+func (s *Server) routeCase(w http.ResponseWriter, r *http.Request, body []byte, segments []string) {
+	var params map[string]interface{}
+	if r.Method == http.MethodPost {
+		var err error
+		params, err = decodeParams(body)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	switch {
+	case len(segments) == 0 && r.Method == http.MethodPost:
+		now := time.Now().UTC()
+		c := &Case{
+			ID:        s.nextID("case"),
+			Params:    map[string]interface{}{},
+			Status:    identitymind.CaseStatusOpen,
+			CreatedAt: now,
+		}
+		s.applyCaseParams(c, params, now)
+		s.cases[c.ID] = c
+		s.caseOrder = append(s.caseOrder, c.ID)
+		writeJSON(w, http.StatusOK, caseParams(c))
+	case len(segments) == 1 && segments[0] == "close" && r.Method == http.MethodPost:
+		caseID, _ := params["caseId"].(string)
+		s.updateCase(w, caseID, params, true)
+	case len(segments) == 1 && r.Method == http.MethodGet:
+		c, ok := s.cases[segments[0]]
+		if !ok {
+			writeError(w, http.StatusNotFound, fmt.Sprintf("case not found: %s", segments[0]))
+			return
+		}
+		writeJSON(w, http.StatusOK, caseParams(c))
+	case len(segments) == 1 && r.Method == http.MethodPost:
+		s.updateCase(w, segments[0], params, false)
+	case len(segments) == 2 && segments[1] == "close" && r.Method == http.MethodPost:
+		s.updateCase(w, segments[0], params, true)
+	default:
+		writeError(w, http.StatusNotFound, "Not found")
+	}
+}
+
+// updateCase applies the given params to a case; the caller must hold the mutex
+func (s *Server) updateCase(w http.ResponseWriter, caseID string, params map[string]interface{}, closed bool) {
+	c, ok := s.cases[caseID]
+	if !ok {
+		writeError(w, http.StatusNotFound, fmt.Sprintf("case not found: %s", caseID))
+		return
+	}
+
+	status := c.Status
+	if state, _ := params["state"].(string); state != "" {
+		status = identitymind.CaseStatus(state)
+	}
+	if closed {
+		status = identitymind.CaseStatusClosed
+	}
+	if status != c.Status {
+		err := identitymind.ValidateCaseTransition(c.Status, status)
+		if err != nil {
+			writeError(w, http.StatusConflict, err.Error())
+			return
+		}
+	} else if closed {
+		writeError(w, http.StatusConflict, fmt.Sprintf("case already closed: %s", caseID))
+		return
+	}
+
+	now := time.Now().UTC()
+	s.applyCaseParams(c, params, now)
+	if status != c.Status {
+		c.Status = status
+		c.Closed = status.IsClosed()
+		c.ClosedAt = nil
+		if c.Closed {
+			c.ClosedAt = &now
+		}
+	}
+	writeJSON(w, http.StatusOK, caseParams(c))
+}
+
+// applyCaseParams merges the given params into the case, recording any note; the status is
+// managed by the caller. The caller must hold the mutex
+func (s *Server) applyCaseParams(c *Case, params map[string]interface{}, now time.Time) {
+	for key, val := range params {
+		switch key {
+		case "caseId", "state", "note", "noteAuthor", "notes", "createdAt", "updatedAt", "closedAt":
+			continue
+		}
+		c.Params[key] = val
+	}
+	if text, _ := params["note"].(string); text != "" {
+		author, _ := params["noteAuthor"].(string)
+		note := &CaseNote{
+			ID:        s.nextID("note"),
+			Author:    author,
+			Text:      text,
+			CreatedAt: now,
+		}
+		c.Notes = append(c.Notes, note)
+	}
+	c.UpdatedAt = now
+}
+
+func (s *Server) routeMerchant(w http.ResponseWriter, r *http.Request, body []byte, segments []string) {
+	var params map[string]interface{}
+	if r.Method == http.MethodPost {
+		var err error
+		params, err = decodeParams(body)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	switch {
+	case len(segments) == 0 && r.Method == http.MethodPost:
+		merchant, err := testdouble.CreateMerchant(s.merchants, params, func() string { return s.nextID("merchant") })
+		if err != nil {
+			writeError(w, http.StatusConflict, err.Error())
+			return
+		}
+		writeJSON(w, http.StatusOK, merchant)
+	case len(segments) == 1 && (r.Method == http.MethodGet || r.Method == http.MethodPost):
+		merchant, ok := s.merchants[segments[0]]
+		if !ok {
+			writeError(w, http.StatusNotFound, fmt.Sprintf("merchant not found: %s", segments[0]))
+			return
+		}
+		if r.Method == http.MethodGet {
+			writeJSON(w, http.StatusOK, apiparams.Copy(merchant))
+			return
+		}
+		merchant, err := testdouble.UpdateMerchant(merchant, params)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		writeJSON(w, http.StatusOK, merchant)
+	case len(segments) <= 1:
+		w.Header().Set("Allow", "GET, POST")
+		writeError(w, http.StatusMethodNotAllowed, "Method not allowed")
+	default:
+		writeError(w, http.StatusNotFound, "Not found")
+	}
+}
+
+// nextID returns a new unique identifier; the caller must hold the mutex
+func (s *Server) nextID(prefix string) string {
+	s.seq++
+	return fmt.Sprintf("%s-%d", prefix, s.seq)
+}
+
+// stateFor returns the scripted state for the given account name; the caller must hold the mutex
+func (s *Server) stateFor(accountName string) identitymind.ApplicationState {
+	if state, ok := s.accountStates[accountName]; ok && accountName != "" {
+		return state
+	}
+	return s.defaultState
+}
+
+func sleep(ctx context.Context, d time.Duration) bool {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return false
+	case <-timer.C:
+		return true
+	}
+}
+
+func decodeParams(body []byte) (map[string]interface{}, error) {
+	params := map[string]interface{}{}
+	if len(bytes.TrimSpace(body)) == 0 {
+		return params, nil
+	}
+	err := json.Unmarshal(body, &params)
+	if err != nil {
+		return nil, fmt.Errorf("invalid JSON body; %s", err.Error())
+	}
+	if params == nil {
+		params = map[string]interface{}{}
+	}
+	return params, nil
+}
+
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v)
+}
+
+func writeError(w http.ResponseWriter, status int, message string) {
+	writeJSON(w, status, map[string]interface{}{"error_message": message})
+}
+
+// transactionScore returns the fraud risk score reported for a transaction with the given policy result
+func transactionScore(result identitymind.PolicyResult) float64 {
+	switch result {
+	case identitymind.PolicyResultDeny:
+		return 1
+	case identitymind.PolicyResultManualReview:
+		return 0.5
+	}
+	return 0
+}
+
+func evaluation(app *Application) map[string]interface{} {
+	result := string(app.State.PolicyResult())
+	return map[string]interface{}{
+		"mtid":  app.ID,
+		"tid":   app.ID,
+		"state": string(app.State),
+		"frp":   result,
+		"res":   result,
+		"rcd":   "1000",
+		"frn":   "Fallthrough",
+		"frd":   "Fallthrough for transaction with an unknown entity. No rules triggered.",
+		"user":  "UNKNOWN",
+		"upr":   "UNKNOWN",
+		"erd":   "Unknown User",
+		"ednaScoreCard": map[string]interface{}{
+			"sc":  []interface{}{},
+			"etr": []interface{}{},
+			"er": map[string]interface{}{
+				"profile":      "DEFAULT",
+				"reportedRule": map[string]interface{}{"name": "Fallthrough", "ruleId": 0, "resultCode": result, "testResults": []interface{}{}},
+			},
+		},
+	}
+}
+
+func documentInfo(doc *Document) map[string]interface{} {
+	return map[string]interface{}{
+		"id":          doc.ID,
+		"name":        doc.Filename,
+		"contentType": doc.ContentType,
+		"description": doc.Description,
+		"size":        len(doc.Data),
+		"uploadedAt":  doc.UploadedAt.UnixNano() / int64(time.Millisecond),
+	}
+}
+
+func caseParams(c *Case) map[string]interface{} {
+	params := apiparams.Copy(c.Params)
+	params["caseId"] = c.ID
+	params["state"] = string(c.Status)
+	params["notes"] = append([]*CaseNote{}, c.Notes...)
+	params["createdAt"] = c.CreatedAt
+	params["updatedAt"] = c.UpdatedAt
+	if c.ClosedAt != nil {
+		params["closedAt"] = *c.ClosedAt
+	}
+	return params
+}
@@ -0,0 +1,20 @@
+package identitymindtest
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestCloseConnectionWithoutHijacker(t *testing.T) {
+	w := httptest.NewRecorder()
+	defer func() {
+		if r := recover(); r != http.ErrAbortHandler {
+			t.Fatalf("expected the handler to be aborted; got %v", r)
+		}
+		if w.Code != http.StatusOK || w.Body.Len() != 0 {
+			t.Fatalf("expected no response to be written; got %d %q", w.Code, w.Body.String())
+		}
+	}()
+	closeConnection(w)
+}
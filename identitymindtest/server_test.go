@@ -0,0 +1,200 @@
+package identitymindtest_test
+
+import (
+	"errors"
+	"net/http"
+	"testing"
+
+	identitymind "github.com/kthomas/identitymind-golang"
+	"github.com/kthomas/identitymind-golang/identitymindtest"
+)
+
+func newClient(t *testing.T, srv *identitymindtest.Server) *identitymind.IdentityMindAPIClient {
+	client, err := srv.Client()
+	if err != nil {
+		t.Fatal(err)
+	}
+	return client
+}
+
+func statusOf(err error) int {
+	var apiErr *identitymind.APIError
+	if errors.As(err, &apiErr) {
+		return apiErr.StatusCode
+	}
+	return 0
+}
+
+func TestServerApplicationRoundTrip(t *testing.T) {
+	srv := identitymindtest.NewServer(identitymindtest.WithDefaultState(identitymind.ApplicationStateUnderReview))
+	defer srv.Close()
+	client := newClient(t, srv)
+
+	app, err := client.SubmitApplication(map[string]interface{}{"man": "alice"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !app.IsUnderReview() {
+		t.Fatalf("expected the application to be under review; got %s", app.CurrentState())
+	}
+
+	err = srv.Decide(*app.MTID, identitymind.ApplicationStateAccepted)
+	if err != nil {
+		t.Fatal(err)
+	}
+	app, err = client.GetApplication(*app.MTID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !app.IsAccepted() {
+		t.Fatalf("expected the application to be accepted; got %s", app.CurrentState())
+	}
+
+	_, err = client.GetApplication("missing")
+	if !identitymind.IsNotFound(err) {
+		t.Fatalf("expected a not found error; got %v", err)
+	}
+}
+
+func TestServerDecide(t *testing.T) {
+	tests := []struct {
+		name  string
+		from  identitymind.ApplicationState
+		to    identitymind.ApplicationState
+		valid bool
+	}{
+		{"accept under review", identitymind.ApplicationStateUnderReview, identitymind.ApplicationStateAccepted, true},
+		{"reject under review", identitymind.ApplicationStateUnderReview, identitymind.ApplicationStateRejected, true},
+		{"review under review", identitymind.ApplicationStateUnderReview, identitymind.ApplicationStateUnderReview, false},
+		{"unknown state", identitymind.ApplicationStateUnderReview, identitymind.ApplicationState("X"), false},
+		{"accept accepted", identitymind.ApplicationStateAccepted, identitymind.ApplicationStateAccepted, false},
+		{"reject accepted", identitymind.ApplicationStateAccepted, identitymind.ApplicationStateRejected, false},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			srv := identitymindtest.NewServer(identitymindtest.WithDefaultState(test.from))
+			defer srv.Close()
+			app, err := newClient(t, srv).SubmitApplication(map[string]interface{}{"man": "alice"})
+			if err != nil {
+				t.Fatal(err)
+			}
+			err = srv.Decide(*app.MTID, test.to)
+			if test.valid && err != nil {
+				t.Fatalf("expected the decision to be permitted; got %v", err)
+			}
+			if !test.valid && err == nil {
+				t.Fatal("expected the decision to be rejected")
+			}
+		})
+	}
+
+	srv := identitymindtest.NewServer()
+	defer srv.Close()
+	if srv.Decide("missing", identitymind.ApplicationStateAccepted) == nil {
+		t.Fatal("expected an error deciding a missing application")
+	}
+}
+
+func TestServerMerchants(t *testing.T) {
+	srv := identitymindtest.NewServer()
+	defer srv.Close()
+	client := newClient(t, srv)
+
+	_, err := client.CreateMerchant(map[string]interface{}{"m": "acme", "name": "Acme"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = client.CreateMerchant(map[string]interface{}{"m": "acme"})
+	if statusOf(err) != http.StatusConflict {
+		t.Fatalf("expected a conflict creating a duplicate merchant; got %v", err)
+	}
+
+	_, err = client.UpdateMerchant("acme", map[string]interface{}{"name": "Acme Inc"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = client.UpdateMerchant("acme", map[string]interface{}{"m": "other"})
+	if statusOf(err) != http.StatusBadRequest {
+		t.Fatalf("expected a bad request changing the merchant identifier; got %v", err)
+	}
+
+	merchant, err := client.GetMerchant("acme", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if name := merchant.(map[string]interface{})["name"]; name != "Acme Inc" {
+		t.Fatalf("expected the updated merchant name; got %v", name)
+	}
+
+	_, err = client.GetMerchant("missing", nil)
+	if !identitymind.IsNotFound(err) {
+		t.Fatalf("expected a not found error; got %v", err)
+	}
+}
+
+func TestServerTransactionFeedback(t *testing.T) {
+	srv := identitymindtest.NewServer()
+	defer srv.Close()
+	client := newClient(t, srv)
+
+	result, err := client.EvaluateFraud(map[string]interface{}{"man": "alice", "amt": "10", "ccy": "USD"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = client.ReportTransactionChargeback(*result.TID, &identitymind.TransactionFeedback{
+		Reason:   identitymind.TransactionFeedbackReasonUnauthorized,
+		Amount:   "5",
+		Currency: "USD",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	txs := srv.Transactions()
+	if len(txs) != 1 || len(txs[0].Feedback) != 1 || txs[0].Feedback[0].Type != identitymind.TransactionFeedbackTypeChargeback {
+		t.Fatalf("expected the chargeback to be recorded; got %v", txs)
+	}
+	if reason := txs[0].Feedback[0].Params["reason"]; reason != string(identitymind.TransactionFeedbackReasonUnauthorized) {
+		t.Fatalf("expected the reason to be recorded; got %v", reason)
+	}
+
+	requests := len(srv.Requests())
+	_, err = client.AcceptTransaction("", nil)
+	if err == nil {
+		t.Fatal("expected an error providing feedback without a transaction id")
+	}
+	if len(srv.Requests()) != requests {
+		t.Fatal("expected no request to be sent without a transaction id")
+	}
+}
+
+func TestServerFaults(t *testing.T) {
+	tests := []struct {
+		name   string
+		fault  identitymindtest.Fault
+		status int
+	}{
+		{"status_code", identitymindtest.Fault{StatusCode: http.StatusTooManyRequests}, http.StatusTooManyRequests},
+		{"default_status_code", identitymindtest.Fault{Body: `{"error_message":"boom"}`}, http.StatusInternalServerError},
+		{"close_connection", identitymindtest.Fault{CloseConnection: true}, 0},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			srv := identitymindtest.NewServer()
+			defer srv.Close()
+			client, err := srv.Client(identitymind.WithRetryPolicy(nil))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			srv.InjectFault(test.fault)
+			_, err = client.GetApplication("app-1")
+			if err == nil {
+				t.Fatal("expected the injected fault to fail the request")
+			}
+			if status := statusOf(err); status != test.status {
+				t.Fatalf("expected status %d; got %d (%v)", test.status, status, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,195 @@
+package identitymind
+
+import (
+	"context"
+	"io"
+)
+
+// KYC is implemented by clients of the identitymind consumer KYC API
+type KYC interface {
+	GetApplication(applicationID string) (*KYCApplication, error)
+	GetApplicationWithContext(ctx context.Context, applicationID string) (*KYCApplication, error)
+	SubmitApplication(params map[string]interface{}) (*KYCApplication, error)
+	SubmitApplicationWithContext(ctx context.Context, params map[string]interface{}) (*KYCApplication, error)
+	SubmitConsumerApplication(application *ConsumerApplicationRequest) (*KYCApplication, error)
+	SubmitConsumerApplicationWithContext(ctx context.Context, application *ConsumerApplicationRequest) (*KYCApplication, error)
+	ProvideApplicationResponse(applicationID string, params map[string]interface{}) (interface{}, error)
+	ProvideApplicationResponseWithContext(ctx context.Context, applicationID string, params map[string]interface{}) (interface{}, error)
+	ListApplicationDocuments(applicationID string) ([]*DocumentInfo, error)
+	ListApplicationDocumentsWithContext(ctx context.Context, applicationID string) ([]*DocumentInfo, error)
+	DownloadApplicationDocument(applicationID, documentID string) (interface{}, error)
+	DownloadApplicationDocumentWithContext(ctx context.Context, applicationID, documentID string) (interface{}, error)
+	DownloadApplicationDocumentTo(applicationID, documentID string, w io.Writer) (*DocumentDownload, error)
+	DownloadApplicationDocumentToWithContext(ctx context.Context, applicationID, documentID string, w io.Writer) (*DocumentDownload, error)
+	UploadApplicationDocument(applicationID string, params map[string]interface{}) (interface{}, error)
+	UploadApplicationDocumentWithContext(ctx context.Context, applicationID string, params map[string]interface{}) (interface{}, error)
+	UploadApplicationDocumentStream(applicationID string, upload *DocumentUpload) (interface{}, error)
+	UploadApplicationDocumentStreamWithContext(ctx context.Context, applicationID string, upload *DocumentUpload) (interface{}, error)
+	UploadApplicationDocumentVerificationImage(applicationID string, params map[string]interface{}) (interface{}, error)
+	UploadApplicationDocumentVerificationImageWithContext(ctx context.Context, applicationID string, params map[string]interface{}) (interface{}, error)
+	UploadApplicationDocumentVerificationImageStream(applicationID string, upload *DocumentUpload) (interface{}, error)
+	UploadApplicationDocumentVerificationImageStreamWithContext(ctx context.Context, applicationID string, upload *DocumentUpload) (interface{}, error)
+	ApproveApplication(applicationID string, params map[string]interface{}) (interface{}, error)
+	ApproveApplicationWithContext(ctx context.Context, applicationID string, params map[string]interface{}) (interface{}, error)
+	RejectApplication(applicationID string, params map[string]interface{}) (interface{}, error)
+	RejectApplicationWithContext(ctx context.Context, applicationID string, params map[string]interface{}) (interface{}, error)
+	UndecideApplication(applicationID string, params map[string]interface{}) (interface{}, error)
+	UndecideApplicationWithContext(ctx context.Context, applicationID string, params map[string]interface{}) (interface{}, error)
+	WaitForApplicationDecision(ctx context.Context, applicationID string, opts *PollOptions) (*KYCApplication, error)
+}
+
+// KYB is implemented by clients of the identitymind KYB API
+type KYB interface {
+	GetBusinessApplication(applicationID string) (*BusinessApplication, error)
+	GetBusinessApplicationWithContext(ctx context.Context, applicationID string) (*BusinessApplication, error)
+	ReevaluateBusinessApplication(applicationID string) (*BusinessApplication, error)
+	ReevaluateBusinessApplicationWithContext(ctx context.Context, applicationID string) (*BusinessApplication, error)
+	SubmitBusinessApplication(params map[string]interface{}) (*BusinessApplication, error)
+	SubmitBusinessApplicationWithContext(ctx context.Context, params map[string]interface{}) (*BusinessApplication, error)
+	SubmitKYBApplication(application *BusinessApplicationRequest) (*BusinessApplication, error)
+	SubmitKYBApplicationWithContext(ctx context.Context, application *BusinessApplicationRequest) (*BusinessApplication, error)
+	ListBusinessApplicationDocuments(applicationID string) ([]*DocumentInfo, error)
+	ListBusinessApplicationDocumentsWithContext(ctx context.Context, applicationID string) ([]*DocumentInfo, error)
+	DownloadBusinessApplicationDocument(applicationID, documentID string) (interface{}, error)
+	DownloadBusinessApplicationDocumentWithContext(ctx context.Context, applicationID, documentID string) (interface{}, error)
+	DownloadBusinessApplicationDocumentTo(applicationID, documentID string, w io.Writer) (*DocumentDownload, error)
+	DownloadBusinessApplicationDocumentToWithContext(ctx context.Context, applicationID, documentID string, w io.Writer) (*DocumentDownload, error)
+	UploadBusinessApplicationDocument(applicationID string, params map[string]interface{}) (interface{}, error)
+	UploadBusinessApplicationDocumentWithContext(ctx context.Context, applicationID string, params map[string]interface{}) (interface{}, error)
+	UploadBusinessApplicationDocumentStream(applicationID string, upload *DocumentUpload) (interface{}, error)
+	UploadBusinessApplicationDocumentStreamWithContext(ctx context.Context, applicationID string, upload *DocumentUpload) (interface{}, error)
+	UploadBusinessApplicationDocumentVerificationImage(applicationID string, params map[string]interface{}) (interface{}, error)
+	UploadBusinessApplicationDocumentVerificationImageWithContext(ctx context.Context, applicationID string, params map[string]interface{}) (interface{}, error)
+	UploadBusinessApplicationDocumentVerificationImageStream(applicationID string, upload *DocumentUpload) (interface{}, error)
+	UploadBusinessApplicationDocumentVerificationImageStreamWithContext(ctx context.Context, applicationID string, upload *DocumentUpload) (interface{}, error)
+	ApproveBusinessApplication(applicationID string, params map[string]interface{}) (interface{}, error)
+	ApproveBusinessApplicationWithContext(ctx context.Context, applicationID string, params map[string]interface{}) (interface{}, error)
+	RejectBusinessApplication(applicationID string, params map[string]interface{}) (interface{}, error)
+	RejectBusinessApplicationWithContext(ctx context.Context, applicationID string, params map[string]interface{}) (interface{}, error)
+	UndecideBusinessApplication(applicationID string, params map[string]interface{}) (interface{}, error)
+	UndecideBusinessApplicationWithContext(ctx context.Context, applicationID string, params map[string]interface{}) (interface{}, error)
+	WaitForBusinessApplicationDecision(ctx context.Context, applicationID string, opts *PollOptions) (*BusinessApplication, error)
+}
+
+// Merchants is implemented by clients of the identitymind merchant aggregation API
+type Merchants interface {
+	CreateMerchant(params map[string]interface{}) (interface{}, error)
+	CreateMerchantWithContext(ctx context.Context, params map[string]interface{}) (interface{}, error)
+	GetMerchant(merchantID string, params map[string]interface{}) (interface{}, error)
+	GetMerchantWithContext(ctx context.Context, merchantID string, params map[string]interface{}) (interface{}, error)
+	UpdateMerchant(merchantID string, params map[string]interface{}) (interface{}, error)
+	UpdateMerchantWithContext(ctx context.Context, merchantID string, params map[string]interface{}) (interface{}, error)
+	GetMerchantApplication(applicationID string) (interface{}, error)
+	GetMerchantApplicationWithContext(ctx context.Context, applicationID string) (interface{}, error)
+	SubmitMerchantApplication(params map[string]interface{}) (interface{}, error)
+	SubmitMerchantApplicationWithContext(ctx context.Context, params map[string]interface{}) (interface{}, error)
+	ListMerchantApplicationDocuments(applicationID string) ([]*DocumentInfo, error)
+	ListMerchantApplicationDocumentsWithContext(ctx context.Context, applicationID string) ([]*DocumentInfo, error)
+	DownloadMerchantApplicationDocument(applicationID, documentID string) (interface{}, error)
+	DownloadMerchantApplicationDocumentWithContext(ctx context.Context, applicationID, documentID string) (interface{}, error)
+	DownloadMerchantApplicationDocumentTo(applicationID, documentID string, w io.Writer) (*DocumentDownload, error)
+	DownloadMerchantApplicationDocumentToWithContext(ctx context.Context, applicationID, documentID string, w io.Writer) (*DocumentDownload, error)
+	UploadMerchantApplicationDocument(applicationID string, params map[string]interface{}) (interface{}, error)
+	UploadMerchantApplicationDocumentWithContext(ctx context.Context, applicationID string, params map[string]interface{}) (interface{}, error)
+	UploadMerchantApplicationDocumentStream(applicationID string, upload *DocumentUpload) (interface{}, error)
+	UploadMerchantApplicationDocumentStreamWithContext(ctx context.Context, applicationID string, upload *DocumentUpload) (interface{}, error)
+	UploadMerchantApplicationDocumentVerificationImage(applicationID string, params map[string]interface{}) (interface{}, error)
+	UploadMerchantApplicationDocumentVerificationImageWithContext(ctx context.Context, applicationID string, params map[string]interface{}) (interface{}, error)
+	UploadMerchantApplicationDocumentVerificationImageStream(applicationID string, upload *DocumentUpload) (interface{}, error)
+	UploadMerchantApplicationDocumentVerificationImageStreamWithContext(ctx context.Context, applicationID string, upload *DocumentUpload) (interface{}, error)
+	ApproveMerchantApplication(applicationID string, params map[string]interface{}) (interface{}, error)
+	ApproveMerchantApplicationWithContext(ctx context.Context, applicationID string, params map[string]interface{}) (interface{}, error)
+	RejectMerchantApplication(applicationID string, params map[string]interface{}) (interface{}, error)
+	RejectMerchantApplicationWithContext(ctx context.Context, applicationID string, params map[string]interface{}) (interface{}, error)
+	UndecideMerchantApplication(applicationID string, params map[string]interface{}) (interface{}, error)
+	UndecideMerchantApplicationWithContext(ctx context.Context, applicationID string, params map[string]interface{}) (interface{}, error)
+	ProvideMerchantApplicationResponse(applicationID string, params map[string]interface{}) (interface{}, error)
+	ProvideMerchantApplicationResponseWithContext(ctx context.Context, applicationID string, params map[string]interface{}) (interface{}, error)
+	RejectMerchantBusinessApplication(applicationID string, params map[string]interface{}) (interface{}, error)
+	RejectMerchantBusinessApplicationWithContext(ctx context.Context, applicationID string, params map[string]interface{}) (interface{}, error)
+	UndecideMerchantBusinessApplication(applicationID string, params map[string]interface{}) (interface{}, error)
+	UndecideMerchantBusinessApplicationWithContext(ctx context.Context, applicationID string, params map[string]interface{}) (interface{}, error)
+	GetMerchantBusinessApplication(applicationID string) (*BusinessApplication, error)
+	GetMerchantBusinessApplicationWithContext(ctx context.Context, applicationID string) (*BusinessApplication, error)
+	ReevaluateMerchantBusinessApplication(applicationID string) (*BusinessApplication, error)
+	ReevaluateMerchantBusinessApplicationWithContext(ctx context.Context, applicationID string) (*BusinessApplication, error)
+	SubmitMerchantBusinessApplication(merchantID string, params map[string]interface{}) (*BusinessApplication, error)
+	SubmitMerchantBusinessApplicationWithContext(ctx context.Context, merchantID string, params map[string]interface{}) (*BusinessApplication, error)
+	SubmitMerchantKYBApplication(merchantID string, application *BusinessApplicationRequest) (*BusinessApplication, error)
+	SubmitMerchantKYBApplicationWithContext(ctx context.Context, merchantID string, application *BusinessApplicationRequest) (*BusinessApplication, error)
+	ListMerchantBusinessApplicationDocuments(applicationID string) ([]*DocumentInfo, error)
+	ListMerchantBusinessApplicationDocumentsWithContext(ctx context.Context, applicationID string) ([]*DocumentInfo, error)
+	DownloadMerchantBusinessApplicationDocument(applicationID, documentID string) (interface{}, error)
+	DownloadMerchantBusinessApplicationDocumentWithContext(ctx context.Context, applicationID, documentID string) (interface{}, error)
+	DownloadMerchantBusinessApplicationDocumentTo(applicationID, documentID string, w io.Writer) (*DocumentDownload, error)
+	DownloadMerchantBusinessApplicationDocumentToWithContext(ctx context.Context, applicationID, documentID string, w io.Writer) (*DocumentDownload, error)
+	UploadMerchantBusinessApplicationDocument(applicationID string, params map[string]interface{}) (interface{}, error)
+	UploadMerchantBusinessApplicationDocumentWithContext(ctx context.Context, applicationID string, params map[string]interface{}) (interface{}, error)
+	UploadMerchantBusinessApplicationDocumentStream(applicationID string, upload *DocumentUpload) (interface{}, error)
+	UploadMerchantBusinessApplicationDocumentStreamWithContext(ctx context.Context, applicationID string, upload *DocumentUpload) (interface{}, error)
+	UploadMerchantBusinessApplicationDocumentVerificationImage(applicationID string, params map[string]interface{}) (interface{}, error)
+	UploadMerchantBusinessApplicationDocumentVerificationImageWithContext(ctx context.Context, applicationID string, params map[string]interface{}) (interface{}, error)
+	UploadMerchantBusinessApplicationDocumentVerificationImageStream(applicationID string, upload *DocumentUpload) (interface{}, error)
+	UploadMerchantBusinessApplicationDocumentVerificationImageStreamWithContext(ctx context.Context, applicationID string, upload *DocumentUpload) (interface{}, error)
+	ApproveMerchantBusinessApplication(applicationID string, params map[string]interface{}) (interface{}, error)
+	ApproveMerchantBusinessApplicationWithContext(ctx context.Context, applicationID string, params map[string]interface{}) (interface{}, error)
+	EvaluateMerchantFraud(merchantID string, params map[string]interface{}) (*TransactionResult, error)
+	EvaluateMerchantFraudWithContext(ctx context.Context, merchantID string, params map[string]interface{}) (*TransactionResult, error)
+	EvaluateMerchantPayment(merchantID string, payment *PaymentTransaction) (*TransactionResult, error)
+	EvaluateMerchantPaymentWithContext(ctx context.Context, merchantID string, payment *PaymentTransaction) (*TransactionResult, error)
+	ReportMerchantTransaction(merchantID, txType string, params map[string]interface{}) (*TransactionResult, error)
+	ReportMerchantTransactionWithContext(ctx context.Context, merchantID, txType string, params map[string]interface{}) (*TransactionResult, error)
+	ReportMerchantTransfer(merchantID string, transfer TransferRequest) (*TransactionResult, error)
+	ReportMerchantTransferWithContext(ctx context.Context, merchantID string, transfer TransferRequest) (*TransactionResult, error)
+}
+
+// Cases is implemented by clients of the identitymind case management API
+type Cases interface {
+	GetCase(caseID string) (*Case, error)
+	GetCaseWithContext(ctx context.Context, caseID string) (*Case, error)
+	CreateCase(params map[string]interface{}) (*Case, error)
+	CreateCaseWithContext(ctx context.Context, params map[string]interface{}) (*Case, error)
+	OpenCase(request *CaseRequest) (*Case, error)
+	OpenCaseWithContext(ctx context.Context, request *CaseRequest) (*Case, error)
+	CloseCase(caseID string, params map[string]interface{}) (*Case, error)
+	CloseCaseWithContext(ctx context.Context, caseID string, params map[string]interface{}) (*Case, error)
+	ResolveCase(caseID string, closure *CaseClosure) (*Case, error)
+	ResolveCaseWithContext(ctx context.Context, caseID string, closure *CaseClosure) (*Case, error)
+	UpdateCase(caseID string, params map[string]interface{}) (*Case, error)
+	UpdateCaseWithContext(ctx context.Context, caseID string, params map[string]interface{}) (*Case, error)
+	EditCase(caseID string, update *CaseUpdate) (*Case, error)
+	EditCaseWithContext(ctx context.Context, caseID string, update *CaseUpdate) (*Case, error)
+}
+
+// Transactions is implemented by clients of the identitymind transaction and anti-fraud API
+type Transactions interface {
+	EvaluateFraud(params map[string]interface{}) (*TransactionResult, error)
+	EvaluateFraudWithContext(ctx context.Context, params map[string]interface{}) (*TransactionResult, error)
+	EvaluatePayment(payment *PaymentTransaction) (*TransactionResult, error)
+	EvaluatePaymentWithContext(ctx context.Context, payment *PaymentTransaction) (*TransactionResult, error)
+	ReportFraud(params map[string]interface{}) (interface{}, error)
+	ReportFraudWithContext(ctx context.Context, params map[string]interface{}) (interface{}, error)
+	ReportTransaction(txType string, params map[string]interface{}) (*TransactionResult, error)
+	ReportTransactionWithContext(ctx context.Context, txType string, params map[string]interface{}) (*TransactionResult, error)
+	ReportTransfer(transfer TransferRequest) (*TransactionResult, error)
+	ReportTransferWithContext(ctx context.Context, transfer TransferRequest) (*TransactionResult, error)
+	AcceptTransaction(transactionID string, feedback *TransactionFeedback) (interface{}, error)
+	AcceptTransactionWithContext(ctx context.Context, transactionID string, feedback *TransactionFeedback) (interface{}, error)
+	RejectTransaction(transactionID string, feedback *TransactionFeedback) (interface{}, error)
+	RejectTransactionWithContext(ctx context.Context, transactionID string, feedback *TransactionFeedback) (interface{}, error)
+	ReportTransactionFraud(transactionID string, feedback *TransactionFeedback) (interface{}, error)
+	ReportTransactionFraudWithContext(ctx context.Context, transactionID string, feedback *TransactionFeedback) (interface{}, error)
+	ReportTransactionChargeback(transactionID string, feedback *TransactionFeedback) (interface{}, error)
+	ReportTransactionChargebackWithContext(ctx context.Context, transactionID string, feedback *TransactionFeedback) (interface{}, error)
+}
+
+// Client is implemented by clients of the complete identitymind API, i.e. IdentityMindAPIClient
+type Client interface {
+	KYC
+	KYB
+	Merchants
+	Cases
+	Transactions
+}
+
+var _ Client = (*IdentityMindAPIClient)(nil)
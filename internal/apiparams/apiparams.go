@@ -0,0 +1,35 @@
+// Package apiparams provides the conversion of typed requests to the parameters maps sent to the
+// identitymind API; it is shared by the client and its test doubles so that both encode requests alike
+package apiparams
+
+import (
+	"bytes"
+	"encoding/json"
+)
+
+// Marshal converts the given JSON-tagged struct to a parameters map; numbers are decoded as
+// json.Number so that amounts and identifiers are not rounded via float64
+func Marshal(v interface{}) (map[string]interface{}, error) {
+	raw, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+
+	var params map[string]interface{}
+	decoder := json.NewDecoder(bytes.NewReader(raw))
+	decoder.UseNumber()
+	err = decoder.Decode(&params)
+	if err != nil {
+		return nil, err
+	}
+	return params, nil
+}
+
+// Copy returns a shallow copy of the given parameters map
+func Copy(params map[string]interface{}) map[string]interface{} {
+	cpy := make(map[string]interface{}, len(params))
+	for key, val := range params {
+		cpy[key] = val
+	}
+	return cpy
+}
@@ -0,0 +1,106 @@
+// Package testdouble contains the records kept by the identitymindfake and identitymindtest test
+// doubles, which are exposed by both packages via type aliases
+package testdouble
+
+import (
+	"fmt"
+	"time"
+
+	identitymind "github.com/kthomas/identitymind-golang"
+	"github.com/kthomas/identitymind-golang/internal/apiparams"
+)
+
+// Document is a document or verification image uploaded to a test double
+type Document struct {
+	ID                string
+	ApplicationID     string
+	VerificationImage bool
+	Params            map[string]interface{} // params of the upload, when uploaded using a params map
+
+	// Field, Filename, ContentType, Description and Data are set when the document was uploaded as a file
+	Field       string // multipart form field name
+	Filename    string
+	ContentType string
+	Description string
+	Data        []byte
+
+	UploadedAt time.Time
+}
+
+// Transaction is a transaction evaluated or reported via a test double
+type Transaction struct {
+	ID         string
+	Type       string // transaction type, i.e. transferin, or empty for anti-fraud evaluations
+	MerchantID string
+	Params     map[string]interface{}
+	Result     identitymind.PolicyResult
+	Feedback   []*TransactionFeedback // feedback provided for the transaction, in order
+}
+
+// TransactionFeedback is feedback provided for a transaction via a test double
+type TransactionFeedback struct {
+	Type   identitymind.TransactionFeedbackType
+	Params map[string]interface{}
+}
+
+// Case is a case created via a test double
+type Case struct {
+	ID        string
+	Params    map[string]interface{}
+	Status    identitymind.CaseStatus
+	Closed    bool        // true while the status is closed
+	Notes     []*CaseNote // notes recorded via the note param, in order
+	CreatedAt time.Time
+	UpdatedAt time.Time
+	ClosedAt  *time.Time
+}
+
+// CaseNote is a note recorded against a case created via a test double
+type CaseNote struct {
+	ID        string    `json:"id"`
+	Author    string    `json:"author,omitempty"`
+	Text      string    `json:"text"`
+	CreatedAt time.Time `json:"createdAt"`
+}
+
+// ValidateDecision returns an error if a reviewer may not move an application from the given state to the
+// given state; reviewers decide applications which are under review, while decisions are reversed using
+// the feedback endpoints
+func ValidateDecision(from, to identitymind.ApplicationState) error {
+	if !to.IsTerminal() {
+		return fmt.Errorf("invalid decision: %s", to)
+	}
+	if !(identitymind.StateTransition{From: from, To: to}).IsDecision() {
+		return fmt.Errorf("application is not under review; it is %s", from)
+	}
+	return nil
+}
+
+// CreateMerchant stores a merchant with the given params in the given merchants, returning an error if a
+// merchant with the requested identifier (m) already exists; newID is invoked when none is requested
+func CreateMerchant(merchants map[string]map[string]interface{}, params map[string]interface{}, newID func() string) (map[string]interface{}, error) {
+	merchantID, _ := params["m"].(string)
+	if merchantID == "" {
+		merchantID = newID()
+	} else if _, ok := merchants[merchantID]; ok {
+		return nil, fmt.Errorf("merchant already exists: %s", merchantID)
+	}
+	merchant := apiparams.Copy(params)
+	merchant["m"] = merchantID
+	merchant["id"] = merchantID
+	merchants[merchantID] = merchant
+	return apiparams.Copy(merchant), nil
+}
+
+// UpdateMerchant merges the given params into the given merchant; the merchant identifier may not be changed
+func UpdateMerchant(merchant map[string]interface{}, params map[string]interface{}) (map[string]interface{}, error) {
+	for _, key := range []string{"m", "id"} {
+		if val, ok := params[key]; ok && val != merchant[key] {
+			return nil, fmt.Errorf("merchant identifier may not be changed")
+		}
+	}
+	for key, val := range params {
+		merchant[key] = val
+	}
+	return apiparams.Copy(merchant), nil
+}
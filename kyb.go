@@ -1,105 +1,223 @@
 package identitymind
 
-import "fmt"
+import (
+	"context"
+	"fmt"
+	"io"
+)
 
 // KYB
 
 // GetBusinessApplication see https://edoc.identitymind.com/reference#getmerchantkyc
-func (i *IdentityMindAPIClient) GetBusinessApplication(applicationID string) (interface{}, error) {
-	var resp map[string]interface{}
-	status, err := i.Get(fmt.Sprintf("im/account/merchant/%s", applicationID), map[string]interface{}{}, &resp)
+func (i *IdentityMindAPIClient) GetBusinessApplication(applicationID string) (*BusinessApplication, error) {
+	return i.GetBusinessApplicationWithContext(context.Background(), applicationID)
+}
+
+// GetBusinessApplicationWithContext see https://edoc.identitymind.com/reference#getmerchantkyc
+func (i *IdentityMindAPIClient) GetBusinessApplicationWithContext(ctx context.Context, applicationID string) (*BusinessApplication, error) {
+	var resp *BusinessApplication
+	status, err := i.GetWithContext(ctx, fmt.Sprintf("im/account/merchant/%s", applicationID), map[string]interface{}{}, &resp)
 	if err != nil {
-		return nil, fmt.Errorf("Failed to retrieve KYB application via identitymind API; status: %d; %s", status, err.Error())
+		return nil, fmt.Errorf("Failed to retrieve KYB application via identitymind API; status: %d; %w", status, err)
 	}
 	return resp, nil
 }
 
 // ReevaluateBusinessApplication see https://edoc.identitymind.com/reference#reevaluatemerchant
-func (i *IdentityMindAPIClient) ReevaluateBusinessApplication(applicationID string) (interface{}, error) {
-	var resp map[string]interface{}
-	status, err := i.Post(fmt.Sprintf("im/account/merchant/%s", applicationID), map[string]interface{}{}, &resp)
+func (i *IdentityMindAPIClient) ReevaluateBusinessApplication(applicationID string) (*BusinessApplication, error) {
+	return i.ReevaluateBusinessApplicationWithContext(context.Background(), applicationID)
+}
+
+// ReevaluateBusinessApplicationWithContext see https://edoc.identitymind.com/reference#reevaluatemerchant
+func (i *IdentityMindAPIClient) ReevaluateBusinessApplicationWithContext(ctx context.Context, applicationID string) (*BusinessApplication, error) {
+	var resp *BusinessApplication
+	status, err := i.PostWithContext(ctx, fmt.Sprintf("im/account/merchant/%s", applicationID), map[string]interface{}{}, &resp)
 	if err != nil {
-		return nil, fmt.Errorf("Failed to reevaluate KYB application via identitymind API; status: %d; %s", status, err.Error())
+		return nil, fmt.Errorf("Failed to reevaluate KYB application via identitymind API; status: %d; %w", status, err)
 	}
 	return resp, nil
 }
 
 // SubmitBusinessApplication see https://edoc.identitymind.com/reference#merchant
-func (i *IdentityMindAPIClient) SubmitBusinessApplication(params map[string]interface{}) (interface{}, error) {
-	var resp map[string]interface{}
-	status, err := i.Post("im/account/merchant?graphScoreResponse=false", params, &resp)
+func (i *IdentityMindAPIClient) SubmitBusinessApplication(params map[string]interface{}) (*BusinessApplication, error) {
+	return i.SubmitBusinessApplicationWithContext(context.Background(), params)
+}
+
+// SubmitBusinessApplicationWithContext see https://edoc.identitymind.com/reference#merchant
+func (i *IdentityMindAPIClient) SubmitBusinessApplicationWithContext(ctx context.Context, params map[string]interface{}) (*BusinessApplication, error) {
+	var resp *BusinessApplication
+	status, err := i.PostWithContext(ctx, "im/account/merchant?graphScoreResponse=false", params, &resp)
 	if err != nil {
-		return nil, fmt.Errorf("Failed to reevaluate KYB application via identitymind API; status: %d; %s", status, err.Error())
+		return nil, fmt.Errorf("Failed to submit KYB application via identitymind API; status: %d; %w", status, err)
 	}
 	return resp, nil
 }
 
+// SubmitKYBApplication submits the given typed KYB application, including its beneficial owners and officers; see https://edoc.identitymind.com/reference#merchant
+func (i *IdentityMindAPIClient) SubmitKYBApplication(application *BusinessApplicationRequest) (*BusinessApplication, error) {
+	return i.SubmitKYBApplicationWithContext(context.Background(), application)
+}
+
+// SubmitKYBApplicationWithContext submits the given typed KYB application, including its beneficial owners and officers; see https://edoc.identitymind.com/reference#merchant
+func (i *IdentityMindAPIClient) SubmitKYBApplicationWithContext(ctx context.Context, application *BusinessApplicationRequest) (*BusinessApplication, error) {
+	if application == nil {
+		return nil, fmt.Errorf("Failed to submit KYB application; application is required")
+	}
+	if err := application.Validate(); err != nil {
+		return nil, fmt.Errorf("Failed to submit KYB application; %s", err.Error())
+	}
+	params, err := marshalParams(application)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to marshal KYB application; %s", err.Error())
+	}
+	return i.SubmitBusinessApplicationWithContext(ctx, params)
+}
+
 // ListBusinessApplicationDocuments see https://edoc.identitymind.com/reference#getfilelistforapplicationformerchant
-func (i *IdentityMindAPIClient) ListBusinessApplicationDocuments(applicationID string) (interface{}, error) {
-	var resp map[string]interface{}
-	status, err := i.Get(fmt.Sprintf("im/account/merchant/%s/files", applicationID), map[string]interface{}{}, &resp)
+func (i *IdentityMindAPIClient) ListBusinessApplicationDocuments(applicationID string) ([]*DocumentInfo, error) {
+	return i.ListBusinessApplicationDocumentsWithContext(context.Background(), applicationID)
+}
+
+// ListBusinessApplicationDocumentsWithContext see https://edoc.identitymind.com/reference#getfilelistforapplicationformerchant
+func (i *IdentityMindAPIClient) ListBusinessApplicationDocumentsWithContext(ctx context.Context, applicationID string) ([]*DocumentInfo, error) {
+	var resp *documentList
+	status, err := i.GetWithContext(ctx, fmt.Sprintf("im/account/merchant/%s/files", applicationID), map[string]interface{}{}, &resp)
 	if err != nil {
-		return nil, fmt.Errorf("Failed to list KYB documents via identitymind API; status: %d; %s", status, err.Error())
+		return nil, fmt.Errorf("Failed to list KYB documents via identitymind API; status: %d; %w", status, err)
 	}
-	return resp, nil
+	return resp.documents(), nil
 }
 
 // DownloadBusinessApplicationDocument see https://edoc.identitymind.com/reference#reevaluatemerchant
 func (i *IdentityMindAPIClient) DownloadBusinessApplicationDocument(applicationID, documentID string) (interface{}, error) {
+	return i.DownloadBusinessApplicationDocumentWithContext(context.Background(), applicationID, documentID)
+}
+
+// DownloadBusinessApplicationDocumentWithContext see https://edoc.identitymind.com/reference#reevaluatemerchant
+func (i *IdentityMindAPIClient) DownloadBusinessApplicationDocumentWithContext(ctx context.Context, applicationID, documentID string) (interface{}, error) {
 	var resp map[string]interface{}
-	status, err := i.Get(fmt.Sprintf("im/account/consumer/%s/files/%s", applicationID, documentID), map[string]interface{}{}, &resp)
+	status, err := i.GetWithContext(ctx, fmt.Sprintf("im/account/merchant/%s/files/%s", applicationID, documentID), map[string]interface{}{}, &resp)
 	if err != nil {
-		return nil, fmt.Errorf("Failed to download KYB document via identitymind API; status: %d; %s", status, err.Error())
+		return nil, fmt.Errorf("Failed to download KYB document via identitymind API; status: %d; %w", status, err)
 	}
 	return resp, nil
 }
 
+// DownloadBusinessApplicationDocumentTo streams the raw content of the given document of the KYB application to w and returns its metadata; see https://edoc.identitymind.com/reference#reevaluatemerchant
+func (i *IdentityMindAPIClient) DownloadBusinessApplicationDocumentTo(applicationID, documentID string, w io.Writer) (*DocumentDownload, error) {
+	return i.DownloadBusinessApplicationDocumentToWithContext(context.Background(), applicationID, documentID, w)
+}
+
+// DownloadBusinessApplicationDocumentToWithContext streams the raw content of the given document of the KYB application to w and returns its metadata; see https://edoc.identitymind.com/reference#reevaluatemerchant
+func (i *IdentityMindAPIClient) DownloadBusinessApplicationDocumentToWithContext(ctx context.Context, applicationID, documentID string, w io.Writer) (*DocumentDownload, error) {
+	download, status, err := i.getDocumentDownload(ctx, fmt.Sprintf("im/account/merchant/%s/files/%s", applicationID, documentID), w)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to download KYB document via identitymind API; status: %d; %w", status, err)
+	}
+	return download, nil
+}
+
 // UploadBusinessApplicationDocument see https://edoc.identitymind.com/reference#processfileuploadrequestformerchantkyc
 func (i *IdentityMindAPIClient) UploadBusinessApplicationDocument(applicationID string, params map[string]interface{}) (interface{}, error) {
+	return i.UploadBusinessApplicationDocumentWithContext(context.Background(), applicationID, params)
+}
+
+// UploadBusinessApplicationDocumentWithContext see https://edoc.identitymind.com/reference#processfileuploadrequestformerchantkyc
+func (i *IdentityMindAPIClient) UploadBusinessApplicationDocumentWithContext(ctx context.Context, applicationID string, params map[string]interface{}) (interface{}, error) {
+	var resp map[string]interface{}
+	status, err := i.PostMultipartFormDataWithContext(ctx, fmt.Sprintf("im/account/merchant/%s/files", applicationID), params, &resp)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to upload KYB document via identitymind API; status: %d; %w", status, err)
+	}
+	return resp, nil
+}
+
+// UploadBusinessApplicationDocumentStream streams the given document to the KYB application without buffering it in memory; the upload is not retried; see https://edoc.identitymind.com/reference#processfileuploadrequestformerchantkyc
+func (i *IdentityMindAPIClient) UploadBusinessApplicationDocumentStream(applicationID string, upload *DocumentUpload) (interface{}, error) {
+	return i.UploadBusinessApplicationDocumentStreamWithContext(context.Background(), applicationID, upload)
+}
+
+// UploadBusinessApplicationDocumentStreamWithContext streams the given document to the KYB application without buffering it in memory; the upload is not retried; see https://edoc.identitymind.com/reference#processfileuploadrequestformerchantkyc
+func (i *IdentityMindAPIClient) UploadBusinessApplicationDocumentStreamWithContext(ctx context.Context, applicationID string, upload *DocumentUpload) (interface{}, error) {
 	var resp map[string]interface{}
-	status, err := i.PostMultipartFormData(fmt.Sprintf("im/account/merchant/%s/files", applicationID), params, &resp)
+	status, err := i.postDocumentUpload(ctx, fmt.Sprintf("im/account/merchant/%s/files", applicationID), upload, &resp)
 	if err != nil {
-		return nil, fmt.Errorf("Failed to upload KYB document via identitymind API; status: %d; %s", status, err.Error())
+		return nil, fmt.Errorf("Failed to upload KYB document via identitymind API; status: %d; %w", status, err)
 	}
 	return resp, nil
 }
 
 // UploadBusinessApplicationDocumentVerificationImage see https://edoc.identitymind.com/reference#processfileuploadrequestformerchantkyc
 func (i *IdentityMindAPIClient) UploadBusinessApplicationDocumentVerificationImage(applicationID string, params map[string]interface{}) (interface{}, error) {
+	return i.UploadBusinessApplicationDocumentVerificationImageWithContext(context.Background(), applicationID, params)
+}
+
+// UploadBusinessApplicationDocumentVerificationImageWithContext see https://edoc.identitymind.com/reference#processfileuploadrequestformerchantkyc
+func (i *IdentityMindAPIClient) UploadBusinessApplicationDocumentVerificationImageWithContext(ctx context.Context, applicationID string, params map[string]interface{}) (interface{}, error) {
 	var resp map[string]interface{}
-	status, err := i.PostMultipartFormData(fmt.Sprintf("im/account/merchant/%s/dv", applicationID), params, &resp)
+	status, err := i.PostMultipartFormDataWithContext(ctx, fmt.Sprintf("im/account/merchant/%s/dv", applicationID), params, &resp)
 	if err != nil {
-		return nil, fmt.Errorf("Failed to upload KYB document image for verification via identitymind API; status: %d; %s", status, err.Error())
+		return nil, fmt.Errorf("Failed to upload KYB document image for verification via identitymind API; status: %d; %w", status, err)
+	}
+	return resp, nil
+}
+
+// UploadBusinessApplicationDocumentVerificationImageStream streams the given document to the KYB application for verification without buffering it in memory; the upload is not retried; see https://edoc.identitymind.com/reference#processfileuploadrequestformerchantkyc
+func (i *IdentityMindAPIClient) UploadBusinessApplicationDocumentVerificationImageStream(applicationID string, upload *DocumentUpload) (interface{}, error) {
+	return i.UploadBusinessApplicationDocumentVerificationImageStreamWithContext(context.Background(), applicationID, upload)
+}
+
+// UploadBusinessApplicationDocumentVerificationImageStreamWithContext streams the given document to the KYB application for verification without buffering it in memory; the upload is not retried; see https://edoc.identitymind.com/reference#processfileuploadrequestformerchantkyc
+func (i *IdentityMindAPIClient) UploadBusinessApplicationDocumentVerificationImageStreamWithContext(ctx context.Context, applicationID string, upload *DocumentUpload) (interface{}, error) {
+	var resp map[string]interface{}
+	status, err := i.postDocumentUpload(ctx, fmt.Sprintf("im/account/merchant/%s/dv", applicationID), upload, &resp)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to upload KYB document image for verification via identitymind API; status: %d; %w", status, err)
 	}
 	return resp, nil
 }
 
 // ApproveBusinessApplication see https://edoc.identitymind.com/reference#feedback_1
 func (i *IdentityMindAPIClient) ApproveBusinessApplication(applicationID string, params map[string]interface{}) (interface{}, error) {
+	return i.ApproveBusinessApplicationWithContext(context.Background(), applicationID, params)
+}
+
+// ApproveBusinessApplicationWithContext see https://edoc.identitymind.com/reference#feedback_1
+func (i *IdentityMindAPIClient) ApproveBusinessApplicationWithContext(ctx context.Context, applicationID string, params map[string]interface{}) (interface{}, error) {
 	var resp map[string]interface{}
-	status, err := i.Post(fmt.Sprintf("im/account/merchant/%s/accepted", applicationID), params, &resp)
+	status, err := i.PostWithContext(ctx, fmt.Sprintf("im/account/merchant/%s/accepted", applicationID), params, &resp)
 	if err != nil {
-		return nil, fmt.Errorf("Failed to acccept KYB application via identitymind API; status: %d; %s", status, err.Error())
+		return nil, fmt.Errorf("Failed to acccept KYB application via identitymind API; status: %d; %w", status, err)
 	}
 	return resp, nil
 }
 
 // RejectBusinessApplication see https://edoc.identitymind.com/reference#feedback_1
 func (i *IdentityMindAPIClient) RejectBusinessApplication(applicationID string, params map[string]interface{}) (interface{}, error) {
+	return i.RejectBusinessApplicationWithContext(context.Background(), applicationID, params)
+}
+
+// RejectBusinessApplicationWithContext see https://edoc.identitymind.com/reference#feedback_1
+func (i *IdentityMindAPIClient) RejectBusinessApplicationWithContext(ctx context.Context, applicationID string, params map[string]interface{}) (interface{}, error) {
 	var resp map[string]interface{}
-	status, err := i.Post(fmt.Sprintf("im/account/merchant/%s/rejected", applicationID), params, &resp)
+	status, err := i.PostWithContext(ctx, fmt.Sprintf("im/account/merchant/%s/rejected", applicationID), params, &resp)
 	if err != nil {
-		return nil, fmt.Errorf("Failed to reject KYB application via identitymind API; status: %d; %s", status, err.Error())
+		return nil, fmt.Errorf("Failed to reject KYB application via identitymind API; status: %d; %w", status, err)
 	}
 	return resp, nil
 }
 
 // UndecideBusinessApplication see https://edoc.identitymind.com/reference#feedback_1
 func (i *IdentityMindAPIClient) UndecideBusinessApplication(applicationID string, params map[string]interface{}) (interface{}, error) {
+	return i.UndecideBusinessApplicationWithContext(context.Background(), applicationID, params)
+}
+
+// UndecideBusinessApplicationWithContext see https://edoc.identitymind.com/reference#feedback_1
+func (i *IdentityMindAPIClient) UndecideBusinessApplicationWithContext(ctx context.Context, applicationID string, params map[string]interface{}) (interface{}, error) {
 	var resp map[string]interface{}
-	status, err := i.Post(fmt.Sprintf("im/account/merchant/%s/review", applicationID), params, &resp)
+	status, err := i.PostWithContext(ctx, fmt.Sprintf("im/account/merchant/%s/review", applicationID), params, &resp)
 	if err != nil {
-		return nil, fmt.Errorf("Failed to undecide KYB application via identitymind API; status: %d; %s", status, err.Error())
+		return nil, fmt.Errorf("Failed to undecide KYB application via identitymind API; status: %d; %w", status, err)
 	}
 	return resp, nil
 }
@@ -1,105 +1,220 @@
 package identitymind
 
-import "fmt"
+import (
+	"context"
+	"fmt"
+	"io"
+)
 
 // KYC
 
 // GetApplication see https://edoc.identitymind.com/reference#getv2
-func (i *IdentityMindAPIClient) GetApplication(applicationID string) (interface{}, error) {
-	var resp map[string]interface{}
-	status, err := i.Get(fmt.Sprintf("im/account/consumer/v2/%s", applicationID), map[string]interface{}{}, &resp)
+func (i *IdentityMindAPIClient) GetApplication(applicationID string) (*KYCApplication, error) {
+	return i.GetApplicationWithContext(context.Background(), applicationID)
+}
+
+// GetApplicationWithContext see https://edoc.identitymind.com/reference#getv2
+func (i *IdentityMindAPIClient) GetApplicationWithContext(ctx context.Context, applicationID string) (*KYCApplication, error) {
+	var resp *KYCApplication
+	status, err := i.GetWithContext(ctx, fmt.Sprintf("im/account/consumer/v2/%s", applicationID), map[string]interface{}{}, &resp)
 	if err != nil {
-		return nil, fmt.Errorf("Failed to retrieve KYC application via identitymind API; status: %d; %s", status, err.Error())
+		return nil, fmt.Errorf("Failed to retrieve KYC application via identitymind API; status: %d; %w", status, err)
 	}
 	return resp, nil
 }
 
 // SubmitApplication see https://edoc.identitymind.com/reference#create
-func (i *IdentityMindAPIClient) SubmitApplication(params map[string]interface{}) (interface{}, error) {
-	var resp map[string]interface{}
-	status, err := i.Post("im/account/consumer?graphScoreResponse=false", params, &resp)
+func (i *IdentityMindAPIClient) SubmitApplication(params map[string]interface{}) (*KYCApplication, error) {
+	return i.SubmitApplicationWithContext(context.Background(), params)
+}
+
+// SubmitApplicationWithContext see https://edoc.identitymind.com/reference#create
+func (i *IdentityMindAPIClient) SubmitApplicationWithContext(ctx context.Context, params map[string]interface{}) (*KYCApplication, error) {
+	var resp *KYCApplication
+	status, err := i.PostWithContext(ctx, "im/account/consumer?graphScoreResponse=false", params, &resp)
 	if err != nil {
-		return nil, fmt.Errorf("Failed to upload consumer KYC document via identitymind API; status: %d; %s", status, err.Error())
+		return nil, fmt.Errorf("Failed to upload consumer KYC document via identitymind API; status: %d; %w", status, err)
 	}
 	return resp, nil
 }
 
+// SubmitConsumerApplication submits the given typed consumer KYC application; see https://edoc.identitymind.com/reference#create
+func (i *IdentityMindAPIClient) SubmitConsumerApplication(application *ConsumerApplicationRequest) (*KYCApplication, error) {
+	return i.SubmitConsumerApplicationWithContext(context.Background(), application)
+}
+
+// SubmitConsumerApplicationWithContext submits the given typed consumer KYC application; see https://edoc.identitymind.com/reference#create
+func (i *IdentityMindAPIClient) SubmitConsumerApplicationWithContext(ctx context.Context, application *ConsumerApplicationRequest) (*KYCApplication, error) {
+	if application == nil || application.AccountName == "" {
+		return nil, fmt.Errorf("Failed to submit consumer KYC application; account name (man) is required")
+	}
+	params, err := marshalParams(application)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to marshal consumer KYC application; %s", err.Error())
+	}
+	return i.SubmitApplicationWithContext(ctx, params)
+}
+
 // ProvideApplicationResponse see https://edoc.identitymind.com/reference#quizresponse_1
 func (i *IdentityMindAPIClient) ProvideApplicationResponse(applicationID string, params map[string]interface{}) (interface{}, error) {
+	return i.ProvideApplicationResponseWithContext(context.Background(), applicationID, params)
+}
+
+// ProvideApplicationResponseWithContext see https://edoc.identitymind.com/reference#quizresponse_1
+func (i *IdentityMindAPIClient) ProvideApplicationResponseWithContext(ctx context.Context, applicationID string, params map[string]interface{}) (interface{}, error) {
 	var resp map[string]interface{}
-	status, err := i.Post(fmt.Sprintf("im/account/consumer/%s/quizresponse", applicationID), params, &resp)
+	status, err := i.PostWithContext(ctx, fmt.Sprintf("im/account/consumer/%s/quizresponse", applicationID), params, &resp)
 	if err != nil {
-		return nil, fmt.Errorf("Failed to reject merchant KYC application via identitymind API; status: %d; %s", status, err.Error())
+		return nil, fmt.Errorf("Failed to reject merchant KYC application via identitymind API; status: %d; %w", status, err)
 	}
 	return resp, nil
 }
 
 // ListApplicationDocuments see https://edoc.identitymind.com/reference#getfilelistforapplication
-func (i *IdentityMindAPIClient) ListApplicationDocuments(applicationID string) (interface{}, error) {
-	var resp map[string]interface{}
-	status, err := i.Get(fmt.Sprintf("im/account/consumer/%s/files", applicationID), map[string]interface{}{}, &resp)
+func (i *IdentityMindAPIClient) ListApplicationDocuments(applicationID string) ([]*DocumentInfo, error) {
+	return i.ListApplicationDocumentsWithContext(context.Background(), applicationID)
+}
+
+// ListApplicationDocumentsWithContext see https://edoc.identitymind.com/reference#getfilelistforapplication
+func (i *IdentityMindAPIClient) ListApplicationDocumentsWithContext(ctx context.Context, applicationID string) ([]*DocumentInfo, error) {
+	var resp *documentList
+	status, err := i.GetWithContext(ctx, fmt.Sprintf("im/account/consumer/%s/files", applicationID), map[string]interface{}{}, &resp)
 	if err != nil {
-		return nil, fmt.Errorf("Failed to list KYC documents via identitymind API; status: %d; %s", status, err.Error())
+		return nil, fmt.Errorf("Failed to list KYC documents via identitymind API; status: %d; %w", status, err)
 	}
-	return resp, nil
+	return resp.documents(), nil
 }
 
 // DownloadApplicationDocument see https://edoc.identitymind.com/reference#reevaluatemerchant
 func (i *IdentityMindAPIClient) DownloadApplicationDocument(applicationID, documentID string) (interface{}, error) {
+	return i.DownloadApplicationDocumentWithContext(context.Background(), applicationID, documentID)
+}
+
+// DownloadApplicationDocumentWithContext see https://edoc.identitymind.com/reference#reevaluatemerchant
+func (i *IdentityMindAPIClient) DownloadApplicationDocumentWithContext(ctx context.Context, applicationID, documentID string) (interface{}, error) {
 	var resp map[string]interface{}
-	status, err := i.Get(fmt.Sprintf("im/account/consumer/%s/files/%s", applicationID, documentID), map[string]interface{}{}, &resp)
+	status, err := i.GetWithContext(ctx, fmt.Sprintf("im/account/consumer/%s/files/%s", applicationID, documentID), map[string]interface{}{}, &resp)
 	if err != nil {
-		return nil, fmt.Errorf("Failed to download KYC document via identitymind API; status: %d; %s", status, err.Error())
+		return nil, fmt.Errorf("Failed to download KYC document via identitymind API; status: %d; %w", status, err)
 	}
 	return resp, nil
 }
 
+// DownloadApplicationDocumentTo streams the raw content of the given document of the KYC application to w and returns its metadata; see https://edoc.identitymind.com/reference#reevaluatemerchant
+func (i *IdentityMindAPIClient) DownloadApplicationDocumentTo(applicationID, documentID string, w io.Writer) (*DocumentDownload, error) {
+	return i.DownloadApplicationDocumentToWithContext(context.Background(), applicationID, documentID, w)
+}
+
+// DownloadApplicationDocumentToWithContext streams the raw content of the given document of the KYC application to w and returns its metadata; see https://edoc.identitymind.com/reference#reevaluatemerchant
+func (i *IdentityMindAPIClient) DownloadApplicationDocumentToWithContext(ctx context.Context, applicationID, documentID string, w io.Writer) (*DocumentDownload, error) {
+	download, status, err := i.getDocumentDownload(ctx, fmt.Sprintf("im/account/consumer/%s/files/%s", applicationID, documentID), w)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to download consumer KYC document via identitymind API; status: %d; %w", status, err)
+	}
+	return download, nil
+}
+
 // UploadApplicationDocument see https://edoc.identitymind.com/reference#processfileuploadrequest
 func (i *IdentityMindAPIClient) UploadApplicationDocument(applicationID string, params map[string]interface{}) (interface{}, error) {
+	return i.UploadApplicationDocumentWithContext(context.Background(), applicationID, params)
+}
+
+// UploadApplicationDocumentWithContext see https://edoc.identitymind.com/reference#processfileuploadrequest
+func (i *IdentityMindAPIClient) UploadApplicationDocumentWithContext(ctx context.Context, applicationID string, params map[string]interface{}) (interface{}, error) {
 	var resp map[string]interface{}
-	status, err := i.PostMultipartFormData(fmt.Sprintf("im/account/consumer/%s/files", applicationID), params, &resp)
+	status, err := i.PostMultipartFormDataWithContext(ctx, fmt.Sprintf("im/account/consumer/%s/files", applicationID), params, &resp)
 	if err != nil {
-		return nil, fmt.Errorf("Failed to upload consumer KYC document via identitymind API; status: %d; %s", status, err.Error())
+		return nil, fmt.Errorf("Failed to upload consumer KYC document via identitymind API; status: %d; %w", status, err)
+	}
+	return resp, nil
+}
+
+// UploadApplicationDocumentStream streams the given document to the KYC application without buffering it in memory; the upload is not retried; see https://edoc.identitymind.com/reference#processfileuploadrequest
+func (i *IdentityMindAPIClient) UploadApplicationDocumentStream(applicationID string, upload *DocumentUpload) (interface{}, error) {
+	return i.UploadApplicationDocumentStreamWithContext(context.Background(), applicationID, upload)
+}
+
+// UploadApplicationDocumentStreamWithContext streams the given document to the KYC application without buffering it in memory; the upload is not retried; see https://edoc.identitymind.com/reference#processfileuploadrequest
+func (i *IdentityMindAPIClient) UploadApplicationDocumentStreamWithContext(ctx context.Context, applicationID string, upload *DocumentUpload) (interface{}, error) {
+	var resp map[string]interface{}
+	status, err := i.postDocumentUpload(ctx, fmt.Sprintf("im/account/consumer/%s/files", applicationID), upload, &resp)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to upload consumer KYC document via identitymind API; status: %d; %w", status, err)
 	}
 	return resp, nil
 }
 
 // UploadApplicationDocumentVerificationImage see https://edoc.identitymind.com/reference#processimageuploadrequest
 func (i *IdentityMindAPIClient) UploadApplicationDocumentVerificationImage(applicationID string, params map[string]interface{}) (interface{}, error) {
+	return i.UploadApplicationDocumentVerificationImageWithContext(context.Background(), applicationID, params)
+}
+
+// UploadApplicationDocumentVerificationImageWithContext see https://edoc.identitymind.com/reference#processimageuploadrequest
+func (i *IdentityMindAPIClient) UploadApplicationDocumentVerificationImageWithContext(ctx context.Context, applicationID string, params map[string]interface{}) (interface{}, error) {
+	var resp map[string]interface{}
+	status, err := i.PostMultipartFormDataWithContext(ctx, fmt.Sprintf("im/account/consumer/%s/dv", applicationID), params, &resp)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to upload consumer KYC document image for verification via identitymind API; status: %d; %w", status, err)
+	}
+	return resp, nil
+}
+
+// UploadApplicationDocumentVerificationImageStream streams the given document to the KYC application for verification without buffering it in memory; the upload is not retried; see https://edoc.identitymind.com/reference#processimageuploadrequest
+func (i *IdentityMindAPIClient) UploadApplicationDocumentVerificationImageStream(applicationID string, upload *DocumentUpload) (interface{}, error) {
+	return i.UploadApplicationDocumentVerificationImageStreamWithContext(context.Background(), applicationID, upload)
+}
+
+// UploadApplicationDocumentVerificationImageStreamWithContext streams the given document to the KYC application for verification without buffering it in memory; the upload is not retried; see https://edoc.identitymind.com/reference#processimageuploadrequest
+func (i *IdentityMindAPIClient) UploadApplicationDocumentVerificationImageStreamWithContext(ctx context.Context, applicationID string, upload *DocumentUpload) (interface{}, error) {
 	var resp map[string]interface{}
-	status, err := i.PostMultipartFormData(fmt.Sprintf("im/account/consumer/%s/dv", applicationID), params, &resp)
+	status, err := i.postDocumentUpload(ctx, fmt.Sprintf("im/account/consumer/%s/dv", applicationID), upload, &resp)
 	if err != nil {
-		return nil, fmt.Errorf("Failed to upload consumer KYC document image for verification via identitymind API; status: %d; %s", status, err.Error())
+		return nil, fmt.Errorf("Failed to upload consumer KYC document image for verification via identitymind API; status: %d; %w", status, err)
 	}
 	return resp, nil
 }
 
 // ApproveApplication see https://edoc.identitymind.com/reference#feedback
 func (i *IdentityMindAPIClient) ApproveApplication(applicationID string, params map[string]interface{}) (interface{}, error) {
+	return i.ApproveApplicationWithContext(context.Background(), applicationID, params)
+}
+
+// ApproveApplicationWithContext see https://edoc.identitymind.com/reference#feedback
+func (i *IdentityMindAPIClient) ApproveApplicationWithContext(ctx context.Context, applicationID string, params map[string]interface{}) (interface{}, error) {
 	var resp map[string]interface{}
-	status, err := i.Post(fmt.Sprintf("im/account/consumer/%s/accepted", applicationID), params, &resp)
+	status, err := i.PostWithContext(ctx, fmt.Sprintf("im/account/consumer/%s/accepted", applicationID), params, &resp)
 	if err != nil {
-		return nil, fmt.Errorf("Failed to approve KYC application via identitymind API; status: %d; %s", status, err.Error())
+		return nil, fmt.Errorf("Failed to approve KYC application via identitymind API; status: %d; %w", status, err)
 	}
 	return resp, nil
 }
 
 // RejectApplication see https://edoc.identitymind.com/reference#feedback
 func (i *IdentityMindAPIClient) RejectApplication(applicationID string, params map[string]interface{}) (interface{}, error) {
+	return i.RejectApplicationWithContext(context.Background(), applicationID, params)
+}
+
+// RejectApplicationWithContext see https://edoc.identitymind.com/reference#feedback
+func (i *IdentityMindAPIClient) RejectApplicationWithContext(ctx context.Context, applicationID string, params map[string]interface{}) (interface{}, error) {
 	var resp map[string]interface{}
-	status, err := i.Post(fmt.Sprintf("im/account/consumer/%s/rejected", applicationID), params, &resp)
+	status, err := i.PostWithContext(ctx, fmt.Sprintf("im/account/consumer/%s/rejected", applicationID), params, &resp)
 	if err != nil {
-		return nil, fmt.Errorf("Failed to reject KYC application via identitymind API; status: %d; %s", status, err.Error())
+		return nil, fmt.Errorf("Failed to reject KYC application via identitymind API; status: %d; %w", status, err)
 	}
 	return resp, nil
 }
 
 // UndecideApplication see https://edoc.identitymind.com/reference#feedback
 func (i *IdentityMindAPIClient) UndecideApplication(applicationID string, params map[string]interface{}) (interface{}, error) {
+	return i.UndecideApplicationWithContext(context.Background(), applicationID, params)
+}
+
+// UndecideApplicationWithContext see https://edoc.identitymind.com/reference#feedback
+func (i *IdentityMindAPIClient) UndecideApplicationWithContext(ctx context.Context, applicationID string, params map[string]interface{}) (interface{}, error) {
 	var resp map[string]interface{}
-	status, err := i.Post(fmt.Sprintf("im/account/consumer/%s/review", applicationID), params, &resp)
+	status, err := i.PostWithContext(ctx, fmt.Sprintf("im/account/consumer/%s/review", applicationID), params, &resp)
 	if err != nil {
-		return nil, fmt.Errorf("Failed to undecide KYC application via identitymind API; status: %d; %s", status, err.Error())
+		return nil, fmt.Errorf("Failed to undecide KYC application via identitymind API; status: %d; %w", status, err)
 	}
 	return resp, nil
 }
@@ -0,0 +1,163 @@
+package identitymind
+
+import (
+	"encoding/json"
+	"net/http"
+	"reflect"
+	"sync/atomic"
+	"testing"
+)
+
+func TestSubmitConsumerApplication(t *testing.T) {
+	tests := []struct {
+		name        string
+		application *ConsumerApplicationRequest
+		expected    map[string]interface{}
+	}{
+		{
+			name:        "account name only",
+			application: &ConsumerApplicationRequest{AccountName: "alice"},
+			expected:    map[string]interface{}{"man": "alice"},
+		},
+		{
+			name: "identity, address, document and face images",
+			application: &ConsumerApplicationRequest{
+				AccountName:     "alice",
+				FirstName:       "Alice",
+				LastName:        "Smith",
+				DateOfBirth:     "1980-01-01",
+				Street:          "1 Main St",
+				Country:         "US",
+				Email:           "alice@example.com",
+				DocumentType:    DocumentTypePassport,
+				DocumentCountry: "US",
+				FaceImages:      []string{"aGVsbG8="},
+			},
+			expected: map[string]interface{}{
+				"man":        "alice",
+				"bfn":        "Alice",
+				"bln":        "Smith",
+				"dob":        "1980-01-01",
+				"bsn":        "1 Main St",
+				"bco":        "US",
+				"tea":        "alice@example.com",
+				"docType":    "PP",
+				"docCountry": "US",
+				"faceImages": []interface{}{"aGVsbG8="},
+			},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var params map[string]interface{}
+			client, srv := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+				if r.Method != http.MethodPost || r.URL.Path != "/im/account/consumer" {
+					t.Errorf("unexpected %s %s", r.Method, r.URL.Path)
+				}
+				if contentType := r.Header.Get("Content-Type"); contentType != "application/json" {
+					t.Errorf("expected application/json; got %s", contentType)
+				}
+				if err := json.NewDecoder(r.Body).Decode(&params); err != nil {
+					t.Error(err)
+				}
+				w.Write([]byte(`{"tid":"app-1","state":"R"}`))
+			})
+			defer srv.Close()
+
+			_, err := client.SubmitConsumerApplication(test.application)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(params, test.expected) {
+				t.Errorf("expected %v; got %v", test.expected, params)
+			}
+		})
+	}
+}
+
+func TestSubmitConsumerApplicationRequiresAccountName(t *testing.T) {
+	var requests int32
+	client, srv := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&requests, 1)
+	})
+	defer srv.Close()
+
+	for _, application := range []*ConsumerApplicationRequest{nil, {FirstName: "Alice"}} {
+		_, err := client.SubmitConsumerApplication(application)
+		if err == nil {
+			t.Errorf("expected %v to be rejected", application)
+		}
+	}
+	if requests != 0 {
+		t.Errorf("expected no requests; got %d", requests)
+	}
+}
+
+func TestGetApplicationDecodesScorecard(t *testing.T) {
+	client, srv := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{
+			"mtid": "app-1",
+			"tid": "app-1",
+			"state": "D",
+			"frp": "DENY",
+			"frn": "Deny on sanctions",
+			"res": "DENY",
+			"user": "BAD",
+			"ednaScoreCard": {
+				"sc": [
+					{"test": "ss:1", "fired": true, "details": "[+] sanctions match", "ts": 1577934245000},
+					{"test": "ed:1", "fired": false}
+				],
+				"etr": [
+					{"test": "dv:0", "fired": true, "condition": {"left": "dv:0", "operator": "eq", "right": true}},
+					null
+				],
+				"er": {
+					"profile": "DEFAULT",
+					"reportedRule": {
+						"name": "Deny on sanctions",
+						"ruleId": 1002,
+						"resultCode": "DENY",
+						"testResults": [{"test": "ss:1", "fired": true, "waitingForData": false}]
+					}
+				},
+				"ar": {"result": "FAIL", "ruleId": "7", "ruleName": "Manual review"}
+			}
+		}`))
+	})
+	defer srv.Close()
+
+	app, err := client.GetApplication("app-1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !app.IsRejected() || app.FRP == nil || *app.FRP != PolicyResultDeny || *app.User != "BAD" {
+		t.Errorf("unexpected application result; %v", app)
+	}
+	scorecard := app.EDNAScorecard
+	if scorecard == nil || len(scorecard.SC) != 2 || len(scorecard.ETR) != 2 {
+		t.Fatalf("expected 2 security and 2 external test results; got %v", scorecard)
+	}
+	if *scorecard.SC[0].Timestamp != 1577934245000 || *scorecard.ETR[0].Condition.Operator != "eq" || scorecard.ETR[0].Condition.Right != true {
+		t.Errorf("unexpected test result fields; %v", scorecard.SC[0])
+	}
+	rule := scorecard.ER.ReportedRule
+	if *scorecard.ER.Profile != "DEFAULT" || *rule.RuleID != 1002 || *rule.ResultCode != "DENY" || *scorecard.AR.RuleID != "7" {
+		t.Errorf("unexpected evaluated rules; %v", scorecard.ER)
+	}
+
+	var fired []string
+	for _, result := range app.FiredTests() {
+		fired = append(fired, *result.Test)
+	}
+	if !reflect.DeepEqual(fired, []string{"ss:1", "dv:0", "ss:1"}) {
+		t.Errorf("expected the fired security, external and reported rule tests; got %v", fired)
+	}
+}
+
+func TestFiredTestsWithoutScorecard(t *testing.T) {
+	app := &KYCApplication{}
+	if fired := app.FiredTests(); fired == nil || len(fired) != 0 {
+		t.Errorf("expected no fired tests; got %v", fired)
+	}
+}
@@ -1,35 +1,54 @@
 package identitymind
 
-import "fmt"
+import (
+	"context"
+	"fmt"
+	"io"
+)
 
 // Merchant aggregation
 
 // CreateMerchant creates a merchant account
 func (i *IdentityMindAPIClient) CreateMerchant(params map[string]interface{}) (interface{}, error) {
+	return i.CreateMerchantWithContext(context.Background(), params)
+}
+
+// CreateMerchantWithContext creates a merchant account
+func (i *IdentityMindAPIClient) CreateMerchantWithContext(ctx context.Context, params map[string]interface{}) (interface{}, error) {
 	var resp map[string]interface{}
-	status, err := i.Post("im/admin/jax/merchant", params, &resp)
+	status, err := i.PostWithContext(ctx, "im/admin/jax/merchant", params, &resp)
 	if err != nil {
-		return nil, fmt.Errorf("Failed to create merchant account via identitymind API; status: %d; %s", status, err.Error())
+		return nil, fmt.Errorf("Failed to create merchant account via identitymind API; status: %d; %w", status, err)
 	}
 	return resp, nil
 }
 
-// GetMerchant creates a merchant account
+// GetMerchant fetches a merchant account
 func (i *IdentityMindAPIClient) GetMerchant(merchantID string, params map[string]interface{}) (interface{}, error) {
+	return i.GetMerchantWithContext(context.Background(), merchantID, params)
+}
+
+// GetMerchantWithContext fetches a merchant account
+func (i *IdentityMindAPIClient) GetMerchantWithContext(ctx context.Context, merchantID string, params map[string]interface{}) (interface{}, error) {
 	var resp map[string]interface{}
-	status, err := i.Get(fmt.Sprintf("im/admin/jax/merchant/%s", merchantID), params, &resp)
+	status, err := i.GetWithContext(ctx, fmt.Sprintf("im/admin/jax/merchant/%s", merchantID), params, &resp)
 	if err != nil {
-		return nil, fmt.Errorf("Failed to fetch merchant account via identitymind API; status: %d; %s", status, err.Error())
+		return nil, fmt.Errorf("Failed to fetch merchant account via identitymind API; status: %d; %w", status, err)
 	}
 	return resp, nil
 }
 
-// UpdateMerchant creates a merchant account
+// UpdateMerchant updates a merchant account
 func (i *IdentityMindAPIClient) UpdateMerchant(merchantID string, params map[string]interface{}) (interface{}, error) {
+	return i.UpdateMerchantWithContext(context.Background(), merchantID, params)
+}
+
+// UpdateMerchantWithContext updates a merchant account
+func (i *IdentityMindAPIClient) UpdateMerchantWithContext(ctx context.Context, merchantID string, params map[string]interface{}) (interface{}, error) {
 	var resp map[string]interface{}
-	status, err := i.Post(fmt.Sprintf("im/admin/jax/merchant/%s", merchantID), params, &resp)
+	status, err := i.PostWithContext(ctx, fmt.Sprintf("im/admin/jax/merchant/%s", merchantID), params, &resp)
 	if err != nil {
-		return nil, fmt.Errorf("Failed to update merchant account via identitymind API; status: %d; %s", status, err.Error())
+		return nil, fmt.Errorf("Failed to update merchant account via identitymind API; status: %d; %w", status, err)
 	}
 	return resp, nil
 }
@@ -38,100 +57,194 @@ func (i *IdentityMindAPIClient) UpdateMerchant(merchantID string, params map[str
 
 // GetMerchantApplication see https://edoc.identitymind.com/reference#getmerchantkyc
 func (i *IdentityMindAPIClient) GetMerchantApplication(applicationID string) (interface{}, error) {
+	return i.GetMerchantApplicationWithContext(context.Background(), applicationID)
+}
+
+// GetMerchantApplicationWithContext see https://edoc.identitymind.com/reference#getmerchantkyc
+func (i *IdentityMindAPIClient) GetMerchantApplicationWithContext(ctx context.Context, applicationID string) (interface{}, error) {
 	var resp map[string]interface{}
-	status, err := i.Get(fmt.Sprintf("im/account/merchant/%s", applicationID), map[string]interface{}{}, &resp)
+	status, err := i.GetWithContext(ctx, fmt.Sprintf("im/account/merchant/%s", applicationID), map[string]interface{}{}, &resp)
 	if err != nil {
-		return nil, fmt.Errorf("Failed to retrieve merchant KYC application via identitymind API; status: %d; %s", status, err.Error())
+		return nil, fmt.Errorf("Failed to retrieve merchant KYC application via identitymind API; status: %d; %w", status, err)
 	}
 	return resp, nil
 }
 
 // SubmitMerchantApplication see https://edoc.identitymind.com/reference#create
 func (i *IdentityMindAPIClient) SubmitMerchantApplication(params map[string]interface{}) (interface{}, error) {
+	return i.SubmitMerchantApplicationWithContext(context.Background(), params)
+}
+
+// SubmitMerchantApplicationWithContext see https://edoc.identitymind.com/reference#create
+func (i *IdentityMindAPIClient) SubmitMerchantApplicationWithContext(ctx context.Context, params map[string]interface{}) (interface{}, error) {
 	var resp map[string]interface{}
-	status, err := i.Post("im/account/merchant?graphScoreResponse=false", params, &resp)
+	status, err := i.PostWithContext(ctx, "im/account/merchant?graphScoreResponse=false", params, &resp)
 	if err != nil {
-		return nil, fmt.Errorf("Failed to upload merchant KYC document via identitymind API; status: %d; %s", status, err.Error())
+		return nil, fmt.Errorf("Failed to upload merchant KYC document via identitymind API; status: %d; %w", status, err)
 	}
 	return resp, nil
 }
 
 // ListMerchantApplicationDocuments see https://edoc.identitymind.com/reference#getfilelistforapplication
-func (i *IdentityMindAPIClient) ListMerchantApplicationDocuments(applicationID string) (interface{}, error) {
-	var resp map[string]interface{}
-	status, err := i.Get(fmt.Sprintf("im/account/merchant/%s/files", applicationID), map[string]interface{}{}, &resp)
+func (i *IdentityMindAPIClient) ListMerchantApplicationDocuments(applicationID string) ([]*DocumentInfo, error) {
+	return i.ListMerchantApplicationDocumentsWithContext(context.Background(), applicationID)
+}
+
+// ListMerchantApplicationDocumentsWithContext see https://edoc.identitymind.com/reference#getfilelistforapplication
+func (i *IdentityMindAPIClient) ListMerchantApplicationDocumentsWithContext(ctx context.Context, applicationID string) ([]*DocumentInfo, error) {
+	var resp *documentList
+	status, err := i.GetWithContext(ctx, fmt.Sprintf("im/account/merchant/%s/files", applicationID), map[string]interface{}{}, &resp)
 	if err != nil {
-		return nil, fmt.Errorf("Failed to list merchant KYC documents via identitymind API; status: %d; %s", status, err.Error())
+		return nil, fmt.Errorf("Failed to list merchant KYC documents via identitymind API; status: %d; %w", status, err)
 	}
-	return resp, nil
+	return resp.documents(), nil
 }
 
 // DownloadMerchantApplicationDocument see https://edoc.identitymind.com/reference#reevaluatemerchant
 func (i *IdentityMindAPIClient) DownloadMerchantApplicationDocument(applicationID, documentID string) (interface{}, error) {
+	return i.DownloadMerchantApplicationDocumentWithContext(context.Background(), applicationID, documentID)
+}
+
+// DownloadMerchantApplicationDocumentWithContext see https://edoc.identitymind.com/reference#reevaluatemerchant
+func (i *IdentityMindAPIClient) DownloadMerchantApplicationDocumentWithContext(ctx context.Context, applicationID, documentID string) (interface{}, error) {
 	var resp map[string]interface{}
-	status, err := i.Get(fmt.Sprintf("im/account/merchant/%s/files/%s", applicationID, documentID), map[string]interface{}{}, &resp)
+	status, err := i.GetWithContext(ctx, fmt.Sprintf("im/account/merchant/%s/files/%s", applicationID, documentID), map[string]interface{}{}, &resp)
 	if err != nil {
-		return nil, fmt.Errorf("Failed to download merchant KYC document via identitymind API; status: %d; %s", status, err.Error())
+		return nil, fmt.Errorf("Failed to download merchant KYC document via identitymind API; status: %d; %w", status, err)
 	}
 	return resp, nil
 }
 
+// DownloadMerchantApplicationDocumentTo streams the raw content of the given document of the merchant KYC application to w and returns its metadata; see https://edoc.identitymind.com/reference#reevaluatemerchant
+func (i *IdentityMindAPIClient) DownloadMerchantApplicationDocumentTo(applicationID, documentID string, w io.Writer) (*DocumentDownload, error) {
+	return i.DownloadMerchantApplicationDocumentToWithContext(context.Background(), applicationID, documentID, w)
+}
+
+// DownloadMerchantApplicationDocumentToWithContext streams the raw content of the given document of the merchant KYC application to w and returns its metadata; see https://edoc.identitymind.com/reference#reevaluatemerchant
+func (i *IdentityMindAPIClient) DownloadMerchantApplicationDocumentToWithContext(ctx context.Context, applicationID, documentID string, w io.Writer) (*DocumentDownload, error) {
+	download, status, err := i.getDocumentDownload(ctx, fmt.Sprintf("im/account/merchant/%s/files/%s", applicationID, documentID), w)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to download merchant KYC document via identitymind API; status: %d; %w", status, err)
+	}
+	return download, nil
+}
+
 // UploadMerchantApplicationDocument see https://edoc.identitymind.com/reference#processfileuploadrequest
 func (i *IdentityMindAPIClient) UploadMerchantApplicationDocument(applicationID string, params map[string]interface{}) (interface{}, error) {
+	return i.UploadMerchantApplicationDocumentWithContext(context.Background(), applicationID, params)
+}
+
+// UploadMerchantApplicationDocumentWithContext see https://edoc.identitymind.com/reference#processfileuploadrequest
+func (i *IdentityMindAPIClient) UploadMerchantApplicationDocumentWithContext(ctx context.Context, applicationID string, params map[string]interface{}) (interface{}, error) {
+	var resp map[string]interface{}
+	status, err := i.PostMultipartFormDataWithContext(ctx, fmt.Sprintf("im/account/merchant/%s/files", applicationID), params, &resp)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to upload merchant merchant KYC document via identitymind API; status: %d; %w", status, err)
+	}
+	return resp, nil
+}
+
+// UploadMerchantApplicationDocumentStream streams the given document to the merchant KYC application without buffering it in memory; the upload is not retried; see https://edoc.identitymind.com/reference#processfileuploadrequest
+func (i *IdentityMindAPIClient) UploadMerchantApplicationDocumentStream(applicationID string, upload *DocumentUpload) (interface{}, error) {
+	return i.UploadMerchantApplicationDocumentStreamWithContext(context.Background(), applicationID, upload)
+}
+
+// UploadMerchantApplicationDocumentStreamWithContext streams the given document to the merchant KYC application without buffering it in memory; the upload is not retried; see https://edoc.identitymind.com/reference#processfileuploadrequest
+func (i *IdentityMindAPIClient) UploadMerchantApplicationDocumentStreamWithContext(ctx context.Context, applicationID string, upload *DocumentUpload) (interface{}, error) {
 	var resp map[string]interface{}
-	status, err := i.PostMultipartFormData(fmt.Sprintf("im/account/merchant/%s/files", applicationID), params, &resp)
+	status, err := i.postDocumentUpload(ctx, fmt.Sprintf("im/account/merchant/%s/files", applicationID), upload, &resp)
 	if err != nil {
-		return nil, fmt.Errorf("Failed to upload merchant merchant KYC document via identitymind API; status: %d; %s", status, err.Error())
+		return nil, fmt.Errorf("Failed to upload merchant KYC document via identitymind API; status: %d; %w", status, err)
 	}
 	return resp, nil
 }
 
 // UploadMerchantApplicationDocumentVerificationImage see https://edoc.identitymind.com/reference#processimageuploadrequest
 func (i *IdentityMindAPIClient) UploadMerchantApplicationDocumentVerificationImage(applicationID string, params map[string]interface{}) (interface{}, error) {
+	return i.UploadMerchantApplicationDocumentVerificationImageWithContext(context.Background(), applicationID, params)
+}
+
+// UploadMerchantApplicationDocumentVerificationImageWithContext see https://edoc.identitymind.com/reference#processimageuploadrequest
+func (i *IdentityMindAPIClient) UploadMerchantApplicationDocumentVerificationImageWithContext(ctx context.Context, applicationID string, params map[string]interface{}) (interface{}, error) {
+	var resp map[string]interface{}
+	status, err := i.PostMultipartFormDataWithContext(ctx, fmt.Sprintf("im/account/merchant/%s/dv", applicationID), params, &resp)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to upload merchant merchant KYC document image for verification via identitymind API; status: %d; %w", status, err)
+	}
+	return resp, nil
+}
+
+// UploadMerchantApplicationDocumentVerificationImageStream streams the given document to the merchant KYC application for verification without buffering it in memory; the upload is not retried; see https://edoc.identitymind.com/reference#processimageuploadrequest
+func (i *IdentityMindAPIClient) UploadMerchantApplicationDocumentVerificationImageStream(applicationID string, upload *DocumentUpload) (interface{}, error) {
+	return i.UploadMerchantApplicationDocumentVerificationImageStreamWithContext(context.Background(), applicationID, upload)
+}
+
+// UploadMerchantApplicationDocumentVerificationImageStreamWithContext streams the given document to the merchant KYC application for verification without buffering it in memory; the upload is not retried; see https://edoc.identitymind.com/reference#processimageuploadrequest
+func (i *IdentityMindAPIClient) UploadMerchantApplicationDocumentVerificationImageStreamWithContext(ctx context.Context, applicationID string, upload *DocumentUpload) (interface{}, error) {
 	var resp map[string]interface{}
-	status, err := i.PostMultipartFormData(fmt.Sprintf("im/account/merchant/%s/dv", applicationID), params, &resp)
+	status, err := i.postDocumentUpload(ctx, fmt.Sprintf("im/account/merchant/%s/dv", applicationID), upload, &resp)
 	if err != nil {
-		return nil, fmt.Errorf("Failed to upload merchant merchant KYC document image for verification via identitymind API; status: %d; %s", status, err.Error())
+		return nil, fmt.Errorf("Failed to upload merchant KYC document image for verification via identitymind API; status: %d; %w", status, err)
 	}
 	return resp, nil
 }
 
 // ApproveMerchantApplication see https://edoc.identitymind.com/reference#feedback
 func (i *IdentityMindAPIClient) ApproveMerchantApplication(applicationID string, params map[string]interface{}) (interface{}, error) {
+	return i.ApproveMerchantApplicationWithContext(context.Background(), applicationID, params)
+}
+
+// ApproveMerchantApplicationWithContext see https://edoc.identitymind.com/reference#feedback
+func (i *IdentityMindAPIClient) ApproveMerchantApplicationWithContext(ctx context.Context, applicationID string, params map[string]interface{}) (interface{}, error) {
 	var resp map[string]interface{}
-	status, err := i.Post(fmt.Sprintf("im/account/merchant/%s/accepted", applicationID), params, &resp)
+	status, err := i.PostWithContext(ctx, fmt.Sprintf("im/account/merchant/%s/accepted", applicationID), params, &resp)
 	if err != nil {
-		return nil, fmt.Errorf("Failed to approve merchant KYC application via identitymind API; status: %d; %s", status, err.Error())
+		return nil, fmt.Errorf("Failed to approve merchant KYC application via identitymind API; status: %d; %w", status, err)
 	}
 	return resp, nil
 }
 
 // RejectMerchantApplication see https://edoc.identitymind.com/reference#feedback
 func (i *IdentityMindAPIClient) RejectMerchantApplication(applicationID string, params map[string]interface{}) (interface{}, error) {
+	return i.RejectMerchantApplicationWithContext(context.Background(), applicationID, params)
+}
+
+// RejectMerchantApplicationWithContext see https://edoc.identitymind.com/reference#feedback
+func (i *IdentityMindAPIClient) RejectMerchantApplicationWithContext(ctx context.Context, applicationID string, params map[string]interface{}) (interface{}, error) {
 	var resp map[string]interface{}
-	status, err := i.Post(fmt.Sprintf("im/account/merchant/%s/rejected", applicationID), params, &resp)
+	status, err := i.PostWithContext(ctx, fmt.Sprintf("im/account/merchant/%s/rejected", applicationID), params, &resp)
 	if err != nil {
-		return nil, fmt.Errorf("Failed to reject merchant KYC application via identitymind API; status: %d; %s", status, err.Error())
+		return nil, fmt.Errorf("Failed to reject merchant KYC application via identitymind API; status: %d; %w", status, err)
 	}
 	return resp, nil
 }
 
 // UndecideMerchantApplication see https://edoc.identitymind.com/reference#feedback
 func (i *IdentityMindAPIClient) UndecideMerchantApplication(applicationID string, params map[string]interface{}) (interface{}, error) {
+	return i.UndecideMerchantApplicationWithContext(context.Background(), applicationID, params)
+}
+
+// UndecideMerchantApplicationWithContext see https://edoc.identitymind.com/reference#feedback
+func (i *IdentityMindAPIClient) UndecideMerchantApplicationWithContext(ctx context.Context, applicationID string, params map[string]interface{}) (interface{}, error) {
 	var resp map[string]interface{}
-	status, err := i.Post(fmt.Sprintf("im/account/merchant/%s/review", applicationID), params, &resp)
+	status, err := i.PostWithContext(ctx, fmt.Sprintf("im/account/merchant/%s/review", applicationID), params, &resp)
 	if err != nil {
-		return nil, fmt.Errorf("Failed to undecide merchant KYC application via identitymind API; status: %d; %s", status, err.Error())
+		return nil, fmt.Errorf("Failed to undecide merchant KYC application via identitymind API; status: %d; %w", status, err)
 	}
 	return resp, nil
 }
 
 // ProvideMerchantApplicationResponse see https://edoc.identitymind.com/reference#quizresponse_1
 func (i *IdentityMindAPIClient) ProvideMerchantApplicationResponse(applicationID string, params map[string]interface{}) (interface{}, error) {
+	return i.ProvideMerchantApplicationResponseWithContext(context.Background(), applicationID, params)
+}
+
+// ProvideMerchantApplicationResponseWithContext see https://edoc.identitymind.com/reference#quizresponse_1
+func (i *IdentityMindAPIClient) ProvideMerchantApplicationResponseWithContext(ctx context.Context, applicationID string, params map[string]interface{}) (interface{}, error) {
 	var resp map[string]interface{}
-	status, err := i.Post(fmt.Sprintf("im/account/merchant/%s/quizresponse", applicationID), params, &resp)
+	status, err := i.PostWithContext(ctx, fmt.Sprintf("im/account/merchant/%s/quizresponse", applicationID), params, &resp)
 	if err != nil {
-		return nil, fmt.Errorf("Failed to reject merchant KYC application via identitymind API; status: %d; %s", status, err.Error())
+		return nil, fmt.Errorf("Failed to reject merchant KYC application via identitymind API; status: %d; %w", status, err)
 	}
 	return resp, nil
 }
@@ -140,126 +253,278 @@ func (i *IdentityMindAPIClient) ProvideMerchantApplicationResponse(applicationID
 
 // RejectMerchantBusinessApplication see https://edoc.identitymind.com/reference#feedback_1
 func (i *IdentityMindAPIClient) RejectMerchantBusinessApplication(applicationID string, params map[string]interface{}) (interface{}, error) {
+	return i.RejectMerchantBusinessApplicationWithContext(context.Background(), applicationID, params)
+}
+
+// RejectMerchantBusinessApplicationWithContext see https://edoc.identitymind.com/reference#feedback_1
+func (i *IdentityMindAPIClient) RejectMerchantBusinessApplicationWithContext(ctx context.Context, applicationID string, params map[string]interface{}) (interface{}, error) {
 	var resp map[string]interface{}
-	status, err := i.Post(fmt.Sprintf("im/account/merchant/%s/rejected", applicationID), params, &resp)
+	status, err := i.PostWithContext(ctx, fmt.Sprintf("im/account/merchant/%s/rejected", applicationID), params, &resp)
 	if err != nil {
-		return nil, fmt.Errorf("Failed to reject merchant KYB application via identitymind API; status: %d; %s", status, err.Error())
+		return nil, fmt.Errorf("Failed to reject merchant KYB application via identitymind API; status: %d; %w", status, err)
 	}
 	return resp, nil
 }
 
 // UndecideMerchantBusinessApplication see https://edoc.identitymind.com/reference#feedback_1
 func (i *IdentityMindAPIClient) UndecideMerchantBusinessApplication(applicationID string, params map[string]interface{}) (interface{}, error) {
+	return i.UndecideMerchantBusinessApplicationWithContext(context.Background(), applicationID, params)
+}
+
+// UndecideMerchantBusinessApplicationWithContext see https://edoc.identitymind.com/reference#feedback_1
+func (i *IdentityMindAPIClient) UndecideMerchantBusinessApplicationWithContext(ctx context.Context, applicationID string, params map[string]interface{}) (interface{}, error) {
 	var resp map[string]interface{}
-	status, err := i.Post(fmt.Sprintf("im/account/merchant/%s/review", applicationID), params, &resp)
+	status, err := i.PostWithContext(ctx, fmt.Sprintf("im/account/merchant/%s/review", applicationID), params, &resp)
 	if err != nil {
-		return nil, fmt.Errorf("Failed to undecide merchant KYB application via identitymind API; status: %d; %s", status, err.Error())
+		return nil, fmt.Errorf("Failed to undecide merchant KYB application via identitymind API; status: %d; %w", status, err)
 	}
 	return resp, nil
 }
 
 // GetMerchantBusinessApplication see https://edoc.identitymind.com/reference#getmerchantkyc
-func (i *IdentityMindAPIClient) GetMerchantBusinessApplication(applicationID string) (interface{}, error) {
-	var resp map[string]interface{}
-	status, err := i.Get(fmt.Sprintf("im/account/merchant/%s", applicationID), map[string]interface{}{}, &resp)
+func (i *IdentityMindAPIClient) GetMerchantBusinessApplication(applicationID string) (*BusinessApplication, error) {
+	return i.GetMerchantBusinessApplicationWithContext(context.Background(), applicationID)
+}
+
+// GetMerchantBusinessApplicationWithContext see https://edoc.identitymind.com/reference#getmerchantkyc
+func (i *IdentityMindAPIClient) GetMerchantBusinessApplicationWithContext(ctx context.Context, applicationID string) (*BusinessApplication, error) {
+	var resp *BusinessApplication
+	status, err := i.GetWithContext(ctx, fmt.Sprintf("im/account/merchant/%s", applicationID), map[string]interface{}{}, &resp)
 	if err != nil {
-		return nil, fmt.Errorf("Failed to retrieve merchant KYB application via identitymind API; status: %d; %s", status, err.Error())
+		return nil, fmt.Errorf("Failed to retrieve merchant KYB application via identitymind API; status: %d; %w", status, err)
 	}
 	return resp, nil
 }
 
 // ReevaluateMerchantBusinessApplication see https://edoc.identitymind.com/reference#reevaluatemerchant
-func (i *IdentityMindAPIClient) ReevaluateMerchantBusinessApplication(applicationID string) (interface{}, error) {
-	var resp map[string]interface{}
-	status, err := i.Post(fmt.Sprintf("im/account/merchant/%s", applicationID), map[string]interface{}{}, &resp)
+func (i *IdentityMindAPIClient) ReevaluateMerchantBusinessApplication(applicationID string) (*BusinessApplication, error) {
+	return i.ReevaluateMerchantBusinessApplicationWithContext(context.Background(), applicationID)
+}
+
+// ReevaluateMerchantBusinessApplicationWithContext see https://edoc.identitymind.com/reference#reevaluatemerchant
+func (i *IdentityMindAPIClient) ReevaluateMerchantBusinessApplicationWithContext(ctx context.Context, applicationID string) (*BusinessApplication, error) {
+	var resp *BusinessApplication
+	status, err := i.PostWithContext(ctx, fmt.Sprintf("im/account/merchant/%s", applicationID), map[string]interface{}{}, &resp)
 	if err != nil {
-		return nil, fmt.Errorf("Failed to reevaluate merchant KYB application via identitymind API; status: %d; %s", status, err.Error())
+		return nil, fmt.Errorf("Failed to reevaluate merchant KYB application via identitymind API; status: %d; %w", status, err)
 	}
 	return resp, nil
 }
 
 // SubmitMerchantBusinessApplication see https://edoc.identitymind.com/reference#merchant
-func (i *IdentityMindAPIClient) SubmitMerchantBusinessApplication(merchantID string, params map[string]interface{}) (interface{}, error) {
-	var resp map[string]interface{}
+func (i *IdentityMindAPIClient) SubmitMerchantBusinessApplication(merchantID string, params map[string]interface{}) (*BusinessApplication, error) {
+	return i.SubmitMerchantBusinessApplicationWithContext(context.Background(), merchantID, params)
+}
+
+// SubmitMerchantBusinessApplicationWithContext see https://edoc.identitymind.com/reference#merchant
+func (i *IdentityMindAPIClient) SubmitMerchantBusinessApplicationWithContext(ctx context.Context, merchantID string, params map[string]interface{}) (*BusinessApplication, error) {
+	var resp *BusinessApplication
 	params["m"] = merchantID
-	status, err := i.Post(fmt.Sprintf("im/account/merchant?graphScoreResponse=false"), params, &resp)
+	status, err := i.PostWithContext(ctx, fmt.Sprintf("im/account/merchant?graphScoreResponse=false"), params, &resp)
 	if err != nil {
-		return nil, fmt.Errorf("Failed to reevaluate merchant KYB application via identitymind API; status: %d; %s", status, err.Error())
+		return nil, fmt.Errorf("Failed to submit merchant KYB application via identitymind API; status: %d; %w", status, err)
 	}
 	return resp, nil
 }
 
+// SubmitMerchantKYBApplication submits the given typed KYB application on behalf of the given merchant; see https://edoc.identitymind.com/reference#merchant
+func (i *IdentityMindAPIClient) SubmitMerchantKYBApplication(merchantID string, application *BusinessApplicationRequest) (*BusinessApplication, error) {
+	return i.SubmitMerchantKYBApplicationWithContext(context.Background(), merchantID, application)
+}
+
+// SubmitMerchantKYBApplicationWithContext submits the given typed KYB application on behalf of the given merchant; see https://edoc.identitymind.com/reference#merchant
+func (i *IdentityMindAPIClient) SubmitMerchantKYBApplicationWithContext(ctx context.Context, merchantID string, application *BusinessApplicationRequest) (*BusinessApplication, error) {
+	if application == nil {
+		return nil, fmt.Errorf("Failed to submit merchant KYB application; application is required")
+	}
+	if err := application.Validate(); err != nil {
+		return nil, fmt.Errorf("Failed to submit merchant KYB application; %s", err.Error())
+	}
+	params, err := marshalParams(application)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to marshal merchant KYB application; %s", err.Error())
+	}
+	return i.SubmitMerchantBusinessApplicationWithContext(ctx, merchantID, params)
+}
+
 // ListMerchantBusinessApplicationDocuments see https://edoc.identitymind.com/reference#getfilelistforapplicationformerchant
-func (i *IdentityMindAPIClient) ListMerchantBusinessApplicationDocuments(applicationID string) (interface{}, error) {
-	var resp map[string]interface{}
-	status, err := i.Get(fmt.Sprintf("im/account/merchant/%s/files", applicationID), map[string]interface{}{}, &resp)
+func (i *IdentityMindAPIClient) ListMerchantBusinessApplicationDocuments(applicationID string) ([]*DocumentInfo, error) {
+	return i.ListMerchantBusinessApplicationDocumentsWithContext(context.Background(), applicationID)
+}
+
+// ListMerchantBusinessApplicationDocumentsWithContext see https://edoc.identitymind.com/reference#getfilelistforapplicationformerchant
+func (i *IdentityMindAPIClient) ListMerchantBusinessApplicationDocumentsWithContext(ctx context.Context, applicationID string) ([]*DocumentInfo, error) {
+	var resp *documentList
+	status, err := i.GetWithContext(ctx, fmt.Sprintf("im/account/merchant/%s/files", applicationID), map[string]interface{}{}, &resp)
 	if err != nil {
-		return nil, fmt.Errorf("Failed to list merchant KYB documents via identitymind API; status: %d; %s", status, err.Error())
+		return nil, fmt.Errorf("Failed to list merchant KYB documents via identitymind API; status: %d; %w", status, err)
 	}
-	return resp, nil
+	return resp.documents(), nil
 }
 
 // DownloadMerchantBusinessApplicationDocument see https://edoc.identitymind.com/reference#reevaluatemerchant
 func (i *IdentityMindAPIClient) DownloadMerchantBusinessApplicationDocument(applicationID, documentID string) (interface{}, error) {
+	return i.DownloadMerchantBusinessApplicationDocumentWithContext(context.Background(), applicationID, documentID)
+}
+
+// DownloadMerchantBusinessApplicationDocumentWithContext see https://edoc.identitymind.com/reference#reevaluatemerchant
+func (i *IdentityMindAPIClient) DownloadMerchantBusinessApplicationDocumentWithContext(ctx context.Context, applicationID, documentID string) (interface{}, error) {
 	var resp map[string]interface{}
-	status, err := i.Get(fmt.Sprintf("im/account/merchant/%s/files/%s", applicationID, documentID), map[string]interface{}{}, &resp)
+	status, err := i.GetWithContext(ctx, fmt.Sprintf("im/account/merchant/%s/files/%s", applicationID, documentID), map[string]interface{}{}, &resp)
 	if err != nil {
-		return nil, fmt.Errorf("Failed to download merchant KYB document via identitymind API; status: %d; %s", status, err.Error())
+		return nil, fmt.Errorf("Failed to download merchant KYB document via identitymind API; status: %d; %w", status, err)
 	}
 	return resp, nil
 }
 
+// DownloadMerchantBusinessApplicationDocumentTo streams the raw content of the given document of the merchant KYB application to w and returns its metadata; see https://edoc.identitymind.com/reference#reevaluatemerchant
+func (i *IdentityMindAPIClient) DownloadMerchantBusinessApplicationDocumentTo(applicationID, documentID string, w io.Writer) (*DocumentDownload, error) {
+	return i.DownloadMerchantBusinessApplicationDocumentToWithContext(context.Background(), applicationID, documentID, w)
+}
+
+// DownloadMerchantBusinessApplicationDocumentToWithContext streams the raw content of the given document of the merchant KYB application to w and returns its metadata; see https://edoc.identitymind.com/reference#reevaluatemerchant
+func (i *IdentityMindAPIClient) DownloadMerchantBusinessApplicationDocumentToWithContext(ctx context.Context, applicationID, documentID string, w io.Writer) (*DocumentDownload, error) {
+	download, status, err := i.getDocumentDownload(ctx, fmt.Sprintf("im/account/merchant/%s/files/%s", applicationID, documentID), w)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to download merchant KYB document via identitymind API; status: %d; %w", status, err)
+	}
+	return download, nil
+}
+
 // UploadMerchantBusinessApplicationDocument see https://edoc.identitymind.com/reference#processfileuploadrequestformerchantkyc
 func (i *IdentityMindAPIClient) UploadMerchantBusinessApplicationDocument(applicationID string, params map[string]interface{}) (interface{}, error) {
+	return i.UploadMerchantBusinessApplicationDocumentWithContext(context.Background(), applicationID, params)
+}
+
+// UploadMerchantBusinessApplicationDocumentWithContext see https://edoc.identitymind.com/reference#processfileuploadrequestformerchantkyc
+func (i *IdentityMindAPIClient) UploadMerchantBusinessApplicationDocumentWithContext(ctx context.Context, applicationID string, params map[string]interface{}) (interface{}, error) {
+	var resp map[string]interface{}
+	status, err := i.PostMultipartFormDataWithContext(ctx, fmt.Sprintf("im/account/merchant/%s/files", applicationID), params, &resp)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to upload merchant KYB document via identitymind API; status: %d; %w", status, err)
+	}
+	return resp, nil
+}
+
+// UploadMerchantBusinessApplicationDocumentStream streams the given document to the merchant KYB application without buffering it in memory; the upload is not retried; see https://edoc.identitymind.com/reference#processfileuploadrequestformerchantkyc
+func (i *IdentityMindAPIClient) UploadMerchantBusinessApplicationDocumentStream(applicationID string, upload *DocumentUpload) (interface{}, error) {
+	return i.UploadMerchantBusinessApplicationDocumentStreamWithContext(context.Background(), applicationID, upload)
+}
+
+// UploadMerchantBusinessApplicationDocumentStreamWithContext streams the given document to the merchant KYB application without buffering it in memory; the upload is not retried; see https://edoc.identitymind.com/reference#processfileuploadrequestformerchantkyc
+func (i *IdentityMindAPIClient) UploadMerchantBusinessApplicationDocumentStreamWithContext(ctx context.Context, applicationID string, upload *DocumentUpload) (interface{}, error) {
 	var resp map[string]interface{}
-	status, err := i.PostMultipartFormData(fmt.Sprintf("im/account/merchant/%s/files", applicationID), params, &resp)
+	status, err := i.postDocumentUpload(ctx, fmt.Sprintf("im/account/merchant/%s/files", applicationID), upload, &resp)
 	if err != nil {
-		return nil, fmt.Errorf("Failed to upload merchant KYB document via identitymind API; status: %d; %s", status, err.Error())
+		return nil, fmt.Errorf("Failed to upload merchant KYB document via identitymind API; status: %d; %w", status, err)
 	}
 	return resp, nil
 }
 
 // UploadMerchantBusinessApplicationDocumentVerificationImage see https://edoc.identitymind.com/reference#processfileuploadrequestformerchantkyc
 func (i *IdentityMindAPIClient) UploadMerchantBusinessApplicationDocumentVerificationImage(applicationID string, params map[string]interface{}) (interface{}, error) {
+	return i.UploadMerchantBusinessApplicationDocumentVerificationImageWithContext(context.Background(), applicationID, params)
+}
+
+// UploadMerchantBusinessApplicationDocumentVerificationImageWithContext see https://edoc.identitymind.com/reference#processfileuploadrequestformerchantkyc
+func (i *IdentityMindAPIClient) UploadMerchantBusinessApplicationDocumentVerificationImageWithContext(ctx context.Context, applicationID string, params map[string]interface{}) (interface{}, error) {
 	var resp map[string]interface{}
-	status, err := i.PostMultipartFormData(fmt.Sprintf("im/account/merchant/%s/dv", applicationID), params, &resp)
+	status, err := i.PostMultipartFormDataWithContext(ctx, fmt.Sprintf("im/account/merchant/%s/dv", applicationID), params, &resp)
 	if err != nil {
-		return nil, fmt.Errorf("Failed to upload KYB document image for verification via identitymind API; status: %d; %s", status, err.Error())
+		return nil, fmt.Errorf("Failed to upload KYB document image for verification via identitymind API; status: %d; %w", status, err)
+	}
+	return resp, nil
+}
+
+// UploadMerchantBusinessApplicationDocumentVerificationImageStream streams the given document to the merchant KYB application for verification without buffering it in memory; the upload is not retried; see https://edoc.identitymind.com/reference#processfileuploadrequestformerchantkyc
+func (i *IdentityMindAPIClient) UploadMerchantBusinessApplicationDocumentVerificationImageStream(applicationID string, upload *DocumentUpload) (interface{}, error) {
+	return i.UploadMerchantBusinessApplicationDocumentVerificationImageStreamWithContext(context.Background(), applicationID, upload)
+}
+
+// UploadMerchantBusinessApplicationDocumentVerificationImageStreamWithContext streams the given document to the merchant KYB application for verification without buffering it in memory; the upload is not retried; see https://edoc.identitymind.com/reference#processfileuploadrequestformerchantkyc
+func (i *IdentityMindAPIClient) UploadMerchantBusinessApplicationDocumentVerificationImageStreamWithContext(ctx context.Context, applicationID string, upload *DocumentUpload) (interface{}, error) {
+	var resp map[string]interface{}
+	status, err := i.postDocumentUpload(ctx, fmt.Sprintf("im/account/merchant/%s/dv", applicationID), upload, &resp)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to upload merchant KYB document image for verification via identitymind API; status: %d; %w", status, err)
 	}
 	return resp, nil
 }
 
 // ApproveMerchantBusinessApplication see https://edoc.identitymind.com/reference#feedback_1
 func (i *IdentityMindAPIClient) ApproveMerchantBusinessApplication(applicationID string, params map[string]interface{}) (interface{}, error) {
+	return i.ApproveMerchantBusinessApplicationWithContext(context.Background(), applicationID, params)
+}
+
+// ApproveMerchantBusinessApplicationWithContext see https://edoc.identitymind.com/reference#feedback_1
+func (i *IdentityMindAPIClient) ApproveMerchantBusinessApplicationWithContext(ctx context.Context, applicationID string, params map[string]interface{}) (interface{}, error) {
 	var resp map[string]interface{}
-	status, err := i.Post(fmt.Sprintf("im/account/merchant/%s/accepted", applicationID), params, &resp)
+	status, err := i.PostWithContext(ctx, fmt.Sprintf("im/account/merchant/%s/accepted", applicationID), params, &resp)
 	if err != nil {
-		return nil, fmt.Errorf("Failed to acccept merchant KYB application via identitymind API; status: %d; %s", status, err.Error())
+		return nil, fmt.Errorf("Failed to acccept merchant KYB application via identitymind API; status: %d; %w", status, err)
 	}
 	return resp, nil
 }
 
 // EvaluateMerchantFraud evaluates a transaction for payment fraud on behalf of a given merchant; see https://edoc.identitymind.com/reference#anti-fraud-1
-func (i *IdentityMindAPIClient) EvaluateMerchantFraud(merchantID string, params map[string]interface{}) (interface{}, error) {
-	var resp map[string]interface{}
+func (i *IdentityMindAPIClient) EvaluateMerchantFraud(merchantID string, params map[string]interface{}) (*TransactionResult, error) {
+	return i.EvaluateMerchantFraudWithContext(context.Background(), merchantID, params)
+}
+
+// EvaluateMerchantFraudWithContext evaluates a transaction for payment fraud on behalf of a given merchant; see https://edoc.identitymind.com/reference#anti-fraud-1
+func (i *IdentityMindAPIClient) EvaluateMerchantFraudWithContext(ctx context.Context, merchantID string, params map[string]interface{}) (*TransactionResult, error) {
+	var resp *TransactionResult
 	params["m"] = merchantID
-	status, err := i.Post(fmt.Sprintf("im/transaction?graphScoreResponse=false"), params, &resp)
+	status, err := i.PostWithContext(ctx, fmt.Sprintf("im/transaction?graphScoreResponse=false"), params, &resp)
 	if err != nil {
-		return nil, fmt.Errorf("Failed to evaluate tx for payment fraud via identitymind API; status: %d; %s", status, err.Error())
+		return nil, fmt.Errorf("Failed to evaluate tx for payment fraud via identitymind API; status: %d; %w", status, err)
 	}
 	return resp, nil
 }
 
+// EvaluateMerchantPayment evaluates the given payment for fraud on behalf of a given merchant; see https://edoc.identitymind.com/reference#anti-fraud-1
+func (i *IdentityMindAPIClient) EvaluateMerchantPayment(merchantID string, payment *PaymentTransaction) (*TransactionResult, error) {
+	return i.EvaluateMerchantPaymentWithContext(context.Background(), merchantID, payment)
+}
+
+// EvaluateMerchantPaymentWithContext evaluates the given payment for fraud on behalf of a given merchant; see https://edoc.identitymind.com/reference#anti-fraud-1
+func (i *IdentityMindAPIClient) EvaluateMerchantPaymentWithContext(ctx context.Context, merchantID string, payment *PaymentTransaction) (*TransactionResult, error) {
+	params, err := paymentParams(payment)
+	if err != nil {
+		return nil, err
+	}
+	return i.EvaluateMerchantFraudWithContext(ctx, merchantID, params)
+}
+
 // ReportMerchantTransaction reports various kinds of transactions including deposits, withdrawals and internal transfer
-func (i *IdentityMindAPIClient) ReportMerchantTransaction(merchantID, txType string, params map[string]interface{}) (interface{}, error) {
-	if txType != IdentityMindTxTypeDeposit && txType != IdentityMindTxTypeWithdrawal && txType != IdentityMindTxTypeTransfer {
+func (i *IdentityMindAPIClient) ReportMerchantTransaction(merchantID, txType string, params map[string]interface{}) (*TransactionResult, error) {
+	return i.ReportMerchantTransactionWithContext(context.Background(), merchantID, txType, params)
+}
+
+// ReportMerchantTransactionWithContext reports various kinds of transactions including deposits, withdrawals and internal transfer
+func (i *IdentityMindAPIClient) ReportMerchantTransactionWithContext(ctx context.Context, merchantID, txType string, params map[string]interface{}) (*TransactionResult, error) {
+	if !IsValidTxType(txType) {
 		return nil, fmt.Errorf("Invalid tx type provided: %s", txType)
 	}
 	params["m"] = merchantID
-	var resp map[string]interface{}
-	status, err := i.Post(fmt.Sprintf("im/account/%s?graphScoreResponse=false", txType), params, &resp)
+	var resp *TransactionResult
+	status, err := i.PostWithContext(ctx, fmt.Sprintf("im/account/%s?graphScoreResponse=false", txType), params, &resp)
 	if err != nil {
-		return nil, fmt.Errorf("Failed to report tx via identitymind API; status: %d; %s", status, err.Error())
+		return nil, fmt.Errorf("Failed to report tx via identitymind API; status: %d; %w", status, err)
 	}
 	return resp, nil
 }
+
+// ReportMerchantTransfer reports the given deposit (TransferIn), withdrawal (TransferOut) or internal transfer (Transfer) on behalf of a given merchant; transfers and withdrawals are checked against the TravelRulePolicy of the client, if any
+func (i *IdentityMindAPIClient) ReportMerchantTransfer(merchantID string, transfer TransferRequest) (*TransactionResult, error) {
+	return i.ReportMerchantTransferWithContext(context.Background(), merchantID, transfer)
+}
+
+// ReportMerchantTransferWithContext reports the given deposit (TransferIn), withdrawal (TransferOut) or internal transfer (Transfer) on behalf of a given merchant; transfers and withdrawals are checked against the TravelRulePolicy of the client, if any
+func (i *IdentityMindAPIClient) ReportMerchantTransferWithContext(ctx context.Context, merchantID string, transfer TransferRequest) (*TransactionResult, error) {
+	params, err := transferParams(transfer, i.TravelRulePolicy)
+	if err != nil {
+		return nil, err
+	}
+	return i.ReportMerchantTransactionWithContext(ctx, merchantID, transfer.TxType(), params)
+}
@@ -1,25 +1,653 @@
 package identitymind
 
-// KYCApplication represents a identitymind KYC application
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// KYCApplication represents a identitymind KYC application evaluation; see https://edoc.identitymind.com/reference#kyc-response
 type KYCApplication struct {
-	EDNAScorecard map[string]interface{} `json:"ednaScoreCard"`
-	MTID          *string                `json:"mtid"`
-	TID           *string                `json:"tid"`
-	RCD           *string                `json:"rcd"`
-	State         *string                `json:"state"`
+	EDNAScorecard *EDNAScorecard    `json:"ednaScoreCard"`
+	MTID          *string           `json:"mtid"`
+	TID           *string           `json:"tid"`
+	RCD           *string           `json:"rcd"`
+	State         *ApplicationState `json:"state"`
+	FRP           *PolicyResult     `json:"frp"`  // fraud policy result, i.e. ACCEPT, DENY or MANUAL_REVIEW
+	FRN           *string           `json:"frn"`  // name of the fraud rule which determined the policy result
+	FRD           *string           `json:"frd"`  // description of the fraud rule which determined the policy result
+	RES           *PolicyResult     `json:"res"`  // result of the policy evaluation, i.e. ACCEPT, DENY or MANUAL_REVIEW
+	User          *Reputation       `json:"user"` // current reputation of the user, i.e. TRUSTED, UNKNOWN, SUSPICIOUS or BAD
+	UPR           *Reputation       `json:"upr"`  // previous reputation of the user
+	ERD           *string           `json:"erd"`  // description of the reason for the user reputation
+}
+
+// EDNAScorecard represents the eDNA scorecard attached to a identitymind application evaluation
+type EDNAScorecard struct {
+	SC  []*EDNATestResult    `json:"sc"`  // security tests
+	ER  *EDNAEvaluatedRules  `json:"er"`  // evaluated (fraud policy) rules
+	AR  *EDNAAutomatedReview `json:"ar"`  // automated review rules
+	ETR []*EDNATestResult    `json:"etr"` // external test results
+}
+
+// FiredTests returns the security, external and reported rule test results which fired
+func (s *EDNAScorecard) FiredTests() []*EDNATestResult {
+	fired := make([]*EDNATestResult, 0)
+	if s == nil {
+		return fired
+	}
+
+	results := make([]*EDNATestResult, 0)
+	results = append(results, s.SC...)
+	results = append(results, s.ETR...)
+	if s.ER != nil && s.ER.ReportedRule != nil {
+		results = append(results, s.ER.ReportedRule.TestResults...)
+	}
+
+	for _, result := range results {
+		if result != nil && result.Fired != nil && *result.Fired {
+			fired = append(fired, result)
+		}
+	}
+	return fired
+}
+
+// DescribeReasonCode returns a human-readable description of the given result code, describing the code of
+// the fraud policy rule reported by the scorecard using the description, or name, of the rule
+func (s *EDNAScorecard) DescribeReasonCode(code ReasonCode) string {
+	if s != nil && s.ER != nil && s.ER.ReportedRule != nil && s.ER.ReportedRule.RuleID != nil {
+		rule := s.ER.ReportedRule
+		if strconv.FormatInt(*rule.RuleID, 10) == string(code) {
+			if rule.Description != nil && *rule.Description != "" {
+				return *rule.Description
+			}
+			if rule.Name != nil && *rule.Name != "" {
+				return *rule.Name
+			}
+		}
+	}
+	return code.Description()
+}
+
+// EDNAEvaluatedRules represents the fraud policy rules evaluated against an application
+type EDNAEvaluatedRules struct {
+	Profile      *string           `json:"profile"`
+	ReportedRule *EDNAReportedRule `json:"reportedRule"`
+}
+
+// EDNAReportedRule represents the rule which determined the result of a policy evaluation
+type EDNAReportedRule struct {
+	Name        *string           `json:"name"`
+	Description *string           `json:"description"`
+	Details     *string           `json:"details"`
+	RuleID      *int64            `json:"ruleId"`
+	ResultCode  *string           `json:"resultCode"`
+	TestResults []*EDNATestResult `json:"testResults"`
+}
+
+// EDNAAutomatedReview represents the result of the automated review rules evaluated against an application
+type EDNAAutomatedReview struct {
+	Result          *string `json:"result"`
+	RuleID          *string `json:"ruleId"`
+	RuleName        *string `json:"ruleName"`
+	RuleDescription *string `json:"ruleDescription"`
+}
+
+// EDNATestResult represents the result of a single eDNA test, i.e. ed:1 or ss:2
+type EDNATestResult struct {
+	Test           *string            `json:"test"`
+	Fired          *bool              `json:"fired"`
+	Details        *string            `json:"details"`
+	Stage          *string            `json:"stage"`
+	Timestamp      *int64             `json:"ts"`
+	WaitingForData *bool              `json:"waitingForData"`
+	Condition      *EDNATestCondition `json:"condition"`
+}
+
+// EDNATestCondition represents the condition against which an eDNA test was evaluated
+type EDNATestCondition struct {
+	Left     interface{} `json:"left"`
+	Operator *string     `json:"operator"`
+	Right    interface{} `json:"right"`
+}
+
+// FiredTests returns the eDNA test results which fired during evaluation of the KYC application
+func (k *KYCApplication) FiredTests() []*EDNATestResult {
+	return k.EDNAScorecard.FiredTests()
+}
+
+// CurrentState returns the state of the KYC application, or an empty state if none was provided
+func (k *KYCApplication) CurrentState() ApplicationState {
+	if k.State == nil {
+		return ApplicationState("")
+	}
+	return *k.State
+}
+
+// ReasonCodes returns the parsed result codes of the KYC application evaluation
+func (k *KYCApplication) ReasonCodes() []ReasonCode {
+	if k.RCD == nil {
+		return make([]ReasonCode, 0)
+	}
+	return ParseReasonCodes(*k.RCD)
 }
 
 // IsAccepted returns true if the KYC application has been accepted
 func (k *KYCApplication) IsAccepted() bool {
-	return k.State != nil && *k.State == "A"
+	return k.CurrentState().IsAccepted()
 }
 
 // IsRejected returns true if the KYC application has been rejected
 func (k *KYCApplication) IsRejected() bool {
-	return k.State != nil && *k.State == "D"
+	return k.CurrentState().IsRejected()
 }
 
 // IsUnderReview returns true if the KYC application is currently pending review
 func (k *KYCApplication) IsUnderReview() bool {
-	return k.State != nil && *k.State == "R"
+	return k.CurrentState().IsUnderReview()
+}
+
+// IsDecided returns true if the KYC application has been accepted or rejected
+func (k *KYCApplication) IsDecided() bool {
+	return k.CurrentState().IsTerminal()
+}
+
+// DocumentTypePassport is the identitymind document type for a passport
+const DocumentTypePassport = "PP"
+
+// DocumentTypeDriversLicense is the identitymind document type for a driver's license
+const DocumentTypeDriversLicense = "DL"
+
+// DocumentTypeGovernmentID is the identitymind document type for a government-issued identity card
+const DocumentTypeGovernmentID = "ID"
+
+// DocumentTypeResidencePermit is the identitymind document type for a residence permit
+const DocumentTypeResidencePermit = "RP"
+
+// DocumentTypeUtilityBill is the identitymind document type for a utility bill
+const DocumentTypeUtilityBill = "UB"
+
+// ConsumerApplicationRequest represents a identitymind consumer KYC application; see https://edoc.identitymind.com/reference#create
+type ConsumerApplicationRequest struct {
+	// Identity
+	AccountName   string `json:"man"`               // unique identifier of the applicant's account; required
+	ApplicationID string `json:"tid,omitempty"`     // caller-assigned application identifier; assigned by identitymind when omitted
+	Title         string `json:"title,omitempty"`   // applicant title, i.e. Mr, Ms
+	FirstName     string `json:"bfn,omitempty"`     // applicant first name
+	MiddleName    string `json:"bmn,omitempty"`     // applicant middle name
+	LastName      string `json:"bln,omitempty"`     // applicant last name
+	Gender        string `json:"bgd,omitempty"`     // applicant gender; M or F
+	DateOfBirth   string `json:"dob,omitempty"`     // applicant date of birth formatted as YYYY-MM-DD
+	NationalID    string `json:"assn,omitempty"`    // applicant social security number or other national identifier
+	Nationality   string `json:"nat,omitempty"`     // applicant nationality as an ISO 3166-1 alpha-2 country code
+	Profile       string `json:"profile,omitempty"` // policy profile against which the application is evaluated
+	Stage         string `json:"stage,omitempty"`   // stage of the onboarding workflow, i.e. 1, 2 or 3
+	Memo          string `json:"memo,omitempty"`    // free-form memo associated with the application
+
+	// Billing (residential) address
+	Street     string `json:"bsn,omitempty"`  // street address
+	City       string `json:"bc,omitempty"`   // city
+	State      string `json:"bs,omitempty"`   // state or province
+	PostalCode string `json:"bz,omitempty"`   // postal or zip code
+	Country    string `json:"bco,omitempty"`  // ISO 3166-1 alpha-2 country code
+	District   string `json:"bnbh,omitempty"` // neighborhood or district
+
+	// Shipping address
+	ShippingFirstName  string `json:"sfn,omitempty"` // shipping recipient first name
+	ShippingLastName   string `json:"sln,omitempty"` // shipping recipient last name
+	ShippingStreet     string `json:"ssn,omitempty"` // shipping street address
+	ShippingCity       string `json:"sc,omitempty"`  // shipping city
+	ShippingState      string `json:"ss,omitempty"`  // shipping state or province
+	ShippingPostalCode string `json:"sz,omitempty"`  // shipping postal or zip code
+	ShippingCountry    string `json:"sco,omitempty"` // shipping ISO 3166-1 alpha-2 country code
+
+	// Contact
+	Email       string `json:"tea,omitempty"` // applicant email address
+	Phone       string `json:"phn,omitempty"` // applicant phone number
+	MobilePhone string `json:"pm,omitempty"`  // applicant mobile phone number
+
+	// Document
+	DocumentType          string   `json:"docType,omitempty"`           // one of the DocumentType constants
+	DocumentCountry       string   `json:"docCountry,omitempty"`        // ISO 3166-1 alpha-2 code of the issuing country
+	DocumentState         string   `json:"docState,omitempty"`          // issuing state or province, where applicable
+	DocumentImage         string   `json:"scanData,omitempty"`          // base64-encoded image of the front of the document
+	DocumentBacksideImage string   `json:"backsideImageData,omitempty"` // base64-encoded image of the back of the document
+	FaceImages            []string `json:"faceImages,omitempty"`        // base64-encoded images of the applicant's face
+
+	// Device
+	IP                    string `json:"ip,omitempty"`                  // applicant IP address
+	DeviceFingerprint     string `json:"dfp,omitempty"`                 // device fingerprint
+	DeviceFingerprintType string `json:"dft,omitempty"`                 // device fingerprint type, i.e. AU (augur), CB (custom)
+	AccountCreationTime   string `json:"accountCreationTime,omitempty"` // time at which the account was created, formatted as ISO 8601
+}
+
+// BusinessApplication represents a identitymind KYB application evaluation; see https://edoc.identitymind.com/reference#merchant
+type BusinessApplication struct {
+	EDNAScorecard *EDNAScorecard    `json:"ednaScoreCard"`
+	MTID          *string           `json:"mtid"`
+	TID           *string           `json:"tid"`
+	RCD           *string           `json:"rcd"`
+	State         *ApplicationState `json:"state"`
+	FRP           *PolicyResult     `json:"frp"`  // fraud policy result, i.e. ACCEPT, DENY or MANUAL_REVIEW
+	FRN           *string           `json:"frn"`  // name of the fraud rule which determined the policy result
+	FRD           *string           `json:"frd"`  // description of the fraud rule which determined the policy result
+	RES           *PolicyResult     `json:"res"`  // result of the policy evaluation, i.e. ACCEPT, DENY or MANUAL_REVIEW
+	User          *Reputation       `json:"user"` // current reputation of the merchant, i.e. TRUSTED, UNKNOWN, SUSPICIOUS or BAD
+	UPR           *Reputation       `json:"upr"`  // previous reputation of the merchant
+	ERD           *string           `json:"erd"`  // description of the reason for the merchant reputation
+
+	Owners []*KYCApplication `json:"owners"` // evaluations of the beneficial owners, when returned with the business evaluation
+}
+
+// FiredTests returns the eDNA test results which fired during evaluation of the KYB application
+func (b *BusinessApplication) FiredTests() []*EDNATestResult {
+	return b.EDNAScorecard.FiredTests()
+}
+
+// CurrentState returns the state of the KYB application, or an empty state if none was provided
+func (b *BusinessApplication) CurrentState() ApplicationState {
+	if b.State == nil {
+		return ApplicationState("")
+	}
+	return *b.State
+}
+
+// ReasonCodes returns the parsed result codes of the KYB application evaluation
+func (b *BusinessApplication) ReasonCodes() []ReasonCode {
+	if b.RCD == nil {
+		return make([]ReasonCode, 0)
+	}
+	return ParseReasonCodes(*b.RCD)
+}
+
+// IsAccepted returns true if the KYB application has been accepted
+func (b *BusinessApplication) IsAccepted() bool {
+	return b.CurrentState().IsAccepted()
+}
+
+// IsRejected returns true if the KYB application has been rejected
+func (b *BusinessApplication) IsRejected() bool {
+	return b.CurrentState().IsRejected()
+}
+
+// IsUnderReview returns true if the KYB application is currently pending review
+func (b *BusinessApplication) IsUnderReview() bool {
+	return b.CurrentState().IsUnderReview()
+}
+
+// IsDecided returns true if the KYB application has been accepted or rejected
+func (b *BusinessApplication) IsDecided() bool {
+	return b.CurrentState().IsTerminal()
+}
+
+// OwnersDecided returns true if every beneficial owner evaluation returned with the KYB application
+// has been accepted or rejected
+func (b *BusinessApplication) OwnersDecided() bool {
+	for _, owner := range b.Owners {
+		if owner != nil && !owner.IsDecided() {
+			return false
+		}
+	}
+	return true
+}
+
+// RejectedOwners returns the beneficial owner evaluations returned with the KYB application which
+// have been rejected
+func (b *BusinessApplication) RejectedOwners() []*KYCApplication {
+	rejected := make([]*KYCApplication, 0)
+	for _, owner := range b.Owners {
+		if owner != nil && owner.IsRejected() {
+			rejected = append(rejected, owner)
+		}
+	}
+	return rejected
+}
+
+// BusinessApplicationRequest represents a identitymind KYB application; see https://edoc.identitymind.com/reference#merchant
+type BusinessApplicationRequest struct {
+	// Business
+	AccountName          string `json:"man"`                            // unique identifier of the business account; required
+	ApplicationID        string `json:"tid,omitempty"`                  // caller-assigned application identifier; assigned by identitymind when omitted
+	BusinessName         string `json:"bn,omitempty"`                   // registered legal name of the business
+	DoingBusinessAs      string `json:"dba,omitempty"`                  // trading name of the business, where it differs from the legal name
+	RegistrationNumber   string `json:"regNum,omitempty"`               // company registration number issued by the registry of incorporation
+	TaxID                string `json:"ataxid,omitempty"`               // tax identifier of the business, i.e. EIN or VAT number
+	IncorporationCountry string `json:"incorporationCountry,omitempty"` // ISO 3166-1 alpha-2 code of the country of incorporation
+	IncorporationState   string `json:"incorporationState,omitempty"`   // state or province of incorporation, where applicable
+	IncorporationDate    string `json:"incorporationDate,omitempty"`    // date of incorporation formatted as YYYY-MM-DD
+	BusinessType         string `json:"businessType,omitempty"`         // legal form of the business, i.e. LLC, CORP, PARTNERSHIP
+	Website              string `json:"website,omitempty"`              // URL of the business website
+	Profile              string `json:"profile,omitempty"`              // policy profile against which the application is evaluated
+	Memo                 string `json:"memo,omitempty"`                 // free-form memo associated with the application
+
+	// Registered address
+	Street     string `json:"bsn,omitempty"` // street address
+	City       string `json:"bc,omitempty"`  // city
+	State      string `json:"bs,omitempty"`  // state or province
+	PostalCode string `json:"bz,omitempty"`  // postal or zip code
+	Country    string `json:"bco,omitempty"` // ISO 3166-1 alpha-2 country code
+
+	// Contact
+	Email string `json:"tea,omitempty"` // business email address
+	Phone string `json:"phn,omitempty"` // business phone number
+	IP    string `json:"ip,omitempty"`  // IP address from which the application was submitted
+
+	// Owners and officers
+	Owners   []*BeneficialOwner `json:"owners,omitempty"`   // ultimate beneficial owners of the business
+	Officers []*BusinessOfficer `json:"officers,omitempty"` // directors and officers of the business
+}
+
+// BeneficialOwner represents an ultimate beneficial owner of a business, evaluated using its own KYC fields
+type BeneficialOwner struct {
+	ConsumerApplicationRequest
+	OwnershipPercentage float64 `json:"ownership,omitempty"` // percentage of the business owned, between 0 and 100
+
+	// KYCApplicationID, when set, identifies an existing KYC application (tid) of the owner; the owner is
+	// then submitted with the KYB application as a reference to it, without the owner's KYC fields
+	KYCApplicationID string `json:"-"`
+}
+
+// beneficialOwnerReference is the representation of a BeneficialOwner whose KYC application was submitted separately
+type beneficialOwnerReference struct {
+	TID                 string  `json:"tid"`
+	OwnershipPercentage float64 `json:"ownership,omitempty"`
+}
+
+// MarshalJSON implements json.Marshaler; an owner with a KYCApplicationID is marshaled as a reference
+// to that KYC application, so the owner's PII is not submitted a second time
+func (o BeneficialOwner) MarshalJSON() ([]byte, error) {
+	if o.KYCApplicationID != "" {
+		return json.Marshal(&beneficialOwnerReference{
+			TID:                 o.KYCApplicationID,
+			OwnershipPercentage: o.OwnershipPercentage,
+		})
+	}
+	type owner BeneficialOwner
+	return json.Marshal(owner(o))
+}
+
+// BusinessOfficer represents a director or officer of a business, evaluated using its own KYC fields
+type BusinessOfficer struct {
+	ConsumerApplicationRequest
+	Role string `json:"role,omitempty"` // role of the officer, i.e. CEO, CFO, DIRECTOR
+}
+
+// Validate returns an error if the KYB application is missing required fields
+func (r *BusinessApplicationRequest) Validate() error {
+	if r.AccountName == "" {
+		return fmt.Errorf("account name (man) is required")
+	}
+	total := float64(0)
+	for idx, owner := range r.Owners {
+		if owner == nil {
+			return fmt.Errorf("beneficial owner %d is nil", idx)
+		}
+		if owner.AccountName == "" && owner.KYCApplicationID == "" {
+			return fmt.Errorf("beneficial owner %d account name (man) or KYC application identifier is required", idx)
+		}
+		if owner.OwnershipPercentage < 0 || owner.OwnershipPercentage > 100 {
+			return fmt.Errorf("beneficial owner %d ownership percentage must be between 0 and 100", idx)
+		}
+		total += owner.OwnershipPercentage
+	}
+	if total > 100 {
+		return fmt.Errorf("beneficial owner ownership percentages total %.2f; must not exceed 100", total)
+	}
+	for idx, officer := range r.Officers {
+		if officer == nil {
+			return fmt.Errorf("officer %d is nil", idx)
+		}
+		if officer.AccountName == "" {
+			return fmt.Errorf("officer %d account name (man) is required", idx)
+		}
+	}
+	return nil
+}
+
+// TransactionResult represents a identitymind transaction evaluation; see https://edoc.identitymind.com/reference#transaction-response
+type TransactionResult struct {
+	EDNAScorecard *EDNAScorecard `json:"ednaScoreCard"`
+	TID           *string        `json:"tid"`
+	RCD           *string        `json:"rcd"`
+	FRP           *PolicyResult  `json:"frp"`   // fraud policy result, i.e. ACCEPT, DENY or MANUAL_REVIEW
+	FRN           *string        `json:"frn"`   // name of the fraud rule which determined the policy result
+	FRD           *string        `json:"frd"`   // description of the fraud rule which determined the policy result
+	RES           *PolicyResult  `json:"res"`   // result of the policy evaluation, i.e. ACCEPT, DENY or MANUAL_REVIEW
+	Score         *float64       `json:"score"` // fraud risk score of the transaction, when returned
+	User          *Reputation    `json:"user"`  // current reputation of the user, i.e. TRUSTED, UNKNOWN, SUSPICIOUS or BAD
+	UPR           *Reputation    `json:"upr"`   // previous reputation of the user
+	ERD           *string        `json:"erd"`   // description of the reason for the user reputation
+}
+
+// PolicyResult returns the fraud policy result of the transaction evaluation, falling back to the result
+// of the policy evaluation (res) when no fraud policy result was provided
+func (t *TransactionResult) PolicyResult() PolicyResult {
+	if t.FRP != nil {
+		return *t.FRP
+	}
+	if t.RES != nil {
+		return *t.RES
+	}
+	return PolicyResult("")
+}
+
+// IsAccepted returns true if the transaction was accepted by the fraud policy
+func (t *TransactionResult) IsAccepted() bool {
+	return t.PolicyResult() == PolicyResultAccept
+}
+
+// IsDenied returns true if the transaction was denied by the fraud policy
+func (t *TransactionResult) IsDenied() bool {
+	return t.PolicyResult() == PolicyResultDeny
+}
+
+// IsUnderReview returns true if the transaction was referred for manual review by the fraud policy
+func (t *TransactionResult) IsUnderReview() bool {
+	return t.PolicyResult() == PolicyResultManualReview
+}
+
+// FiredRule returns the fraud policy rule which determined the result of the transaction evaluation, if any
+func (t *TransactionResult) FiredRule() *EDNAReportedRule {
+	if t.EDNAScorecard == nil || t.EDNAScorecard.ER == nil {
+		return nil
+	}
+	return t.EDNAScorecard.ER.ReportedRule
+}
+
+// FiredTests returns the eDNA test results which fired during evaluation of the transaction
+func (t *TransactionResult) FiredTests() []*EDNATestResult {
+	return t.EDNAScorecard.FiredTests()
+}
+
+// ReasonCodes returns the parsed result codes of the transaction evaluation
+func (t *TransactionResult) ReasonCodes() []ReasonCode {
+	if t.RCD == nil {
+		return make([]ReasonCode, 0)
+	}
+	return ParseReasonCodes(*t.RCD)
+}
+
+// PaymentInstrumentTypeCard is the payment instrument type for a credit or debit card
+const PaymentInstrumentTypeCard = "CARD"
+
+// PaymentInstrumentTypeBankAccount is the payment instrument type for a bank account, i.e. ACH or wire
+const PaymentInstrumentTypeBankAccount = "BANK"
+
+// PaymentInstrumentTypeWallet is the payment instrument type for a third-party wallet, i.e. PayPal
+const PaymentInstrumentTypeWallet = "WALLET"
+
+// PaymentInstrumentTypeDigitalCurrency is the payment instrument type for a digital currency address
+const PaymentInstrumentTypeDigitalCurrency = "DC"
+
+// TransactionRequest holds the fields common to each typed identitymind transaction request
+type TransactionRequest struct {
+	AccountName   string `json:"man"`               // account name of the user initiating the transaction; required
+	TransactionID string `json:"tid,omitempty"`     // caller-assigned transaction identifier; assigned by identitymind when omitted
+	Amount        string `json:"amt"`               // decimal amount of the transaction, i.e. 25.00; required
+	Currency      string `json:"ccy"`               // ISO 4217 currency code or digital currency symbol, i.e. BTC, of the amount; required
+	Profile       string `json:"profile,omitempty"` // policy profile against which the transaction is evaluated
+	Memo          string `json:"memo,omitempty"`    // free-form memo associated with the transaction
+
+	// Contact
+	Email string `json:"tea,omitempty"` // email address of the user
+	Phone string `json:"phn,omitempty"` // phone number of the user
+
+	// Device
+	IP                    string `json:"ip,omitempty"`  // IP address from which the transaction was initiated
+	DeviceFingerprint     string `json:"dfp,omitempty"` // device fingerprint
+	DeviceFingerprintType string `json:"dft,omitempty"` // device fingerprint type, i.e. AU (augur), CB (custom)
+}
+
+// PaymentInstrument describes the external instrument funding or receiving a transaction
+type PaymentInstrument struct {
+	InstrumentType  string `json:"pm,omitempty"`   // one of the PaymentInstrumentType constants
+	CardHash        string `json:"pccn,omitempty"` // SHA-1 hash of the card number
+	CardToken       string `json:"pcct,omitempty"` // masked card number, i.e. the BIN and last four digits
+	BankAccountHash string `json:"pach,omitempty"` // SHA-1 hash of the bank routing and account numbers
+	BankCountry     string `json:"pbco,omitempty"` // ISO 3166-1 alpha-2 country code of the bank
+	WalletID        string `json:"pwid,omitempty"` // identifier of the account at the third-party wallet provider
+}
+
+// TransferIn represents a deposit into the account of the user from an external instrument; see https://edoc.identitymind.com/reference#transferin
+type TransferIn struct {
+	TransactionRequest
+	PaymentInstrument        // source of the deposit
+	*DigitalCurrencyTransfer // blockchain details, when depositing digital currency
+}
+
+// TransferOut represents a withdrawal from the account of the user to an external instrument; see https://edoc.identitymind.com/reference#transferout
+type TransferOut struct {
+	TransactionRequest
+	PaymentInstrument        // destination of the withdrawal
+	*DigitalCurrencyTransfer // blockchain details, when withdrawing digital currency
+
+	TravelRule *TravelRule `json:"-"` // originator and beneficiary information checked against the travel-rule policy, if any; not submitted
+}
+
+// Transfer represents an internal transfer between the account of the user and another account; see https://edoc.identitymind.com/reference#transfer
+type Transfer struct {
+	TransactionRequest
+	DestinationAccountName string      `json:"dman"` // account name of the user receiving the transfer; required
+	TravelRule             *TravelRule `json:"-"`    // originator and beneficiary information checked against the travel-rule policy, if any; not submitted
+}
+
+// PaymentTransaction represents a payment evaluated for fraud; see https://edoc.identitymind.com/reference#anti-fraud-1
+type PaymentTransaction struct {
+	TransactionRequest
+	PaymentInstrument // instrument funding the payment
+
+	// Billing address
+	FirstName  string `json:"bfn,omitempty"` // billing first name
+	LastName   string `json:"bln,omitempty"` // billing last name
+	Street     string `json:"bsn,omitempty"` // billing street address
+	City       string `json:"bc,omitempty"`  // billing city
+	State      string `json:"bs,omitempty"`  // billing state or province
+	PostalCode string `json:"bz,omitempty"`  // billing postal or zip code
+	Country    string `json:"bco,omitempty"` // billing ISO 3166-1 alpha-2 country code
+
+	// Shipping address
+	ShippingStreet     string `json:"ssn,omitempty"` // shipping street address
+	ShippingCity       string `json:"sc,omitempty"`  // shipping city
+	ShippingState      string `json:"ss,omitempty"`  // shipping state or province
+	ShippingPostalCode string `json:"sz,omitempty"`  // shipping postal or zip code
+	ShippingCountry    string `json:"sco,omitempty"` // shipping ISO 3166-1 alpha-2 country code
+}
+
+// TransferRequest is implemented by the typed TransferIn, TransferOut and Transfer requests
+type TransferRequest interface {
+	TxType() string  // one of the IdentityMindTxType constants
+	Validate() error // returns an error if the request is nil or invalid
+}
+
+// TxType returns IdentityMindTxTypeDeposit
+func (t *TransferIn) TxType() string {
+	return IdentityMindTxTypeDeposit
+}
+
+// Validate returns an error if the deposit is nil or missing required fields, or if its digital currency details are invalid
+func (t *TransferIn) Validate() error {
+	if t == nil {
+		return fmt.Errorf("deposit is required")
+	}
+	if err := t.TransactionRequest.Validate(); err != nil {
+		return err
+	}
+	return validateDigitalCurrencyTransfer(t.PaymentInstrument, t.DigitalCurrencyTransfer)
+}
+
+// TxType returns IdentityMindTxTypeWithdrawal
+func (t *TransferOut) TxType() string {
+	return IdentityMindTxTypeWithdrawal
+}
+
+// Validate returns an error if the withdrawal is nil or missing required fields, or if its digital currency details are invalid
+func (t *TransferOut) Validate() error {
+	if t == nil {
+		return fmt.Errorf("withdrawal is required")
+	}
+	if err := t.TransactionRequest.Validate(); err != nil {
+		return err
+	}
+	return validateDigitalCurrencyTransfer(t.PaymentInstrument, t.DigitalCurrencyTransfer)
+}
+
+// TxType returns IdentityMindTxTypeTransfer
+func (t *Transfer) TxType() string {
+	return IdentityMindTxTypeTransfer
+}
+
+// Validate returns an error if the transfer is nil, is missing required fields or transfers to the same account
+func (t *Transfer) Validate() error {
+	if t == nil {
+		return fmt.Errorf("transfer is required")
+	}
+	if err := t.TransactionRequest.Validate(); err != nil {
+		return err
+	}
+	if t.DestinationAccountName == "" {
+		return fmt.Errorf("destination account name (dman) is required")
+	}
+	if t.DestinationAccountName == t.AccountName {
+		return fmt.Errorf("destination account name (dman) must differ from account name (man)")
+	}
+	return nil
+}
+
+// Validate returns an error if the payment is nil or missing required fields
+func (p *PaymentTransaction) Validate() error {
+	if p == nil {
+		return fmt.Errorf("payment is required")
+	}
+	return p.TransactionRequest.Validate()
+}
+
+// validateDigitalCurrencyTransfer validates the digital currency details of a deposit or withdrawal, which are
+// required when the payment instrument is PaymentInstrumentTypeDigitalCurrency
+func validateDigitalCurrencyTransfer(instrument PaymentInstrument, transfer *DigitalCurrencyTransfer) error {
+	if transfer == nil {
+		if instrument.InstrumentType == PaymentInstrumentTypeDigitalCurrency {
+			return fmt.Errorf("digital currency details are required for payment instrument type %s", PaymentInstrumentTypeDigitalCurrency)
+		}
+		return nil
+	}
+	if instrument.InstrumentType != "" && instrument.InstrumentType != PaymentInstrumentTypeDigitalCurrency {
+		return fmt.Errorf("digital currency details provided for payment instrument type %s", instrument.InstrumentType)
+	}
+	return transfer.Validate()
+}
+
+// Validate returns an error if the transaction is missing required fields or the amount or currency is malformed
+func (r *TransactionRequest) Validate() error {
+	if r.AccountName == "" {
+		return fmt.Errorf("account name (man) is required")
+	}
+	amount, err := strconv.ParseFloat(r.Amount, 64)
+	if err != nil || amount <= 0 {
+		return fmt.Errorf("amount (amt) must be a positive decimal; %q provided", r.Amount)
+	}
+	if len(r.Currency) < 3 || len(r.Currency) > 6 || strings.TrimFunc(r.Currency, func(c rune) bool { return (c >= 'A' && c <= 'Z') || (c >= '0' && c <= '9') }) != "" {
+		return fmt.Errorf("currency (ccy) must be an ISO 4217 currency code or digital currency symbol; %q provided", r.Currency)
+	}
+	return nil
 }
@@ -0,0 +1,36 @@
+package identitymind
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestBeneficialOwnerMarshalJSON(t *testing.T) {
+	tests := []struct {
+		name     string
+		owner    *BeneficialOwner
+		expected string
+	}{
+		{
+			"submitted with the KYB application",
+			&BeneficialOwner{ConsumerApplicationRequest: ConsumerApplicationRequest{AccountName: "alice", FirstName: "Alice"}, OwnershipPercentage: 50},
+			`{"man":"alice","bfn":"Alice","ownership":50}`,
+		},
+		{
+			"reference to a KYC application",
+			&BeneficialOwner{ConsumerApplicationRequest: ConsumerApplicationRequest{AccountName: "bob", FirstName: "Bob"}, OwnershipPercentage: 25, KYCApplicationID: "kyc-1"},
+			`{"tid":"kyc-1","ownership":25}`,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			raw, err := json.Marshal(test.owner)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(raw) != test.expected {
+				t.Fatalf("expected %s; got %s", test.expected, raw)
+			}
+		})
+	}
+}
@@ -0,0 +1,170 @@
+package identitymind
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"time"
+
+	"github.com/kthomas/go-logger"
+)
+
+// EnvironmentSandbox is the identitymind sandbox integration environment
+const EnvironmentSandbox = "sandbox"
+
+// EnvironmentProduction is the identitymind production integration environment
+const EnvironmentProduction = "edna"
+
+// Option configures an IdentityMindAPIClient constructed via NewClient
+type Option func(*IdentityMindAPIClient) error
+
+// NewClient initializes an IdentityMindAPIClient using the given options; unless otherwise
+// configured, the client targets the sandbox environment, sends unauthenticated requests, bounds
+// each invocation to 30 seconds and retries transient failures of safe requests per DefaultRetryPolicy.
+// Options are applied in order, so later options take precedence over earlier ones.
+func NewClient(opts ...Option) (*IdentityMindAPIClient, error) {
+	client := &IdentityMindAPIClient{
+		Timeout:     defaultRequestTimeout,
+		RetryPolicy: DefaultRetryPolicy(),
+	}
+	err := WithEnvironment(identitymindDefaultEnvironment)(client)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, opt := range opts {
+		err := opt(client)
+		if err != nil {
+			client.logger().Warningf("Failed to configure identitymind API client; %s", err.Error())
+			return nil, err
+		}
+	}
+
+	return client, nil
+}
+
+// WithEnvironment targets the given identitymind integration environment, i.e. sandbox or edna;
+// see https://edoc.identitymind.com/reference#section-integration-environments
+func WithEnvironment(env string) Option {
+	return func(i *IdentityMindAPIClient) error {
+		if env == "" {
+			return fmt.Errorf("Invalid identitymind API environment provided")
+		}
+		return WithBaseURL(fmt.Sprintf("https://%s.identitymind.com", env))(i)
+	}
+}
+
+// WithBaseURL targets the identitymind API at the given base URL
+func WithBaseURL(baseURL string) Option {
+	return func(i *IdentityMindAPIClient) error {
+		apiURL, err := url.Parse(baseURL)
+		if err != nil {
+			return fmt.Errorf("Failed to parse identitymind API base url; %s", err.Error())
+		}
+		if apiURL.Scheme == "" || apiURL.Host == "" {
+			return fmt.Errorf("Invalid identitymind API base url: %s", baseURL)
+		}
+
+		i.Scheme = apiURL.Scheme
+		i.Host = apiURL.Host
+		i.Path = apiURL.Path
+		return nil
+	}
+}
+
+// WithBasicAuth authorizes requests using HTTP basic authentication with the given API user and token
+func WithBasicAuth(username, password string) Option {
+	return func(i *IdentityMindAPIClient) error {
+		i.Username = stringOrNil(username)
+		i.Password = stringOrNil(password)
+		i.Token = nil
+		return nil
+	}
+}
+
+// WithBearerToken authorizes requests using the given bearer token
+func WithBearerToken(token string) Option {
+	return func(i *IdentityMindAPIClient) error {
+		i.Token = stringOrNil(token)
+		i.Username = nil
+		i.Password = nil
+		return nil
+	}
+}
+
+// WithTimeout bounds the total duration of each API invocation, including retries and document
+// downloads; a zero timeout leaves invocations bounded only by the context provided by the caller
+func WithTimeout(timeout time.Duration) Option {
+	return func(i *IdentityMindAPIClient) error {
+		i.Timeout = timeout
+		return nil
+	}
+}
+
+// WithLogger uses the given logger in place of the package-level logger
+func WithLogger(lg *logger.Logger) Option {
+	return func(i *IdentityMindAPIClient) error {
+		i.Logger = lg
+		return nil
+	}
+}
+
+// WithHTTPClient sends all requests using the given HTTP client
+func WithHTTPClient(client *http.Client) Option {
+	return func(i *IdentityMindAPIClient) error {
+		i.HTTPClient = client
+		return nil
+	}
+}
+
+// WithTransport sends all requests using the given round tripper
+func WithTransport(transport http.RoundTripper) Option {
+	return func(i *IdentityMindAPIClient) error {
+		i.Transport = transport
+		return nil
+	}
+}
+
+// WithRetryPolicy governs the retry of transient failures using the given policy; nil disables retries
+func WithRetryPolicy(policy *RetryPolicy) Option {
+	return func(i *IdentityMindAPIClient) error {
+		i.RetryPolicy = policy
+		return nil
+	}
+}
+
+// WithTravelRulePolicy checks transfers and withdrawals reported via ReportTransfer against the given
+// travel-rule policy before submission, i.e. NewTravelRulePolicy("US", rates.Convert); nil disables the check
+func WithTravelRulePolicy(policy *TravelRulePolicy) Option {
+	return func(i *IdentityMindAPIClient) error {
+		if policy != nil {
+			if err := policy.Validate(); err != nil {
+				return fmt.Errorf("Invalid travel rule policy; %s", err.Error())
+			}
+		}
+		i.TravelRulePolicy = policy
+		return nil
+	}
+}
+
+// WithEnvironmentVariables configures the environment and basic auth credentials from the
+// IDENTITYMIND_API_ENVIRONMENT, IDENTITYMIND_API_USER and IDENTITYMIND_API_TOKEN environment
+// variables as they are set at the time the client is constructed; unset variables are ignored.
+func WithEnvironmentVariables() Option {
+	return func(i *IdentityMindAPIClient) error {
+		if env := os.Getenv("IDENTITYMIND_API_ENVIRONMENT"); env != "" {
+			err := WithEnvironment(env)(i)
+			if err != nil {
+				return err
+			}
+		}
+
+		user := os.Getenv("IDENTITYMIND_API_USER")
+		token := os.Getenv("IDENTITYMIND_API_TOKEN")
+		if user != "" || token != "" {
+			return WithBasicAuth(user, token)(i)
+		}
+		return nil
+	}
+}
@@ -0,0 +1,133 @@
+package identitymind
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/url"
+	"time"
+)
+
+// PollOptions configures the polling of an application until it has been decided
+type PollOptions struct {
+	InitialInterval time.Duration // delay before the first poll following the initial retrieval; defaults to 5 seconds
+	MaxInterval     time.Duration // upper bound on the delay between polls; defaults to 1 minute
+	Multiplier      float64       // factor by which the delay grows after each poll; defaults to 1.5
+	Jitter          float64       // fraction, between 0 and 1, of each delay which is randomized; defaults to 0.1
+
+	// OnTransition, when non-nil, is invoked each time the application is observed in a new state,
+	// including the state observed by the initial retrieval, in which case the From state is empty
+	OnTransition func(transition StateTransition, application *KYCApplication)
+
+	// OnBusinessTransition is the equivalent of OnTransition when waiting for a KYB application decision
+	OnBusinessTransition func(transition StateTransition, application *BusinessApplication)
+}
+
+// WaitForApplicationDecision polls the given KYC application until it has been accepted or rejected,
+// or until ctx is done, in which case the most recently retrieved application is returned along with
+// the context error; opts may be nil
+func (i *IdentityMindAPIClient) WaitForApplicationDecision(ctx context.Context, applicationID string, opts *PollOptions) (*KYCApplication, error) {
+	var application *KYCApplication
+	err := i.waitForDecision(ctx, opts, func(ctx context.Context) (ApplicationState, bool, error) {
+		current, err := i.GetApplicationWithContext(ctx, applicationID)
+		if err != nil || current == nil {
+			return ApplicationState(""), false, err
+		}
+		application = current
+		return current.CurrentState(), true, nil
+	}, func(transition StateTransition) {
+		if opts != nil && opts.OnTransition != nil {
+			opts.OnTransition(transition, application)
+		}
+	})
+	return application, err
+}
+
+// WaitForBusinessApplicationDecision polls the given KYB application until it has been accepted or rejected,
+// or until ctx is done, in which case the most recently retrieved application is returned along with
+// the context error; opts may be nil
+func (i *IdentityMindAPIClient) WaitForBusinessApplicationDecision(ctx context.Context, applicationID string, opts *PollOptions) (*BusinessApplication, error) {
+	var application *BusinessApplication
+	err := i.waitForDecision(ctx, opts, func(ctx context.Context) (ApplicationState, bool, error) {
+		current, err := i.GetBusinessApplicationWithContext(ctx, applicationID)
+		if err != nil || current == nil {
+			return ApplicationState(""), false, err
+		}
+		application = current
+		return current.CurrentState(), true, nil
+	}, func(transition StateTransition) {
+		if opts != nil && opts.OnBusinessTransition != nil {
+			opts.OnBusinessTransition(transition, application)
+		}
+	})
+	return application, err
+}
+
+// waitForDecision invokes fetch until it reports a terminal state; fetch returns false when no
+// application was retrieved, and notify is invoked each time a new state is observed
+func (i *IdentityMindAPIClient) waitForDecision(ctx context.Context, opts *PollOptions, fetch func(context.Context) (ApplicationState, bool, error), notify func(StateTransition)) error {
+	if opts == nil {
+		opts = &PollOptions{}
+	}
+	policy := &RetryPolicy{
+		InitialBackoff: opts.InitialInterval,
+		MaxBackoff:     opts.MaxInterval,
+		Multiplier:     opts.Multiplier,
+		Jitter:         opts.Jitter,
+	}
+	if policy.InitialBackoff <= 0 {
+		policy.InitialBackoff = 5 * time.Second
+	}
+	if policy.MaxBackoff <= 0 {
+		policy.MaxBackoff = time.Minute
+	}
+	if policy.Multiplier <= 0 {
+		policy.Multiplier = 1.5
+	}
+	if policy.Jitter <= 0 {
+		policy.Jitter = 0.1
+	}
+
+	state := ApplicationState("")
+
+	for attempt := 1; ; attempt++ {
+		next, ok, err := fetch(ctx)
+		if err != nil {
+			if ctx.Err() != nil {
+				return fmt.Errorf("Failed to wait for identitymind application decision; last observed state: %s; %w", state, ctx.Err())
+			}
+			if !isTransientPollError(err) {
+				return err
+			}
+			i.logger().Debugf("Failed to poll identitymind application for decision; will retry; %s", err.Error())
+		} else if ok {
+			if next != state {
+				notify(StateTransition{From: state, To: next})
+				state = next
+			}
+			if state.IsTerminal() {
+				return nil
+			}
+		}
+
+		timer := time.NewTimer(policy.backoff(attempt))
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return fmt.Errorf("Failed to wait for identitymind application decision; last observed state: %s; %w", state, ctx.Err())
+		case <-timer.C:
+		}
+	}
+}
+
+// isTransientPollError returns true if the given error may not recur when the application is next polled,
+// i.e. a 5xx or 429 response, or a transport error such as a reset connection or a per-attempt timeout
+func isTransientPollError(err error) bool {
+	if IsServerError(err) || IsRateLimited(err) {
+		return true
+	}
+	var urlErr *url.Error
+	var netErr net.Error
+	return errors.As(err, &urlErr) || errors.As(err, &netErr)
+}
@@ -0,0 +1,149 @@
+package identitymind
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// failingTransport fails the given number of requests before forwarding requests to the default transport
+type failingTransport struct {
+	failures int32
+}
+
+func (f *failingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if atomic.AddInt32(&f.failures, -1) >= 0 {
+		return nil, fmt.Errorf("connection reset by peer")
+	}
+	return http.DefaultTransport.RoundTrip(req)
+}
+
+func testPollOptions(transitions *[]StateTransition) *PollOptions {
+	return &PollOptions{
+		InitialInterval: time.Millisecond,
+		MaxInterval:     5 * time.Millisecond,
+		OnTransition: func(transition StateTransition, application *KYCApplication) {
+			*transitions = append(*transitions, transition)
+		},
+	}
+}
+
+func TestWaitForApplicationDecision(t *testing.T) {
+	tests := []struct {
+		name        string
+		responses   []int // status of each response; 200 responses report the corresponding state
+		states      []ApplicationState
+		failures    int32 // transport errors preceding the first response
+		transitions []StateTransition
+		err         bool
+	}{
+		{
+			name:        "review then accepted",
+			responses:   []int{200, 200, 200},
+			states:      []ApplicationState{"R", "R", "A"},
+			transitions: []StateTransition{{"", "R"}, {"R", "A"}},
+		},
+		{
+			name:        "transient server error",
+			responses:   []int{200, 503, 200},
+			states:      []ApplicationState{"R", "", "D"},
+			transitions: []StateTransition{{"", "R"}, {"R", "D"}},
+		},
+		{
+			name:        "transport errors",
+			responses:   []int{200},
+			states:      []ApplicationState{"A"},
+			failures:    2,
+			transitions: []StateTransition{{"", "A"}},
+		},
+		{
+			name:      "not found",
+			responses: []int{404},
+			states:    []ApplicationState{""},
+			err:       true,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var requests int32
+			client, srv := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+				idx := int(atomic.AddInt32(&requests, 1)) - 1
+				if idx >= len(test.responses) {
+					idx = len(test.responses) - 1
+				}
+				if test.responses[idx] != http.StatusOK {
+					w.WriteHeader(test.responses[idx])
+					return
+				}
+				fmt.Fprintf(w, `{"tid":"app-1","state":"%s"}`, string(test.states[idx]))
+			}, WithRetryPolicy(nil), WithTransport(&failingTransport{failures: test.failures}))
+			defer srv.Close()
+
+			var transitions []StateTransition
+			app, err := client.WaitForApplicationDecision(context.Background(), "app-1", testPollOptions(&transitions))
+			if test.err {
+				if !IsNotFound(err) {
+					t.Fatalf("expected a not found error; got %v", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !app.IsDecided() || int(requests) != len(test.responses) {
+				t.Errorf("expected a decision after %d requests; got %s after %d", len(test.responses), app.CurrentState(), requests)
+			}
+			if fmt.Sprint(transitions) != fmt.Sprint(test.transitions) {
+				t.Errorf("expected transitions %v; got %v", test.transitions, transitions)
+			}
+		})
+	}
+}
+
+func TestWaitForApplicationDecisionContextDone(t *testing.T) {
+	client, srv := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"tid":"app-1","state":"R"}`))
+	}, WithRetryPolicy(nil))
+	defer srv.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	var transitions []StateTransition
+	app, err := client.WaitForApplicationDecision(ctx, "app-1", testPollOptions(&transitions))
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("expected the context deadline to be exceeded; got %v", err)
+	}
+	if app == nil || !app.IsUnderReview() || len(transitions) != 1 {
+		t.Errorf("expected the application under review to be returned; got %v after %v", app, transitions)
+	}
+}
+
+func TestWaitForBusinessApplicationDecision(t *testing.T) {
+	var requests int32
+	client, srv := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&requests, 1) == 1 {
+			w.Write([]byte(`{"tid":"kyb-1","state":"R"}`))
+			return
+		}
+		w.Write([]byte(`{"tid":"kyb-1","state":"A"}`))
+	}, WithRetryPolicy(nil))
+	defer srv.Close()
+
+	var transitions []StateTransition
+	app, err := client.WaitForBusinessApplicationDecision(context.Background(), "kyb-1", &PollOptions{
+		InitialInterval: time.Millisecond,
+		OnBusinessTransition: func(transition StateTransition, application *BusinessApplication) {
+			transitions = append(transitions, transition)
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !app.IsAccepted() || len(transitions) != 2 || !transitions[1].IsDecision() {
+		t.Errorf("expected the business application to be accepted; got %s after %v", app.CurrentState(), transitions)
+	}
+}
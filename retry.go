@@ -0,0 +1,133 @@
+package identitymind
+
+import (
+	"context"
+	"math"
+	"math/rand"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const idempotencyKeyHeader = "Idempotency-Key"
+
+type idempotencyKeyContextKey struct{}
+
+// RetryPolicy governs the retry of identitymind API requests which fail due to transport errors or
+// transient (429, 502, 503 and 504) responses. Only safe requests (GET, HEAD and OPTIONS) are retried,
+// unless the request context carries an idempotency key; see WithIdempotencyKey. identitymind does not
+// guarantee PUT and DELETE requests, i.e. feedback and case updates, are idempotent, so they are not retried
+// by default.
+type RetryPolicy struct {
+	MaxAttempts          int           // total number of attempts, including the first; values less than 2 disable retries
+	InitialBackoff       time.Duration // backoff before the first retry
+	MaxBackoff           time.Duration // upper bound on the backoff between attempts
+	Multiplier           float64       // factor by which the backoff grows after each attempt
+	Jitter               float64       // fraction, between 0 and 1, of each backoff which is randomized
+	MaxRetryAfter        time.Duration // longest Retry-After which will be honored; longer values end retries; zero means no limit
+	RetryableStatusCodes []int         // response status codes which are retried; defaults to 429, 502, 503 and 504
+}
+
+// DefaultRetryPolicy returns the retry policy used by clients constructed via NewClient
+func DefaultRetryPolicy() *RetryPolicy {
+	return &RetryPolicy{
+		MaxAttempts:    3,
+		InitialBackoff: 250 * time.Millisecond,
+		MaxBackoff:     5 * time.Second,
+		Multiplier:     2,
+		Jitter:         0.2,
+		MaxRetryAfter:  30 * time.Second,
+	}
+}
+
+// WithIdempotencyKey returns a copy of ctx carrying the given idempotency key; requests sent with the
+// returned context include an Idempotency-Key header and are retried regardless of HTTP method
+func WithIdempotencyKey(ctx context.Context, key string) context.Context {
+	return context.WithValue(ctx, idempotencyKeyContextKey{}, key)
+}
+
+// IdempotencyKeyFromContext returns the idempotency key carried by ctx, if any
+func IdempotencyKeyFromContext(ctx context.Context) string {
+	key, _ := ctx.Value(idempotencyKeyContextKey{}).(string)
+	return key
+}
+
+func (p *RetryPolicy) maxAttempts() int {
+	if p.MaxAttempts < 1 {
+		return 1
+	}
+	return p.MaxAttempts
+}
+
+// backoff returns the randomized delay to observe after the given (1-indexed) failed attempt
+func (p *RetryPolicy) backoff(attempt int) time.Duration {
+	multiplier := p.Multiplier
+	if multiplier < 1 {
+		multiplier = 1
+	}
+
+	delay := float64(p.InitialBackoff) * math.Pow(multiplier, float64(attempt-1))
+	if p.MaxBackoff > 0 && delay > float64(p.MaxBackoff) {
+		delay = float64(p.MaxBackoff)
+	}
+
+	if p.Jitter > 0 {
+		jitter := math.Min(p.Jitter, 1)
+		delay -= delay * jitter * rand.Float64()
+	}
+
+	return time.Duration(delay)
+}
+
+func (p *RetryPolicy) isRetryableStatus(status int) bool {
+	codes := p.RetryableStatusCodes
+	if codes == nil {
+		codes = []int{
+			http.StatusTooManyRequests,
+			http.StatusBadGateway,
+			http.StatusServiceUnavailable,
+			http.StatusGatewayTimeout,
+		}
+	}
+	for _, code := range codes {
+		if code == status {
+			return true
+		}
+	}
+	return false
+}
+
+func isSafeMethod(method string) bool {
+	switch strings.ToUpper(method) {
+	case "GET", "HEAD", "OPTIONS":
+		return true
+	}
+	return false
+}
+
+// parseRetryAfter parses the value of a Retry-After header, which may be expressed either
+// in seconds or as an HTTP date, relative to the given time
+func parseRetryAfter(val string, now time.Time) (time.Duration, bool) {
+	val = strings.TrimSpace(val)
+	if val == "" {
+		return 0, false
+	}
+
+	if secs, err := strconv.Atoi(val); err == nil {
+		if secs < 0 {
+			return 0, false
+		}
+		return time.Duration(secs) * time.Second, true
+	}
+
+	if at, err := http.ParseTime(val); err == nil {
+		wait := at.Sub(now)
+		if wait < 0 {
+			wait = 0
+		}
+		return wait, true
+	}
+
+	return 0, false
+}
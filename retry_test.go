@@ -0,0 +1,149 @@
+package identitymind
+
+import (
+	"context"
+	"net/http"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func testRetryPolicy() *RetryPolicy {
+	return &RetryPolicy{
+		MaxAttempts:    3,
+		InitialBackoff: time.Millisecond,
+		MaxBackoff:     5 * time.Millisecond,
+		Multiplier:     2,
+		MaxRetryAfter:  time.Second,
+	}
+}
+
+func TestRetryPolicyBackoff(t *testing.T) {
+	policy := &RetryPolicy{InitialBackoff: 100 * time.Millisecond, MaxBackoff: time.Second, Multiplier: 2}
+	tests := []struct {
+		attempt  int
+		expected time.Duration
+	}{
+		{1, 100 * time.Millisecond},
+		{2, 200 * time.Millisecond},
+		{3, 400 * time.Millisecond},
+		{4, 800 * time.Millisecond},
+		{5, time.Second},
+		{10, time.Second},
+	}
+	for _, test := range tests {
+		if backoff := policy.backoff(test.attempt); backoff != test.expected {
+			t.Errorf("attempt %d: expected backoff of %v; got %v", test.attempt, test.expected, backoff)
+		}
+	}
+
+	policy.Jitter = 0.5
+	for i := 0; i < 100; i++ {
+		backoff := policy.backoff(2)
+		if backoff > 200*time.Millisecond || backoff < 100*time.Millisecond {
+			t.Fatalf("expected a jittered backoff between 100ms and 200ms; got %v", backoff)
+		}
+	}
+}
+
+func TestParseRetryAfter(t *testing.T) {
+	now := time.Date(2020, 1, 2, 3, 4, 5, 0, time.UTC)
+	tests := []struct {
+		val      string
+		expected time.Duration
+		ok       bool
+	}{
+		{"", 0, false},
+		{"120", 2 * time.Minute, true},
+		{" 0 ", 0, true},
+		{"-1", 0, false},
+		{"soon", 0, false},
+		{now.Add(30 * time.Second).Format(http.TimeFormat), 30 * time.Second, true},
+		{now.Add(-30 * time.Second).Format(http.TimeFormat), 0, true},
+	}
+	for _, test := range tests {
+		wait, ok := parseRetryAfter(test.val, now)
+		if wait != test.expected || ok != test.ok {
+			t.Errorf("%q: expected %v, %v; got %v, %v", test.val, test.expected, test.ok, wait, ok)
+		}
+	}
+}
+
+func TestRetry(t *testing.T) {
+	tests := []struct {
+		name       string
+		method     string
+		key        string
+		status     int
+		retryAfter string
+		attempts   int32
+	}{
+		{"get retried until exhausted", http.MethodGet, "", http.StatusServiceUnavailable, "", 3},
+		{"get honors retry-after", http.MethodGet, "", http.StatusTooManyRequests, "0", 3},
+		{"get retry-after beyond MaxRetryAfter ends retries", http.MethodGet, "", http.StatusTooManyRequests, "60", 1},
+		{"get not retried for non-transient status", http.MethodGet, "", http.StatusBadRequest, "", 1},
+		{"post not retried", http.MethodPost, "", http.StatusServiceUnavailable, "", 1},
+		{"put not retried", http.MethodPut, "", http.StatusServiceUnavailable, "", 1},
+		{"delete not retried", http.MethodDelete, "", http.StatusServiceUnavailable, "", 1},
+		{"post with idempotency key retried", http.MethodPost, "key-1", http.StatusServiceUnavailable, "", 3},
+		{"put with idempotency key retried", http.MethodPut, "key-1", http.StatusBadGateway, "", 3},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var attempts int32
+			client, srv := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+				atomic.AddInt32(&attempts, 1)
+				if key := r.Header.Get(idempotencyKeyHeader); key != test.key {
+					t.Errorf("expected idempotency key %q; got %q", test.key, key)
+				}
+				if test.retryAfter != "" {
+					w.Header().Set("Retry-After", test.retryAfter)
+				}
+				w.WriteHeader(test.status)
+			}, WithRetryPolicy(testRetryPolicy()))
+			defer srv.Close()
+
+			ctx := context.Background()
+			if test.key != "" {
+				ctx = WithIdempotencyKey(ctx, test.key)
+			}
+			var err error
+			switch test.method {
+			case http.MethodGet:
+				_, err = client.GetWithContext(ctx, "im/account/consumer/v2/app-1", nil, nil)
+			case http.MethodPost:
+				_, err = client.PostWithContext(ctx, "im/account/consumer", map[string]interface{}{}, nil)
+			case http.MethodPut:
+				_, err = client.PutWithContext(ctx, "im/admin/jax/case/case-1", map[string]interface{}{}, nil)
+			case http.MethodDelete:
+				_, err = client.DeleteWithContext(ctx, "im/admin/jax/case/case-1")
+			}
+			if !hasStatus(err, test.status) {
+				t.Errorf("expected a %d APIError; got %v", test.status, err)
+			}
+			if attempts != test.attempts {
+				t.Errorf("expected %d attempts; got %d", test.attempts, attempts)
+			}
+		})
+	}
+}
+
+func TestRetrySucceedsAfterTransientFailure(t *testing.T) {
+	var attempts int32
+	client, srv := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&attempts, 1) < 3 {
+			w.WriteHeader(http.StatusBadGateway)
+			return
+		}
+		w.Write([]byte(`{"tid":"app-1","state":"A"}`))
+	}, WithRetryPolicy(testRetryPolicy()))
+	defer srv.Close()
+
+	app, err := client.GetApplication("app-1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if app.TID == nil || *app.TID != "app-1" || attempts != 3 {
+		t.Errorf("expected app-1 after 3 attempts; got %v after %d", app, attempts)
+	}
+}
@@ -0,0 +1,273 @@
+package identitymind
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+)
+
+// ApplicationState represents the state of a identitymind KYC or KYB application, as returned in the
+// state field of the application response; see https://edoc.identitymind.com/reference#getv2. The API
+// documents exactly three states: accepted, rejected and under review
+type ApplicationState string
+
+// ApplicationStateAccepted indicates the application has been accepted
+const ApplicationStateAccepted ApplicationState = "A"
+
+// ApplicationStateRejected indicates the application has been rejected (denied)
+const ApplicationStateRejected ApplicationState = "D"
+
+// ApplicationStateUnderReview indicates the application is pending manual review
+const ApplicationStateUnderReview ApplicationState = "R"
+
+// IsAccepted returns true if the state is accepted
+func (s ApplicationState) IsAccepted() bool {
+	return s == ApplicationStateAccepted
+}
+
+// IsRejected returns true if the state is rejected
+func (s ApplicationState) IsRejected() bool {
+	return s == ApplicationStateRejected
+}
+
+// IsUnderReview returns true if the state is pending manual review
+func (s ApplicationState) IsUnderReview() bool {
+	return s == ApplicationStateUnderReview
+}
+
+// IsTerminal returns true if the state represents a decision, i.e. accepted or rejected
+func (s ApplicationState) IsTerminal() bool {
+	return s == ApplicationStateAccepted || s == ApplicationStateRejected
+}
+
+// IsValid returns true if the state is one of the known identitymind application states
+func (s ApplicationState) IsValid() bool {
+	return s == ApplicationStateAccepted || s == ApplicationStateRejected || s == ApplicationStateUnderReview
+}
+
+// String returns a human-readable description of the state
+func (s ApplicationState) String() string {
+	switch s {
+	case ApplicationStateAccepted:
+		return "accepted"
+	case ApplicationStateRejected:
+		return "rejected"
+	case ApplicationStateUnderReview:
+		return "under review"
+	}
+	return fmt.Sprintf("unknown (%s)", string(s))
+}
+
+// CanTransitionTo returns true if an application may move from the state to the given state: both states must
+// be known and differ. Feedback (see https://edoc.identitymind.com/reference#feedback) moves an application to
+// accepted, rejected or under review from either of the other states
+func (s ApplicationState) CanTransitionTo(next ApplicationState) bool {
+	return ValidateStateTransition(s, next) == nil
+}
+
+// ValidateStateTransition returns an error if an application may not move from the given state to the given state
+func ValidateStateTransition(from, to ApplicationState) error {
+	if !from.IsValid() {
+		return fmt.Errorf("invalid application state: %s", string(from))
+	}
+	if !to.IsValid() {
+		return fmt.Errorf("invalid application state: %s", string(to))
+	}
+	if from == to {
+		return fmt.Errorf("application is already %s", from)
+	}
+	return nil
+}
+
+// StateTransition represents the movement of an application from one state to another
+type StateTransition struct {
+	From ApplicationState
+	To   ApplicationState
+}
+
+// IsValid returns true if the transition is permitted; see CanTransitionTo
+func (t StateTransition) IsValid() bool {
+	return t.From.CanTransitionTo(t.To)
+}
+
+// Feedback returns the feedback which effects the transition, i.e. accepted, rejected or review, or
+// an empty string if the transition is not permitted; see https://edoc.identitymind.com/reference#feedback
+func (t StateTransition) Feedback() string {
+	if !t.IsValid() {
+		return ""
+	}
+	switch t.To {
+	case ApplicationStateAccepted:
+		return "accepted"
+	case ApplicationStateRejected:
+		return "rejected"
+	}
+	return "review"
+}
+
+// IsDecision returns true if the transition decides an application which was under review
+func (t StateTransition) IsDecision() bool {
+	return t.From.IsUnderReview() && t.To.IsTerminal()
+}
+
+// IsReversal returns true if the transition reverses a prior decision
+func (t StateTransition) IsReversal() bool {
+	return t.From.IsTerminal() && t.To.IsTerminal() && t.From != t.To
+}
+
+// IsReopen returns true if the transition returns a decided application to review
+func (t StateTransition) IsReopen() bool {
+	return t.From.IsTerminal() && t.To.IsUnderReview()
+}
+
+// PolicyResult represents the result of a identitymind fraud policy evaluation (frp and res)
+type PolicyResult string
+
+// PolicyResultAccept indicates the fraud policy accepted the application or transaction
+const PolicyResultAccept PolicyResult = "ACCEPT"
+
+// PolicyResultDeny indicates the fraud policy denied the application or transaction
+const PolicyResultDeny PolicyResult = "DENY"
+
+// PolicyResultManualReview indicates the fraud policy referred the application or transaction for manual review
+const PolicyResultManualReview PolicyResult = "MANUAL_REVIEW"
+
+// IsValid returns true if the policy result is one of the known identitymind policy results
+func (r PolicyResult) IsValid() bool {
+	return r == PolicyResultAccept || r == PolicyResultDeny || r == PolicyResultManualReview
+}
+
+// ApplicationState returns the application state which corresponds to the policy result
+func (r PolicyResult) ApplicationState() ApplicationState {
+	switch r {
+	case PolicyResultAccept:
+		return ApplicationStateAccepted
+	case PolicyResultDeny:
+		return ApplicationStateRejected
+	case PolicyResultManualReview:
+		return ApplicationStateUnderReview
+	}
+	return ApplicationState("")
+}
+
+// PolicyResult returns the policy result which corresponds to the application state, or an empty
+// policy result if the state is not known
+func (s ApplicationState) PolicyResult() PolicyResult {
+	switch s {
+	case ApplicationStateAccepted:
+		return PolicyResultAccept
+	case ApplicationStateRejected:
+		return PolicyResultDeny
+	case ApplicationStateUnderReview:
+		return PolicyResultManualReview
+	}
+	return PolicyResult("")
+}
+
+// Reputation represents the reputation of a user or merchant, as returned in the user and upr fields of an evaluation
+type Reputation string
+
+// ReputationTrusted indicates the user is trusted
+const ReputationTrusted Reputation = "TRUSTED"
+
+// ReputationUnknown indicates the user has no prior reputation
+const ReputationUnknown Reputation = "UNKNOWN"
+
+// ReputationSuspicious indicates the user is suspicious
+const ReputationSuspicious Reputation = "SUSPICIOUS"
+
+// ReputationBad indicates the user has a bad reputation
+const ReputationBad Reputation = "BAD"
+
+// IsValid returns true if the reputation is one of the known identitymind reputations
+func (r Reputation) IsValid() bool {
+	switch r {
+	case ReputationTrusted, ReputationUnknown, ReputationSuspicious, ReputationBad:
+		return true
+	}
+	return false
+}
+
+// ReasonCode represents a single identitymind result code as returned in the comma-separated rcd list;
+// descriptions are provided for the codes returned in the documented example responses. Codes of fraud
+// policy rules are specific to the policy of a given account; the rule which determined the result of an
+// evaluation is described by its eDNA scorecard (see EDNAScorecard.DescribeReasonCode), and others may be
+// described using RegisterReasonCode
+type ReasonCode string
+
+// ReasonCodeUnknownUser indicates the user has no prior reputation
+const ReasonCodeUnknownUser ReasonCode = "101"
+
+// ReasonCodeUnknownEmail indicates the email address has no prior history
+const ReasonCodeUnknownEmail ReasonCode = "111"
+
+// ReasonCodeUnknownBillingAddress indicates the billing address has no prior history
+const ReasonCodeUnknownBillingAddress ReasonCode = "131"
+
+// ReasonCodeUnknownDevice indicates the device has no prior history
+const ReasonCodeUnknownDevice ReasonCode = "150"
+
+// ReasonCodeUnknownIP indicates the IP address has no prior history
+const ReasonCodeUnknownIP ReasonCode = "202"
+
+// ReasonCodeFallthrough indicates no fraud policy rules fired during evaluation
+const ReasonCodeFallthrough ReasonCode = "1000"
+
+// ReasonCodePolicyAccept indicates the fraud policy accepted the evaluation
+const ReasonCodePolicyAccept ReasonCode = "1002"
+
+var (
+	reasonCodeDescriptionsMutex sync.RWMutex
+	reasonCodeDescriptions      = map[ReasonCode]string{
+		ReasonCodeUnknownUser:           "User has no prior reputation",
+		ReasonCodeUnknownEmail:          "Email address has no prior history",
+		ReasonCodeUnknownBillingAddress: "Billing address has no prior history",
+		ReasonCodeUnknownDevice:         "Device has no prior history",
+		ReasonCodeUnknownIP:             "IP address has no prior history",
+		ReasonCodeFallthrough:           "No fraud policy rules fired",
+		ReasonCodePolicyAccept:          "Accepted by fraud policy",
+	}
+)
+
+// RegisterReasonCode registers a human-readable description for a result code, i.e. for codes
+// which are specific to the fraud policy configured for a given identitymind account
+func RegisterReasonCode(code ReasonCode, description string) {
+	reasonCodeDescriptionsMutex.Lock()
+	defer reasonCodeDescriptionsMutex.Unlock()
+	reasonCodeDescriptions[code] = description
+}
+
+// ParseReasonCodes parses the comma-separated rcd list returned by the identitymind API
+func ParseReasonCodes(rcd string) []ReasonCode {
+	codes := make([]ReasonCode, 0)
+	for _, code := range strings.Split(rcd, ",") {
+		code = strings.TrimSpace(code)
+		if code != "" {
+			codes = append(codes, ReasonCode(code))
+		}
+	}
+	return codes
+}
+
+// IsKnown returns true if a description is registered for the result code
+func (c ReasonCode) IsKnown() bool {
+	reasonCodeDescriptionsMutex.RLock()
+	defer reasonCodeDescriptionsMutex.RUnlock()
+	_, ok := reasonCodeDescriptions[c]
+	return ok
+}
+
+// Description returns a human-readable description of the result code
+func (c ReasonCode) Description() string {
+	reasonCodeDescriptionsMutex.RLock()
+	defer reasonCodeDescriptionsMutex.RUnlock()
+	if description, ok := reasonCodeDescriptions[c]; ok {
+		return description
+	}
+	return fmt.Sprintf("Unknown result code: %s", string(c))
+}
+
+// String returns the result code followed by its description
+func (c ReasonCode) String() string {
+	return fmt.Sprintf("%s (%s)", string(c), c.Description())
+}
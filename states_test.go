@@ -0,0 +1,96 @@
+package identitymind
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+func TestStateTransition(t *testing.T) {
+	tests := []struct {
+		from     ApplicationState
+		to       ApplicationState
+		valid    bool
+		feedback string
+		decision bool
+		reversal bool
+		reopen   bool
+	}{
+		{ApplicationStateUnderReview, ApplicationStateAccepted, true, "accepted", true, false, false},
+		{ApplicationStateUnderReview, ApplicationStateRejected, true, "rejected", true, false, false},
+		{ApplicationStateAccepted, ApplicationStateRejected, true, "rejected", false, true, false},
+		{ApplicationStateRejected, ApplicationStateAccepted, true, "accepted", false, true, false},
+		{ApplicationStateAccepted, ApplicationStateUnderReview, true, "review", false, false, true},
+		{ApplicationStateRejected, ApplicationStateUnderReview, true, "review", false, false, true},
+		{ApplicationStateAccepted, ApplicationStateAccepted, false, "", false, false, false},
+		{ApplicationStateUnderReview, ApplicationStateUnderReview, false, "", false, false, false},
+		{ApplicationState(""), ApplicationStateAccepted, false, "", false, false, false},
+		{ApplicationStateUnderReview, ApplicationState("X"), false, "", false, false, false},
+	}
+	for _, test := range tests {
+		transition := StateTransition{From: test.from, To: test.to}
+		if transition.IsValid() != test.valid || (ValidateStateTransition(test.from, test.to) == nil) != test.valid {
+			t.Errorf("%s -> %s: expected valid: %v", test.from, test.to, test.valid)
+		}
+		if feedback := transition.Feedback(); feedback != test.feedback {
+			t.Errorf("%s -> %s: expected feedback %q; got %q", test.from, test.to, test.feedback, feedback)
+		}
+		if transition.IsDecision() != test.decision || transition.IsReversal() != test.reversal || transition.IsReopen() != test.reopen {
+			t.Errorf("%s -> %s: unexpected classification", test.from, test.to)
+		}
+	}
+}
+
+func TestPolicyResultApplicationState(t *testing.T) {
+	for _, state := range []ApplicationState{ApplicationStateAccepted, ApplicationStateRejected, ApplicationStateUnderReview} {
+		result := state.PolicyResult()
+		if !result.IsValid() || result.ApplicationState() != state {
+			t.Errorf("expected %s to round trip via %s", state, result)
+		}
+	}
+	if PolicyResult("MAYBE").IsValid() || PolicyResult("MAYBE").ApplicationState() != "" {
+		t.Errorf("expected an unknown policy result to map to no state")
+	}
+}
+
+func TestReputation(t *testing.T) {
+	var app *KYCApplication
+	err := json.Unmarshal([]byte(`{"user":"SUSPICIOUS","upr":"TRUSTED"}`), &app)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if *app.User != ReputationSuspicious || *app.UPR != ReputationTrusted || !app.User.IsValid() {
+		t.Errorf("unexpected reputation; %s, %s", *app.User, *app.UPR)
+	}
+	if Reputation("GOOD").IsValid() {
+		t.Errorf("expected an unknown reputation to be invalid")
+	}
+}
+
+func TestReasonCodes(t *testing.T) {
+	var app *KYCApplication
+	err := json.Unmarshal([]byte(`{
+		"rcd": "1000, 101,,4711",
+		"ednaScoreCard": {"er": {"reportedRule": {"name": "Deny high risk country", "ruleId": 4711}}}
+	}`), &app)
+	if err != nil {
+		t.Fatal(err)
+	}
+	codes := app.ReasonCodes()
+	if !reflect.DeepEqual(codes, []ReasonCode{ReasonCodeFallthrough, ReasonCodeUnknownUser, "4711"}) {
+		t.Fatalf("unexpected reason codes; %v", codes)
+	}
+	expected := []string{"No fraud policy rules fired", "User has no prior reputation", "Deny high risk country"}
+	for idx, code := range codes {
+		if description := app.EDNAScorecard.DescribeReasonCode(code); description != expected[idx] {
+			t.Errorf("expected %s to be described as %q; got %q", code, expected[idx], description)
+		}
+	}
+	if ReasonCode("4711").IsKnown() || !ReasonCodeFallthrough.IsKnown() {
+		t.Errorf("expected only registered codes to be known")
+	}
+	var scorecard *EDNAScorecard
+	if description := scorecard.DescribeReasonCode("4711"); description != "Unknown result code: 4711" {
+		t.Errorf("unexpected description; %s", description)
+	}
+}
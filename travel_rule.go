@@ -0,0 +1,197 @@
+package identitymind
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// TravelRuleParty represents the originator or beneficiary of a virtual asset transfer
+type TravelRuleParty struct {
+	Name          string `json:"name"`                   // full legal name of the natural or legal person; required
+	AccountNumber string `json:"account"`                // account number at the VASP, or the wallet address, used to process the transfer; required
+	Address       string `json:"address,omitempty"`      // geographic address
+	Country       string `json:"country,omitempty"`      // ISO 3166-1 alpha-2 country code of residence or incorporation
+	NationalID    string `json:"nationalId,omitempty"`   // national identity or legal entity identifier (LEI) number
+	CustomerID    string `json:"customerId,omitempty"`   // identifier of the customer at the VASP
+	DateOfBirth   string `json:"dob,omitempty"`          // date of birth formatted as YYYY-MM-DD
+	PlaceOfBirth  string `json:"placeOfBirth,omitempty"` // city and country of birth
+}
+
+// VASP represents a virtual asset service provider party to a transfer
+type VASP struct {
+	ID      string `json:"id"`                // identifier of the VASP, i.e. its LEI or travel-rule network identifier; required
+	Name    string `json:"name,omitempty"`    // legal name of the VASP
+	Country string `json:"country,omitempty"` // ISO 3166-1 alpha-2 country code in which the VASP is registered
+}
+
+// TravelRule represents the originator and beneficiary information attached to a virtual asset transfer
+// to satisfy the FATF travel rule (recommendation 16). The published transferout and transfer references
+// define no travel-rule fields, so the information is only checked locally against the travel-rule policy
+// of the client, if any, before the transfer is reported; it is not submitted to identitymind
+type TravelRule struct {
+	Originator      *TravelRuleParty `json:"originator"`
+	Beneficiary     *TravelRuleParty `json:"beneficiary"`
+	OriginatorVASP  *VASP            `json:"originatorVasp,omitempty"`
+	BeneficiaryVASP *VASP            `json:"beneficiaryVasp,omitempty"`
+}
+
+// TravelRulePolicy configures when travel-rule information must accompany a transfer and which of its
+// fields are required. The presets returned by NewTravelRulePolicy reflect published thresholds, which
+// change over time; they should be reviewed against current regulation before use. A policy with a
+// nonzero threshold requires ConvertAmount
+type TravelRulePolicy struct {
+	Jurisdiction             string  // ISO 3166-1 alpha-2 country code, or EU, of the reporting VASP
+	Threshold                float64 // transfers of at least this amount require travel-rule information; zero requires it for every transfer
+	Currency                 string  // currency of the threshold
+	RequireOriginatorAddress bool    // require the originator address, rather than any of address, national id, customer id or date and place of birth
+	RequireVASPs             bool    // require the originator and beneficiary VASP identifiers
+
+	// ConvertAmount converts an amount to the currency of the threshold; it is required when the threshold
+	// is nonzero, as transfers are typically denominated in another currency, i.e. digital currency
+	ConvertAmount func(amount float64, from, to string) (float64, error)
+}
+
+// TravelRuleTransfer is implemented by the typed transfer requests to which travel-rule information may be attached
+type TravelRuleTransfer interface {
+	TransferRequest
+	ValidateTravelRule(policy *TravelRulePolicy) error
+}
+
+var euJurisdictions = map[string]bool{
+	"AT": true, "BE": true, "BG": true, "CY": true, "CZ": true, "DE": true, "DK": true, "EE": true, "ES": true,
+	"FI": true, "FR": true, "GR": true, "HR": true, "HU": true, "IE": true, "IT": true, "LT": true, "LU": true,
+	"LV": true, "MT": true, "NL": true, "PL": true, "PT": true, "RO": true, "SE": true, "SI": true, "SK": true,
+}
+
+// NewTravelRulePolicy returns the travel-rule policy for the given jurisdiction, an ISO 3166-1 alpha-2 country
+// code or EU: US requires information, including the originator address, for transfers of at least 3000 USD;
+// EU member states require information, including VASP identifiers, for every transfer; SG requires information
+// for transfers of at least 1500 SGD. Other jurisdictions use the FATF threshold of 1000 USD. The given function
+// converts transfer amounts to the currency of the threshold; it may only be nil for EU jurisdictions, which have none
+func NewTravelRulePolicy(jurisdiction string, convert func(amount float64, from, to string) (float64, error)) (*TravelRulePolicy, error) {
+	policy := travelRulePolicyPreset(strings.ToUpper(jurisdiction))
+	policy.ConvertAmount = convert
+	if err := policy.Validate(); err != nil {
+		return nil, err
+	}
+	return policy, nil
+}
+
+// Validate returns an error if the policy has a nonzero threshold but no means of converting amounts to its currency
+func (p *TravelRulePolicy) Validate() error {
+	if p.Threshold < 0 {
+		return fmt.Errorf("invalid travel rule threshold %v %s", p.Threshold, p.Currency)
+	}
+	if p.Threshold > 0 && p.Currency == "" {
+		return fmt.Errorf("travel rule threshold currency is required")
+	}
+	if p.Threshold > 0 && p.ConvertAmount == nil {
+		return fmt.Errorf("ConvertAmount is required to evaluate transfers against the %v %s travel rule threshold in %s", p.Threshold, p.Currency, p.Jurisdiction)
+	}
+	return nil
+}
+
+func travelRulePolicyPreset(jurisdiction string) *TravelRulePolicy {
+	switch {
+	case jurisdiction == "US":
+		return &TravelRulePolicy{
+			Jurisdiction:             jurisdiction,
+			Threshold:                3000,
+			Currency:                 "USD",
+			RequireOriginatorAddress: true,
+		}
+	case jurisdiction == "EU" || euJurisdictions[jurisdiction]:
+		return &TravelRulePolicy{
+			Jurisdiction: jurisdiction,
+			Currency:     "EUR",
+			RequireVASPs: true,
+		}
+	case jurisdiction == "SG":
+		return &TravelRulePolicy{
+			Jurisdiction: jurisdiction,
+			Threshold:    1500,
+			Currency:     "SGD",
+		}
+	}
+	return &TravelRulePolicy{
+		Jurisdiction: jurisdiction,
+		Threshold:    1000,
+		Currency:     "USD",
+	}
+}
+
+// Requires returns true if travel-rule information must accompany a transfer of the given amount and currency
+func (p *TravelRulePolicy) Requires(amount, currency string) (bool, error) {
+	if p.Threshold <= 0 {
+		return true, nil
+	}
+	value, err := strconv.ParseFloat(amount, 64)
+	if err != nil {
+		return false, fmt.Errorf("invalid amount %q", amount)
+	}
+	if !strings.EqualFold(currency, p.Currency) {
+		if p.ConvertAmount == nil {
+			return false, fmt.Errorf("unable to compare %s amount against the %s travel rule threshold without ConvertAmount", currency, p.Currency)
+		}
+		value, err = p.ConvertAmount(value, currency, p.Currency)
+		if err != nil {
+			return false, fmt.Errorf("failed to convert %s amount to %s; %s", currency, p.Currency, err.Error())
+		}
+	}
+	return value >= p.Threshold, nil
+}
+
+// Check returns an error if travel-rule information is required for the given transaction, but the given
+// travel rule is missing or lacks fields required by the policy
+func (p *TravelRulePolicy) Check(request *TransactionRequest, rule *TravelRule) error {
+	required, err := p.Requires(request.Amount, request.Currency)
+	if err != nil {
+		return err
+	}
+	if !required {
+		return nil
+	}
+	if rule == nil {
+		if p.Threshold <= 0 {
+			return fmt.Errorf("travel rule information is required for every transfer in %s", p.Jurisdiction)
+		}
+		return fmt.Errorf("travel rule information is required for transfers of at least %v %s in %s", p.Threshold, p.Currency, p.Jurisdiction)
+	}
+
+	originator := rule.Originator
+	if originator == nil || originator.Name == "" || originator.AccountNumber == "" {
+		return fmt.Errorf("originator name and account are required")
+	}
+	if p.RequireOriginatorAddress && originator.Address == "" {
+		return fmt.Errorf("originator address is required in %s", p.Jurisdiction)
+	}
+	if originator.Address == "" && originator.NationalID == "" && originator.CustomerID == "" && (originator.DateOfBirth == "" || originator.PlaceOfBirth == "") {
+		return fmt.Errorf("originator address, national id, customer id or date and place of birth is required")
+	}
+
+	beneficiary := rule.Beneficiary
+	if beneficiary == nil || beneficiary.Name == "" || beneficiary.AccountNumber == "" {
+		return fmt.Errorf("beneficiary name and account are required")
+	}
+
+	if p.RequireVASPs {
+		if rule.OriginatorVASP == nil || rule.OriginatorVASP.ID == "" {
+			return fmt.Errorf("originator VASP id is required in %s", p.Jurisdiction)
+		}
+		if rule.BeneficiaryVASP == nil || rule.BeneficiaryVASP.ID == "" {
+			return fmt.Errorf("beneficiary VASP id is required in %s", p.Jurisdiction)
+		}
+	}
+	return nil
+}
+
+// ValidateTravelRule returns an error if the withdrawal lacks travel-rule information required by the given policy
+func (t *TransferOut) ValidateTravelRule(policy *TravelRulePolicy) error {
+	return policy.Check(&t.TransactionRequest, t.TravelRule)
+}
+
+// ValidateTravelRule returns an error if the transfer lacks travel-rule information required by the given policy
+func (t *Transfer) ValidateTravelRule(policy *TravelRulePolicy) error {
+	return policy.Check(&t.TransactionRequest, t.TravelRule)
+}
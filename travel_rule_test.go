@@ -0,0 +1,94 @@
+package identitymind
+
+import "testing"
+
+func TestNewTravelRulePolicy(t *testing.T) {
+	convert := func(amount float64, from, to string) (float64, error) {
+		return amount * 2, nil
+	}
+
+	tests := []struct {
+		jurisdiction string
+		convert      func(amount float64, from, to string) (float64, error)
+		valid        bool
+	}{
+		{"US", convert, true},
+		{"US", nil, false},
+		{"SG", nil, false},
+		{"JP", nil, false},
+		{"DE", nil, true},
+		{"EU", nil, true},
+	}
+	for _, test := range tests {
+		_, err := NewTravelRulePolicy(test.jurisdiction, test.convert)
+		if (err == nil) != test.valid {
+			t.Errorf("NewTravelRulePolicy(%q, convert=%v) error = %v; expected valid=%v", test.jurisdiction, test.convert != nil, err, test.valid)
+		}
+	}
+
+	policy, err := NewTravelRulePolicy("US", convert)
+	if err != nil {
+		t.Fatalf("NewTravelRulePolicy failed; %s", err.Error())
+	}
+	required, err := policy.Requires("1600", "BTC")
+	if err != nil {
+		t.Fatalf("Requires failed; %s", err.Error())
+	}
+	if !required {
+		t.Errorf("expected a BTC withdrawal converting to 3200 USD to require travel rule information")
+	}
+}
+
+func TestWithTravelRulePolicyRequiresConvertAmount(t *testing.T) {
+	client := &IdentityMindAPIClient{}
+	err := WithTravelRulePolicy(&TravelRulePolicy{Jurisdiction: "US", Threshold: 3000, Currency: "USD"})(client)
+	if err == nil {
+		t.Errorf("expected a policy with a nonzero threshold and no ConvertAmount to be rejected")
+	}
+	if err := WithTravelRulePolicy(nil)(client); err != nil {
+		t.Errorf("expected a nil policy to disable the check; %s", err.Error())
+	}
+}
+
+func TestTransferParamsTravelRule(t *testing.T) {
+	policy, err := NewTravelRulePolicy("EU", nil)
+	if err != nil {
+		t.Fatalf("NewTravelRulePolicy failed; %s", err.Error())
+	}
+	rule := &TravelRule{
+		Originator:      &TravelRuleParty{Name: "Alice", AccountNumber: "alice", Address: "1 Main St"},
+		Beneficiary:     &TravelRuleParty{Name: "Bob", AccountNumber: "bob"},
+		OriginatorVASP:  &VASP{ID: "vasp-1"},
+		BeneficiaryVASP: &VASP{ID: "vasp-2"},
+	}
+
+	tests := []struct {
+		name   string
+		policy *TravelRulePolicy
+		rule   *TravelRule
+		valid  bool
+	}{
+		{"no policy", nil, nil, true},
+		{"missing travel rule", policy, nil, false},
+		{"missing VASPs", policy, &TravelRule{Originator: rule.Originator, Beneficiary: rule.Beneficiary}, false},
+		{"complete travel rule", policy, rule, true},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			params, err := transferParams(&Transfer{
+				TransactionRequest:     TransactionRequest{AccountName: "alice", Amount: "1", Currency: "BTC"},
+				DestinationAccountName: "bob",
+				TravelRule:             test.rule,
+			}, test.policy)
+			if (err == nil) != test.valid {
+				t.Fatalf("expected valid=%v; got %v", test.valid, err)
+			}
+			if err != nil {
+				return
+			}
+			if _, ok := params["travelRule"]; ok || len(params) != 4 {
+				t.Fatalf("expected the travel rule not to be submitted; got %v", params)
+			}
+		})
+	}
+}
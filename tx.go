@@ -1,6 +1,9 @@
 package identitymind
 
-import "fmt"
+import (
+	"context"
+	"fmt"
+)
 
 // IdentityMindTxTypeDeposit maps to 'transferin' URI; see https://edoc.identitymind.com/reference#transferin
 const IdentityMindTxTypeDeposit = "transferin"
@@ -12,34 +15,280 @@ const IdentityMindTxTypeWithdrawal = "transferout"
 const IdentityMindTxTypeTransfer = "transfer"
 
 // EvaluateFraud evaluates a transaction for payment fraud; see https://edoc.identitymind.com/reference#anti-fraud-1
-func (i *IdentityMindAPIClient) EvaluateFraud(params map[string]interface{}) (interface{}, error) {
-	var resp map[string]interface{}
-	status, err := i.Post(fmt.Sprintf("im/transaction?graphScoreResponse=false"), params, &resp)
+func (i *IdentityMindAPIClient) EvaluateFraud(params map[string]interface{}) (*TransactionResult, error) {
+	return i.EvaluateFraudWithContext(context.Background(), params)
+}
+
+// EvaluateFraudWithContext evaluates a transaction for payment fraud; see https://edoc.identitymind.com/reference#anti-fraud-1
+func (i *IdentityMindAPIClient) EvaluateFraudWithContext(ctx context.Context, params map[string]interface{}) (*TransactionResult, error) {
+	var resp *TransactionResult
+	status, err := i.PostWithContext(ctx, fmt.Sprintf("im/transaction?graphScoreResponse=false"), params, &resp)
 	if err != nil {
-		return nil, fmt.Errorf("Failed to evaluate tx for payment fraud via identitymind API; status: %d; %s", status, err.Error())
+		return nil, fmt.Errorf("Failed to evaluate tx for payment fraud via identitymind API; status: %d; %w", status, err)
 	}
 	return resp, nil
 }
 
 // ReportFraud reports a fraud event; see https://edoc.identitymind.com/reference#event
 func (i *IdentityMindAPIClient) ReportFraud(params map[string]interface{}) (interface{}, error) {
+	return i.ReportFraudWithContext(context.Background(), params)
+}
+
+// ReportFraudWithContext reports a fraud event; see https://edoc.identitymind.com/reference#event
+func (i *IdentityMindAPIClient) ReportFraudWithContext(ctx context.Context, params map[string]interface{}) (interface{}, error) {
 	var resp map[string]interface{}
-	status, err := i.Post("im/admin/jax/feg", params, &resp)
+	status, err := i.PostWithContext(ctx, "im/admin/jax/feg", params, &resp)
 	if err != nil {
-		return nil, fmt.Errorf("Failed to report fraud event via identitymind API; status: %d; %s", status, err.Error())
+		return nil, fmt.Errorf("Failed to report fraud event via identitymind API; status: %d; %w", status, err)
 	}
 	return resp, nil
 }
 
+// EvaluatePayment evaluates the given payment for fraud; see https://edoc.identitymind.com/reference#anti-fraud-1
+func (i *IdentityMindAPIClient) EvaluatePayment(payment *PaymentTransaction) (*TransactionResult, error) {
+	return i.EvaluatePaymentWithContext(context.Background(), payment)
+}
+
+// EvaluatePaymentWithContext evaluates the given payment for fraud; see https://edoc.identitymind.com/reference#anti-fraud-1
+func (i *IdentityMindAPIClient) EvaluatePaymentWithContext(ctx context.Context, payment *PaymentTransaction) (*TransactionResult, error) {
+	params, err := paymentParams(payment)
+	if err != nil {
+		return nil, err
+	}
+	return i.EvaluateFraudWithContext(ctx, params)
+}
+
 // ReportTransaction reports various kinds of transactions including deposits, withdrawals and internal transfer
-func (i *IdentityMindAPIClient) ReportTransaction(txType string, params map[string]interface{}) (interface{}, error) {
-	if txType != IdentityMindTxTypeDeposit && txType != IdentityMindTxTypeWithdrawal && txType != IdentityMindTxTypeTransfer {
+func (i *IdentityMindAPIClient) ReportTransaction(txType string, params map[string]interface{}) (*TransactionResult, error) {
+	return i.ReportTransactionWithContext(context.Background(), txType, params)
+}
+
+// ReportTransactionWithContext reports various kinds of transactions including deposits, withdrawals and internal transfer
+func (i *IdentityMindAPIClient) ReportTransactionWithContext(ctx context.Context, txType string, params map[string]interface{}) (*TransactionResult, error) {
+	if !IsValidTxType(txType) {
 		return nil, fmt.Errorf("Invalid tx type provided: %s", txType)
 	}
+	var resp *TransactionResult
+	status, err := i.PostWithContext(ctx, fmt.Sprintf("im/account/%s?graphScoreResponse=false", txType), params, &resp)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to report tx via identitymind API; status: %d; %w", status, err)
+	}
+	return resp, nil
+}
+
+// ReportTransfer reports the given deposit (TransferIn), withdrawal (TransferOut) or internal transfer (Transfer); transfers and withdrawals are checked against the TravelRulePolicy of the client, if any
+func (i *IdentityMindAPIClient) ReportTransfer(transfer TransferRequest) (*TransactionResult, error) {
+	return i.ReportTransferWithContext(context.Background(), transfer)
+}
+
+// ReportTransferWithContext reports the given deposit (TransferIn), withdrawal (TransferOut) or internal transfer (Transfer); transfers and withdrawals are checked against the TravelRulePolicy of the client, if any
+func (i *IdentityMindAPIClient) ReportTransferWithContext(ctx context.Context, transfer TransferRequest) (*TransactionResult, error) {
+	params, err := transferParams(transfer, i.TravelRulePolicy)
+	if err != nil {
+		return nil, err
+	}
+	return i.ReportTransactionWithContext(ctx, transfer.TxType(), params)
+}
+
+// IsValidTxType returns true if the given tx type is one of the IdentityMindTxType constants
+func IsValidTxType(txType string) bool {
+	return txType == IdentityMindTxTypeDeposit || txType == IdentityMindTxTypeWithdrawal || txType == IdentityMindTxTypeTransfer
+}
+
+// paymentParams validates the payment and converts it to the params accepted by the API client
+func paymentParams(payment *PaymentTransaction) (map[string]interface{}, error) {
+	if payment == nil {
+		return nil, fmt.Errorf("Failed to evaluate payment; payment is required")
+	}
+	if err := payment.Validate(); err != nil {
+		return nil, fmt.Errorf("Failed to evaluate payment; %s", err.Error())
+	}
+	params, err := marshalParams(payment)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to marshal payment; %s", err.Error())
+	}
+	return params, nil
+}
+
+// transferParams validates the transfer, including its travel-rule information when a policy is given, and
+// converts it to the params accepted by the API client
+func transferParams(transfer TransferRequest, policy *TravelRulePolicy) (map[string]interface{}, error) {
+	if transfer == nil {
+		return nil, fmt.Errorf("Failed to report transfer; transfer is required")
+	}
+	if err := transfer.Validate(); err != nil {
+		return nil, fmt.Errorf("Failed to report %s tx; %s", transfer.TxType(), err.Error())
+	}
+	if travelRuleTransfer, ok := transfer.(TravelRuleTransfer); ok && policy != nil {
+		if err := travelRuleTransfer.ValidateTravelRule(policy); err != nil {
+			return nil, fmt.Errorf("Failed to report %s tx; %s", transfer.TxType(), err.Error())
+		}
+	}
+	params, err := marshalParams(transfer)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to marshal %s tx; %s", transfer.TxType(), err.Error())
+	}
+	return params, nil
+}
+
+// TransactionFeedbackType distinguishes the kinds of feedback which may be provided for an evaluated transaction;
+// see https://edoc.identitymind.com/reference#feedback
+type TransactionFeedbackType string
+
+// TransactionFeedbackTypeAccepted indicates the transaction was accepted
+const TransactionFeedbackTypeAccepted TransactionFeedbackType = "accepted"
+
+// TransactionFeedbackTypeRejected indicates the transaction was rejected
+const TransactionFeedbackTypeRejected TransactionFeedbackType = "rejected"
+
+// TransactionFeedbackTypeFraud indicates the transaction was confirmed to be fraudulent
+const TransactionFeedbackTypeFraud TransactionFeedbackType = "fraud"
+
+// TransactionFeedbackTypeChargeback indicates the transaction was charged back
+const TransactionFeedbackTypeChargeback TransactionFeedbackType = "chargeback"
+
+// TransactionFeedbackReason is the reason code accompanying transaction feedback
+type TransactionFeedbackReason string
+
+// transactionFeedbackReasons are the valid transaction feedback reason codes
+var transactionFeedbackReasons = map[TransactionFeedbackReason]bool{
+	TransactionFeedbackReasonVerifiedCustomer: true,
+	TransactionFeedbackReasonPolicyViolation:  true,
+	TransactionFeedbackReasonSuspectedFraud:   true,
+	TransactionFeedbackReasonSanctionsMatch:   true,
+	TransactionFeedbackReasonStolenInstrument: true,
+	TransactionFeedbackReasonAccountTakeover:  true,
+	TransactionFeedbackReasonIdentityTheft:    true,
+	TransactionFeedbackReasonFriendlyFraud:    true,
+	TransactionFeedbackReasonUnauthorized:     true,
+	TransactionFeedbackReasonNotReceived:      true,
+	TransactionFeedbackReasonDuplicate:        true,
+	TransactionFeedbackReasonOther:            true,
+}
+
+// IsValid returns true if the reason is one of the enumerated reason codes
+func (r TransactionFeedbackReason) IsValid() bool {
+	return transactionFeedbackReasons[r]
+}
+
+// TransactionFeedbackReasonVerifiedCustomer indicates the customer was verified, i.e. following manual review
+const TransactionFeedbackReasonVerifiedCustomer TransactionFeedbackReason = "VERIFIED_CUSTOMER"
+
+// TransactionFeedbackReasonPolicyViolation indicates the transaction violated internal policy
+const TransactionFeedbackReasonPolicyViolation TransactionFeedbackReason = "POLICY_VIOLATION"
+
+// TransactionFeedbackReasonSuspectedFraud indicates the transaction was suspected, but not confirmed, to be fraudulent
+const TransactionFeedbackReasonSuspectedFraud TransactionFeedbackReason = "SUSPECTED_FRAUD"
+
+// TransactionFeedbackReasonSanctionsMatch indicates a party to the transaction matched a sanctions list
+const TransactionFeedbackReasonSanctionsMatch TransactionFeedbackReason = "SANCTIONS_MATCH"
+
+// TransactionFeedbackReasonStolenInstrument indicates the payment instrument was stolen
+const TransactionFeedbackReasonStolenInstrument TransactionFeedbackReason = "STOLEN_INSTRUMENT"
+
+// TransactionFeedbackReasonAccountTakeover indicates the account of the customer was compromised
+const TransactionFeedbackReasonAccountTakeover TransactionFeedbackReason = "ACCOUNT_TAKEOVER"
+
+// TransactionFeedbackReasonIdentityTheft indicates the identity of the customer was stolen or synthesized
+const TransactionFeedbackReasonIdentityTheft TransactionFeedbackReason = "IDENTITY_THEFT"
+
+// TransactionFeedbackReasonFriendlyFraud indicates the legitimate customer disputed a transaction they made
+const TransactionFeedbackReasonFriendlyFraud TransactionFeedbackReason = "FRIENDLY_FRAUD"
+
+// TransactionFeedbackReasonUnauthorized indicates the customer disputed the transaction as unauthorized
+const TransactionFeedbackReasonUnauthorized TransactionFeedbackReason = "UNAUTHORIZED"
+
+// TransactionFeedbackReasonNotReceived indicates the customer disputed the transaction as goods or services not received
+const TransactionFeedbackReasonNotReceived TransactionFeedbackReason = "NOT_RECEIVED"
+
+// TransactionFeedbackReasonDuplicate indicates the customer disputed the transaction as a duplicate
+const TransactionFeedbackReasonDuplicate TransactionFeedbackReason = "DUPLICATE"
+
+// TransactionFeedbackReasonOther indicates a reason not otherwise enumerated; the description should be provided
+const TransactionFeedbackReasonOther TransactionFeedbackReason = "OTHER"
+
+// TransactionFeedback represents feedback on an evaluated transaction
+type TransactionFeedback struct {
+	Reason      TransactionFeedbackReason `json:"reason,omitempty"`      // reason code
+	Description string                    `json:"description,omitempty"` // free-form description of the reason
+	Analyst     string                    `json:"analyst,omitempty"`     // identifier of the analyst providing the feedback
+	Amount      string                    `json:"amt,omitempty"`         // amount charged back, when less than the transaction amount
+	Currency    string                    `json:"ccy,omitempty"`         // ISO 4217 currency code of the amount charged back
+}
+
+// AcceptTransaction see https://edoc.identitymind.com/reference#feedback
+func (i *IdentityMindAPIClient) AcceptTransaction(transactionID string, feedback *TransactionFeedback) (interface{}, error) {
+	return i.AcceptTransactionWithContext(context.Background(), transactionID, feedback)
+}
+
+// AcceptTransactionWithContext see https://edoc.identitymind.com/reference#feedback
+func (i *IdentityMindAPIClient) AcceptTransactionWithContext(ctx context.Context, transactionID string, feedback *TransactionFeedback) (interface{}, error) {
+	return i.provideTransactionFeedback(ctx, transactionID, TransactionFeedbackTypeAccepted, feedback)
+}
+
+// RejectTransaction see https://edoc.identitymind.com/reference#feedback
+func (i *IdentityMindAPIClient) RejectTransaction(transactionID string, feedback *TransactionFeedback) (interface{}, error) {
+	return i.RejectTransactionWithContext(context.Background(), transactionID, feedback)
+}
+
+// RejectTransactionWithContext see https://edoc.identitymind.com/reference#feedback
+func (i *IdentityMindAPIClient) RejectTransactionWithContext(ctx context.Context, transactionID string, feedback *TransactionFeedback) (interface{}, error) {
+	return i.provideTransactionFeedback(ctx, transactionID, TransactionFeedbackTypeRejected, feedback)
+}
+
+// ReportTransactionFraud marks the given transaction as fraudulent; see https://edoc.identitymind.com/reference#feedback
+func (i *IdentityMindAPIClient) ReportTransactionFraud(transactionID string, feedback *TransactionFeedback) (interface{}, error) {
+	return i.ReportTransactionFraudWithContext(context.Background(), transactionID, feedback)
+}
+
+// ReportTransactionFraudWithContext marks the given transaction as fraudulent; see https://edoc.identitymind.com/reference#feedback
+func (i *IdentityMindAPIClient) ReportTransactionFraudWithContext(ctx context.Context, transactionID string, feedback *TransactionFeedback) (interface{}, error) {
+	return i.provideTransactionFeedback(ctx, transactionID, TransactionFeedbackTypeFraud, feedback)
+}
+
+// ReportTransactionChargeback marks the given transaction as charged back; see https://edoc.identitymind.com/reference#feedback
+func (i *IdentityMindAPIClient) ReportTransactionChargeback(transactionID string, feedback *TransactionFeedback) (interface{}, error) {
+	return i.ReportTransactionChargebackWithContext(context.Background(), transactionID, feedback)
+}
+
+// ReportTransactionChargebackWithContext marks the given transaction as charged back; see https://edoc.identitymind.com/reference#feedback
+func (i *IdentityMindAPIClient) ReportTransactionChargebackWithContext(ctx context.Context, transactionID string, feedback *TransactionFeedback) (interface{}, error) {
+	return i.provideTransactionFeedback(ctx, transactionID, TransactionFeedbackTypeChargeback, feedback)
+}
+
+func (i *IdentityMindAPIClient) provideTransactionFeedback(ctx context.Context, transactionID string, feedbackType TransactionFeedbackType, feedback *TransactionFeedback) (interface{}, error) {
+	if transactionID == "" {
+		return nil, fmt.Errorf("Failed to provide %s feedback for tx; transaction id is required", feedbackType)
+	}
+	params, err := feedback.Params(feedbackType)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to provide %s feedback for tx %s; %s", feedbackType, transactionID, err.Error())
+	}
 	var resp map[string]interface{}
-	status, err := i.Post(fmt.Sprintf("im/account/%s?graphScoreResponse=false", txType), params, &resp)
+	status, err := i.PostWithContext(ctx, fmt.Sprintf("im/transaction/%s/%s", transactionID, feedbackType), params, &resp)
 	if err != nil {
-		return nil, fmt.Errorf("Failed to report tx via identitymind API; status: %d; %s", status, err.Error())
+		return nil, fmt.Errorf("Failed to provide %s feedback for tx via identitymind API; status: %d; %w", feedbackType, status, err)
 	}
 	return resp, nil
 }
+
+// Params validates the feedback and converts it to the params accepted by the API client; a valid reason
+// code is required for fraud and chargeback feedback, and a description is required for TransactionFeedbackReasonOther
+func (f *TransactionFeedback) Params(feedbackType TransactionFeedbackType) (map[string]interface{}, error) {
+	if f == nil {
+		f = &TransactionFeedback{}
+	}
+	if f.Reason == "" && (feedbackType == TransactionFeedbackTypeFraud || feedbackType == TransactionFeedbackTypeChargeback) {
+		return nil, fmt.Errorf("reason is required")
+	}
+	if f.Reason != "" && !f.Reason.IsValid() {
+		return nil, fmt.Errorf("invalid reason: %s", f.Reason)
+	}
+	if f.Reason == TransactionFeedbackReasonOther && f.Description == "" {
+		return nil, fmt.Errorf("description is required when reason is %s", TransactionFeedbackReasonOther)
+	}
+	if f.Amount != "" && feedbackType != TransactionFeedbackTypeChargeback {
+		return nil, fmt.Errorf("amount is only applicable to %s feedback", TransactionFeedbackTypeChargeback)
+	}
+	return marshalParams(f)
+}
@@ -0,0 +1,190 @@
+package identitymind
+
+import (
+	"testing"
+)
+
+func TestTransactionRequestValidate(t *testing.T) {
+	tests := []struct {
+		name    string
+		request TransactionRequest
+		valid   bool
+	}{
+		{"valid", TransactionRequest{AccountName: "alice", Amount: "25.00", Currency: "USD"}, true},
+		{"digital currency", TransactionRequest{AccountName: "alice", Amount: "0.5", Currency: "USDT"}, true},
+		{"missing account name", TransactionRequest{Amount: "25.00", Currency: "USD"}, false},
+		{"missing amount", TransactionRequest{AccountName: "alice", Currency: "USD"}, false},
+		{"zero amount", TransactionRequest{AccountName: "alice", Amount: "0", Currency: "USD"}, false},
+		{"negative amount", TransactionRequest{AccountName: "alice", Amount: "-1", Currency: "USD"}, false},
+		{"malformed amount", TransactionRequest{AccountName: "alice", Amount: "$25", Currency: "USD"}, false},
+		{"lowercase currency", TransactionRequest{AccountName: "alice", Amount: "25.00", Currency: "usd"}, false},
+		{"short currency", TransactionRequest{AccountName: "alice", Amount: "25.00", Currency: "US"}, false},
+		{"long currency", TransactionRequest{AccountName: "alice", Amount: "25.00", Currency: "DOLLARS"}, false},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			err := test.request.Validate()
+			if (err == nil) != test.valid {
+				t.Fatalf("expected valid=%v; got %v", test.valid, err)
+			}
+		})
+	}
+}
+
+func TestPaymentTransactionValidate(t *testing.T) {
+	var nilPayment *PaymentTransaction
+	tests := []struct {
+		name    string
+		payment *PaymentTransaction
+		valid   bool
+	}{
+		{"nil", nilPayment, false},
+		{"valid", &PaymentTransaction{TransactionRequest: TransactionRequest{AccountName: "alice", Amount: "25.00", Currency: "USD"}, FirstName: "Alice"}, true},
+		{"invalid request", &PaymentTransaction{TransactionRequest: TransactionRequest{AccountName: "alice", Currency: "USD"}}, false},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			err := test.payment.Validate()
+			if (err == nil) != test.valid {
+				t.Fatalf("expected valid=%v; got %v", test.valid, err)
+			}
+		})
+	}
+}
+
+func TestTransferParams(t *testing.T) {
+	request := TransactionRequest{AccountName: "alice", Amount: "25.00", Currency: "USD"}
+	var nilTransferIn *TransferIn
+	var nilTransferOut *TransferOut
+	var nilTransfer *Transfer
+
+	tests := []struct {
+		name     string
+		transfer TransferRequest
+		expected map[string]interface{} // subset of the expected params; nil when the transfer is invalid
+	}{
+		{"nil", nil, nil},
+		{"nil deposit", nilTransferIn, nil},
+		{"nil withdrawal", nilTransferOut, nil},
+		{"nil transfer", nilTransfer, nil},
+		{"card deposit with nil digital currency details", &TransferIn{
+			TransactionRequest: request,
+			PaymentInstrument:  PaymentInstrument{InstrumentType: PaymentInstrumentTypeCard, CardToken: "411111XXXXXX1111"},
+		}, map[string]interface{}{"man": "alice", "amt": "25.00", "ccy": "USD", "pm": "CARD", "pcct": "411111XXXXXX1111"}},
+		{"bank withdrawal", &TransferOut{
+			TransactionRequest: request,
+			PaymentInstrument:  PaymentInstrument{InstrumentType: PaymentInstrumentTypeBankAccount, BankCountry: "US"},
+		}, map[string]interface{}{"man": "alice", "pm": "BANK", "pbco": "US"}},
+		{"digital currency deposit without details", &TransferIn{
+			TransactionRequest: request,
+			PaymentInstrument:  PaymentInstrument{InstrumentType: PaymentInstrumentTypeDigitalCurrency},
+		}, nil},
+		{"digital currency details with card instrument", &TransferIn{
+			TransactionRequest:      request,
+			PaymentInstrument:       PaymentInstrument{InstrumentType: PaymentInstrumentTypeCard},
+			DigitalCurrencyTransfer: &DigitalCurrencyTransfer{Asset: "BTC", SourceAddress: "1A1zP1eP5QGefi2DMPTfTL5SLmv7DivfNa"},
+		}, nil},
+		{"digital currency withdrawal", &TransferOut{
+			TransactionRequest:      TransactionRequest{AccountName: "alice", Amount: "0.5", Currency: "BTC"},
+			PaymentInstrument:       PaymentInstrument{InstrumentType: PaymentInstrumentTypeDigitalCurrency},
+			DigitalCurrencyTransfer: &DigitalCurrencyTransfer{Asset: "BTC", DestinationAddress: "1A1zP1eP5QGefi2DMPTfTL5SLmv7DivfNa"},
+		}, map[string]interface{}{"man": "alice", "amt": "0.5", "ccy": "BTC", "pm": "DC"}},
+		{"invalid digital currency address", &TransferOut{
+			TransactionRequest:      TransactionRequest{AccountName: "alice", Amount: "0.5", Currency: "BTC"},
+			PaymentInstrument:       PaymentInstrument{InstrumentType: PaymentInstrumentTypeDigitalCurrency},
+			DigitalCurrencyTransfer: &DigitalCurrencyTransfer{Asset: "BTC", DestinationAddress: "1A1zP1eP5QGefi2DMPTfTL5SLmv7DivfNb"},
+		}, nil},
+		{"transfer", &Transfer{TransactionRequest: request, DestinationAccountName: "bob"}, map[string]interface{}{"man": "alice", "dman": "bob"}},
+		{"transfer to the same account", &Transfer{TransactionRequest: request, DestinationAccountName: "alice"}, nil},
+		{"transfer without destination", &Transfer{TransactionRequest: request}, nil},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			params, err := transferParams(test.transfer, nil)
+			if test.expected == nil {
+				if err == nil {
+					t.Fatalf("expected an invalid transfer; got %v", params)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			for key, val := range test.expected {
+				if params[key] != val {
+					t.Fatalf("expected %s to be %v; got %v", key, val, params[key])
+				}
+			}
+		})
+	}
+}
+
+func TestTransactionFeedbackParams(t *testing.T) {
+	tests := []struct {
+		name         string
+		feedbackType TransactionFeedbackType
+		feedback     *TransactionFeedback
+		expected     map[string]interface{}
+		valid        bool
+	}{
+		{"nil accepted", TransactionFeedbackTypeAccepted, nil, map[string]interface{}{}, true},
+		{"rejected", TransactionFeedbackTypeRejected, &TransactionFeedback{Reason: TransactionFeedbackReasonPolicyViolation, Analyst: "jdoe"}, map[string]interface{}{"reason": "POLICY_VIOLATION", "analyst": "jdoe"}, true},
+		{"fraud", TransactionFeedbackTypeFraud, &TransactionFeedback{Reason: TransactionFeedbackReasonStolenInstrument}, map[string]interface{}{"reason": "STOLEN_INSTRUMENT"}, true},
+		{"fraud without reason", TransactionFeedbackTypeFraud, nil, nil, false},
+		{"chargeback", TransactionFeedbackTypeChargeback, &TransactionFeedback{Reason: TransactionFeedbackReasonNotReceived, Amount: "5", Currency: "USD"}, map[string]interface{}{"reason": "NOT_RECEIVED", "amt": "5", "ccy": "USD"}, true},
+		{"chargeback without reason", TransactionFeedbackTypeChargeback, &TransactionFeedback{Amount: "5"}, nil, false},
+		{"invalid reason", TransactionFeedbackTypeRejected, &TransactionFeedback{Reason: "chargeback"}, nil, false},
+		{"other without description", TransactionFeedbackTypeFraud, &TransactionFeedback{Reason: TransactionFeedbackReasonOther}, nil, false},
+		{"other with description", TransactionFeedbackTypeFraud, &TransactionFeedback{Reason: TransactionFeedbackReasonOther, Description: "mule account"}, map[string]interface{}{"reason": "OTHER", "description": "mule account"}, true},
+		{"amount without chargeback", TransactionFeedbackTypeRejected, &TransactionFeedback{Amount: "5"}, nil, false},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			params, err := test.feedback.Params(test.feedbackType)
+			if !test.valid {
+				if err == nil {
+					t.Fatalf("expected invalid feedback; got %v", params)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(params) != len(test.expected) {
+				t.Fatalf("expected %v; got %v", test.expected, params)
+			}
+			for key, val := range test.expected {
+				if params[key] != val {
+					t.Fatalf("expected %s to be %v; got %v", key, val, params[key])
+				}
+			}
+		})
+	}
+}
+
+func TestTransferParamsOmitDigitalCurrencyTransfer(t *testing.T) {
+	confirmations := 3
+	params, err := transferParams(&TransferIn{
+		TransactionRequest: TransactionRequest{AccountName: "alice", Amount: "0.5", Currency: "ETH"},
+		PaymentInstrument:  PaymentInstrument{InstrumentType: PaymentInstrumentTypeDigitalCurrency},
+		DigitalCurrencyTransfer: &DigitalCurrencyTransfer{
+			Asset:         "ETH",
+			SourceAddress: "0x5aAeb6053F3E94C9b9A09f33669435E7Ef1BeAed",
+			Confirmations: &confirmations,
+		},
+	}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := map[string]interface{}{"man": "alice", "amt": "0.5", "ccy": "ETH", "pm": "DC"}
+	if len(params) != len(expected) {
+		t.Fatalf("expected only the documented fields to be submitted; got %v", params)
+	}
+	for key, val := range expected {
+		if params[key] != val {
+			t.Fatalf("expected %s to be %v; got %v", key, val, params[key])
+		}
+	}
+}
@@ -0,0 +1,210 @@
+package identitymind
+
+import (
+	"context"
+	"fmt"
+)
+
+// BusinessVerification aggregates the KYB application of a business and the KYC applications of
+// its beneficial owners into an overall decision
+type BusinessVerification struct {
+	Business *BusinessApplication           // evaluation of the KYB application
+	Owners   []*BeneficialOwnerVerification // per-owner detail, in the order the owners were provided
+	State    ApplicationState               // overall decision; see BusinessVerifier
+}
+
+// BeneficialOwnerVerification is the KYC detail of a single beneficial owner within a BusinessVerification
+type BeneficialOwnerVerification struct {
+	Owner         *BeneficialOwner // owner as provided to BusinessVerifier
+	ApplicationID string           // identifier of the owner's KYC application, once submitted or linked; referenced by the KYB application
+	Application   *KYCApplication  // most recently retrieved evaluation of the owner's KYC application
+	Linked        bool             // true if an existing KYC application was linked rather than submitted
+	Err           error            // error encountered submitting or retrieving the owner's KYC application
+}
+
+// CurrentState returns the state of the owner's KYC application, or an empty state if it has not been retrieved
+func (o *BeneficialOwnerVerification) CurrentState() ApplicationState {
+	if o.Application == nil {
+		return ApplicationState("")
+	}
+	return o.Application.CurrentState()
+}
+
+// IsAccepted returns true if the business and all of its beneficial owners have been accepted
+func (v *BusinessVerification) IsAccepted() bool {
+	return v.State.IsAccepted()
+}
+
+// IsRejected returns true if the business or any of its beneficial owners has been rejected
+func (v *BusinessVerification) IsRejected() bool {
+	return v.State.IsRejected()
+}
+
+// IsDecided returns true if an overall decision has been reached
+func (v *BusinessVerification) IsDecided() bool {
+	return v.State.IsTerminal()
+}
+
+// PendingOwners returns the beneficial owners whose KYC applications have not been decided
+func (v *BusinessVerification) PendingOwners() []*BeneficialOwnerVerification {
+	pending := make([]*BeneficialOwnerVerification, 0)
+	for _, owner := range v.Owners {
+		if !owner.CurrentState().IsTerminal() {
+			pending = append(pending, owner)
+		}
+	}
+	return pending
+}
+
+// RejectedOwners returns the beneficial owners whose KYC applications have been rejected
+func (v *BusinessVerification) RejectedOwners() []*BeneficialOwnerVerification {
+	rejected := make([]*BeneficialOwnerVerification, 0)
+	for _, owner := range v.Owners {
+		if owner.CurrentState().IsRejected() {
+			rejected = append(rejected, owner)
+		}
+	}
+	return rejected
+}
+
+// aggregate derives the overall state: rejected if the business or any owner was rejected, accepted
+// if the business and every owner were accepted, and otherwise under review
+func (v *BusinessVerification) aggregate() {
+	business := ApplicationState("")
+	if v.Business != nil {
+		business = v.Business.CurrentState()
+	}
+	if business.IsRejected() || len(v.RejectedOwners()) > 0 {
+		v.State = ApplicationStateRejected
+		return
+	}
+	if business.IsAccepted() && len(v.PendingOwners()) == 0 {
+		v.State = ApplicationStateAccepted
+		return
+	}
+	v.State = ApplicationStateUnderReview
+}
+
+// BusinessVerifier orchestrates the KYB application of a business along with the KYC applications
+// of each of its beneficial owners, since a business is only approved once every owner has passed KYC
+type BusinessVerifier struct {
+	KYC KYC
+	KYB KYB
+}
+
+// NewBusinessVerifier initializes a BusinessVerifier using the given KYC and KYB clients, i.e. a IdentityMindAPIClient
+func NewBusinessVerifier(kyc KYC, kyb KYB) *BusinessVerifier {
+	return &BusinessVerifier{
+		KYC: kyc,
+		KYB: kyb,
+	}
+}
+
+// Verify submits, or links, the KYC application of each beneficial owner and then submits the KYB application
+func (v *BusinessVerifier) Verify(application *BusinessApplicationRequest) (*BusinessVerification, error) {
+	return v.VerifyWithContext(context.Background(), application)
+}
+
+// VerifyWithContext submits, or links, the KYC application of each beneficial owner and then submits the
+// KYB application, in which each owner is sent as a reference to its KYC application rather than with its
+// KYC fields; if any owner fails, the KYB application is not submitted and the verification is returned
+// along with an error and the failed owners' Err fields set
+func (v *BusinessVerifier) VerifyWithContext(ctx context.Context, application *BusinessApplicationRequest) (*BusinessVerification, error) {
+	if application == nil {
+		return nil, fmt.Errorf("Failed to verify business; application is required")
+	}
+	if err := application.Validate(); err != nil {
+		return nil, fmt.Errorf("Failed to verify business; %s", err.Error())
+	}
+
+	verification := &BusinessVerification{
+		Owners: make([]*BeneficialOwnerVerification, 0, len(application.Owners)),
+	}
+	references := make([]*BeneficialOwner, 0, len(application.Owners))
+	for _, owner := range application.Owners {
+		ownerVerification := &BeneficialOwnerVerification{Owner: owner}
+		if owner.KYCApplicationID != "" {
+			ownerVerification.ApplicationID = owner.KYCApplicationID
+			ownerVerification.Linked = true
+			ownerVerification.Application, ownerVerification.Err = v.KYC.GetApplicationWithContext(ctx, owner.KYCApplicationID)
+		} else {
+			ownerVerification.Application, ownerVerification.Err = v.KYC.SubmitConsumerApplicationWithContext(ctx, &owner.ConsumerApplicationRequest)
+			if ownerVerification.Application != nil && ownerVerification.Application.TID != nil {
+				ownerVerification.ApplicationID = *ownerVerification.Application.TID
+			}
+		}
+		verification.Owners = append(verification.Owners, ownerVerification)
+		references = append(references, &BeneficialOwner{
+			OwnershipPercentage: owner.OwnershipPercentage,
+			KYCApplicationID:    ownerVerification.ApplicationID,
+		})
+	}
+	if err := verification.ownerErr(); err != nil {
+		verification.aggregate()
+		return verification, err
+	}
+	for idx, reference := range references {
+		if reference.KYCApplicationID == "" {
+			verification.aggregate()
+			return verification, fmt.Errorf("Failed to verify business; KYC application identifier of beneficial owner %d was not returned", idx)
+		}
+	}
+
+	business := *application
+	business.Owners = references
+	submitted, err := v.KYB.SubmitKYBApplicationWithContext(ctx, &business)
+	if err != nil {
+		verification.aggregate()
+		return verification, err
+	}
+	verification.Business = submitted
+	verification.aggregate()
+	return verification, nil
+}
+
+// Refresh retrieves the current state of the KYB application and each beneficial owner's KYC application
+func (v *BusinessVerifier) Refresh(verification *BusinessVerification) error {
+	return v.RefreshWithContext(context.Background(), verification)
+}
+
+// RefreshWithContext retrieves the current state of the KYB application and each beneficial owner's KYC
+// application and updates the overall decision; owners whose applications were never submitted are skipped
+func (v *BusinessVerifier) RefreshWithContext(ctx context.Context, verification *BusinessVerification) error {
+	if verification == nil || verification.Business == nil || verification.Business.TID == nil {
+		return fmt.Errorf("Failed to refresh business verification; KYB application identifier is required")
+	}
+	business, err := v.KYB.GetBusinessApplicationWithContext(ctx, *verification.Business.TID)
+	if err != nil {
+		return err
+	}
+	verification.Business = business
+
+	for _, owner := range verification.Owners {
+		if owner.ApplicationID == "" {
+			continue
+		}
+		application, err := v.KYC.GetApplicationWithContext(ctx, owner.ApplicationID)
+		owner.Err = err
+		if err == nil {
+			owner.Application = application
+		}
+	}
+
+	verification.aggregate()
+	return verification.ownerErr()
+}
+
+func (v *BusinessVerification) ownerErr() error {
+	failed := 0
+	var last error
+	for _, owner := range v.Owners {
+		if owner.Err != nil {
+			failed++
+			last = owner.Err
+		}
+	}
+	if failed == 0 {
+		return nil
+	}
+	return fmt.Errorf("Failed to verify %d of %d beneficial owners; %w", failed, len(v.Owners), last)
+}
@@ -0,0 +1,54 @@
+package identitymind_test
+
+import (
+	"testing"
+
+	identitymind "github.com/kthomas/identitymind-golang"
+	"github.com/kthomas/identitymind-golang/identitymindfake"
+)
+
+func TestBusinessVerifierSubmitsOwnerPIIOnce(t *testing.T) {
+	f := identitymindfake.New()
+	linked, err := f.SubmitConsumerApplication(&identitymind.ConsumerApplicationRequest{AccountName: "bob"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	verifier := identitymind.NewBusinessVerifier(f, f)
+	verification, err := verifier.Verify(&identitymind.BusinessApplicationRequest{
+		AccountName: "acme",
+		Owners: []*identitymind.BeneficialOwner{
+			{ConsumerApplicationRequest: identitymind.ConsumerApplicationRequest{AccountName: "alice", FirstName: "Alice"}, OwnershipPercentage: 50},
+			{KYCApplicationID: *linked.TID, OwnershipPercentage: 25},
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !verification.IsAccepted() {
+		t.Fatalf("expected the business to be accepted; got %s", verification.State)
+	}
+	if !verification.Owners[1].Linked {
+		t.Fatal("expected the second owner to be linked")
+	}
+
+	var business *identitymindfake.Application
+	for _, app := range f.Applications() {
+		if app.Kind == identitymindfake.ApplicationKindBusiness {
+			business = app
+		}
+	}
+	if business == nil {
+		t.Fatal("expected the KYB application to be submitted")
+	}
+	owners, _ := business.Params["owners"].([]interface{})
+	if len(owners) != 2 {
+		t.Fatalf("expected 2 owners; got %v", business.Params["owners"])
+	}
+	for idx, owner := range owners {
+		params := owner.(map[string]interface{})
+		if len(params) != 2 || params["tid"] != verification.Owners[idx].ApplicationID {
+			t.Fatalf("expected owner %d to reference its KYC application only; got %v", idx, params)
+		}
+	}
+}
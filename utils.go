@@ -1,8 +1,17 @@
 package identitymind
 
+import (
+	"github.com/kthomas/identitymind-golang/internal/apiparams"
+)
+
 func stringOrNil(str string) *string {
 	if str == "" {
 		return nil
 	}
 	return &str
 }
+
+// marshalParams converts the given JSON-tagged struct to the parameters map accepted by the API client
+func marshalParams(v interface{}) (map[string]interface{}, error) {
+	return apiparams.Marshal(v)
+}
@@ -0,0 +1,539 @@
+package identitymind
+
+import (
+	"container/list"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"path"
+	"strings"
+	"sync"
+	"time"
+)
+
+const defaultWebhookMaxBodySize = 10 * 1024 * 1024
+const defaultWebhookDeduplicationTTL = 24 * time.Hour
+
+// WebhookEventTypeUnknown is resolved for callbacks whose type cannot be determined; such callbacks are rejected
+const WebhookEventTypeUnknown WebhookEventType = ""
+
+// WebhookEventType identifies the kind of asynchronous callback delivered by identitymind
+type WebhookEventType string
+
+// WebhookEventTypeKYC is delivered when a consumer KYC application changes state
+const WebhookEventTypeKYC WebhookEventType = "kyc"
+
+// WebhookEventTypeKYB is delivered when a KYB application changes state
+const WebhookEventTypeKYB WebhookEventType = "kyb"
+
+// WebhookEventTypeMerchant is delivered when a merchant application changes state
+const WebhookEventTypeMerchant WebhookEventType = "merchant"
+
+// WebhookEventTypeCase is delivered when a case changes state
+const WebhookEventTypeCase WebhookEventType = "case"
+
+// WebhookEvent contains the attributes common to all identitymind callbacks
+type WebhookEvent struct {
+	ID         string           // identifier of the transition, i.e. kyc/<mtid>/<state>, or empty when the payload does not identify its subject
+	Type       WebhookEventType // kind of callback
+	SubjectID  string           // identifier of the application or case which changed state, if present in the payload
+	State      string           // state to which the subject moved, if present in the payload
+	ReceivedAt time.Time        // time at which the callback was received
+	Payload    json.RawMessage  // raw callback body
+}
+
+// KYCEvent is delivered when a consumer KYC application changes state
+type KYCEvent struct {
+	WebhookEvent
+	Application *KYCApplication
+}
+
+// KYBEvent is delivered when a KYB application changes state
+type KYBEvent struct {
+	WebhookEvent
+	Application *BusinessApplication
+}
+
+// MerchantEvent is delivered when a merchant application changes state
+type MerchantEvent struct {
+	WebhookEvent
+	MerchantID  string
+	Application *KYCApplication
+}
+
+// CaseEvent is delivered when a case changes state; since the published reference does not describe the
+// case representation, the state of the case is not decoded and case callbacks are not deduplicated
+type CaseEvent struct {
+	WebhookEvent
+	CaseID string // identifier of the case (caseId), if present in the payload
+	Case   *Case
+}
+
+// WebhookAuthenticator returns an error if the given callback request, whose body has already
+// been read, is not authentic
+type WebhookAuthenticator func(r *http.Request, body []byte) error
+
+// WebhookEventTypeResolver determines the type of the given callback request, returning
+// WebhookEventTypeUnknown when it cannot be determined
+type WebhookEventTypeResolver func(r *http.Request, body []byte) WebhookEventType
+
+// WebhookDeduplicator tracks the state in which each application or case was last successfully handled,
+// so that redeliveries of a transition are ignored while a later transition back to an earlier state,
+// i.e. accepted, then rejected, then accepted again, is handled
+type WebhookDeduplicator interface {
+	// Seen returns true if the subject, identified by the given key, was last handled in the given state
+	Seen(subject, state string) bool
+	// MarkSeen records that the subject was handled in the given state; it is called only after every
+	// registered handler func succeeds
+	MarkSeen(subject, state string)
+}
+
+// WebhookOption configures a WebhookHandler
+type WebhookOption func(*WebhookHandler)
+
+// WebhookHandler is an http.Handler which receives identitymind callbacks, authenticates and
+// deduplicates them, and dispatches typed events to the registered handler funcs. A non-2xx
+// status is returned when a handler func fails, or when a redelivery arrives while the same
+// transition is still being handled, so that identitymind will redeliver the callback.
+type WebhookHandler struct {
+	authenticator WebhookAuthenticator
+	deduplicator  WebhookDeduplicator
+	resolver      WebhookEventTypeResolver
+	maxBodySize   int64
+
+	inFlightMutex sync.Mutex
+	inFlight      map[string]bool
+
+	mutex            sync.RWMutex
+	kycHandlers      []func(context.Context, *KYCEvent) error
+	kybHandlers      []func(context.Context, *KYBEvent) error
+	merchantHandlers []func(context.Context, *MerchantEvent) error
+	caseHandlers     []func(context.Context, *CaseEvent) error
+}
+
+// NewWebhookHandler initializes a WebhookHandler; an authenticator must be configured using
+// WithWebhookAuthenticator, i.e. BasicAuthWebhookAuthenticator or HMACWebhookAuthenticator, as
+// callbacks decide applications. Unless otherwise configured, redeliveries are deduplicated in memory
+// for 24 hours, and the event type is resolved using DefaultWebhookEventTypeResolver
+func NewWebhookHandler(opts ...WebhookOption) (*WebhookHandler, error) {
+	h := &WebhookHandler{
+		deduplicator: NewMemoryWebhookDeduplicator(defaultWebhookDeduplicationTTL),
+		resolver:     DefaultWebhookEventTypeResolver,
+		maxBodySize:  defaultWebhookMaxBodySize,
+		inFlight:     map[string]bool{},
+	}
+	for _, opt := range opts {
+		opt(h)
+	}
+	if h.authenticator == nil {
+		return nil, fmt.Errorf("Failed to initialize identitymind webhook handler; an authenticator is required")
+	}
+	if h.resolver == nil {
+		return nil, fmt.Errorf("Failed to initialize identitymind webhook handler; an event type resolver is required")
+	}
+	return h, nil
+}
+
+// WithWebhookAuthenticator authenticates each callback using the given authenticator; it is required
+func WithWebhookAuthenticator(authenticator WebhookAuthenticator) WebhookOption {
+	return func(h *WebhookHandler) {
+		h.authenticator = authenticator
+	}
+}
+
+// WithWebhookDeduplicator tracks handled callbacks using the given deduplicator; nil disables deduplication
+func WithWebhookDeduplicator(deduplicator WebhookDeduplicator) WebhookOption {
+	return func(h *WebhookHandler) {
+		h.deduplicator = deduplicator
+	}
+}
+
+// WithWebhookEventTypeResolver determines the type of each callback using the given resolver
+func WithWebhookEventTypeResolver(resolver WebhookEventTypeResolver) WebhookOption {
+	return func(h *WebhookHandler) {
+		h.resolver = resolver
+	}
+}
+
+// WithWebhookMaxBodySize limits the size of accepted callback bodies
+func WithWebhookMaxBodySize(size int64) WebhookOption {
+	return func(h *WebhookHandler) {
+		h.maxBodySize = size
+	}
+}
+
+// OnKYC registers a handler func for consumer KYC callbacks
+func (h *WebhookHandler) OnKYC(fn func(context.Context, *KYCEvent) error) {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+	h.kycHandlers = append(h.kycHandlers, fn)
+}
+
+// OnKYB registers a handler func for KYB callbacks
+func (h *WebhookHandler) OnKYB(fn func(context.Context, *KYBEvent) error) {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+	h.kybHandlers = append(h.kybHandlers, fn)
+}
+
+// OnMerchant registers a handler func for merchant application callbacks
+func (h *WebhookHandler) OnMerchant(fn func(context.Context, *MerchantEvent) error) {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+	h.merchantHandlers = append(h.merchantHandlers, fn)
+}
+
+// OnCase registers a handler func for case callbacks
+func (h *WebhookHandler) OnCase(fn func(context.Context, *CaseEvent) error) {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+	h.caseHandlers = append(h.caseHandlers, fn)
+}
+
+// ServeHTTP implements http.Handler
+func (h *WebhookHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		w.Header().Set("Allow", http.MethodPost)
+		http.Error(w, http.StatusText(http.StatusMethodNotAllowed), http.StatusMethodNotAllowed)
+		return
+	}
+
+	body, err := ioutil.ReadAll(http.MaxBytesReader(w, r.Body, h.maxBodySize))
+	if err != nil {
+		log.Warningf("Failed to read identitymind callback; %s", err.Error())
+		http.Error(w, http.StatusText(http.StatusBadRequest), http.StatusBadRequest)
+		return
+	}
+
+	err = h.authenticator(r, body)
+	if err != nil {
+		log.Warningf("Rejected unauthenticated identitymind callback; %s", err.Error())
+		http.Error(w, http.StatusText(http.StatusUnauthorized), http.StatusUnauthorized)
+		return
+	}
+
+	evt := &WebhookEvent{
+		Type:       h.resolver(r, body),
+		ReceivedAt: time.Now(),
+		Payload:    json.RawMessage(body),
+	}
+	handle, err := h.parse(evt)
+	if err != nil {
+		log.Warningf("Failed to parse identitymind %s callback; %s", evt.Type, err.Error())
+		http.Error(w, http.StatusText(http.StatusBadRequest), http.StatusBadRequest)
+		return
+	}
+
+	deduplicate := h.deduplicator != nil && evt.ID != ""
+	if deduplicate {
+		subject := webhookSubject(evt.Type, evt.SubjectID)
+		if !h.claim(subject) {
+			log.Debugf("Deferring identitymind %s callback: %s; the subject is being handled", evt.Type, evt.ID)
+			http.Error(w, http.StatusText(http.StatusConflict), http.StatusConflict)
+			return
+		}
+		defer h.release(subject)
+
+		if h.deduplicator.Seen(subject, evt.State) {
+			log.Debugf("Ignoring redelivered identitymind %s callback: %s", evt.Type, evt.ID)
+			w.WriteHeader(http.StatusOK)
+			return
+		}
+	}
+
+	err = handle(r.Context())
+	if err != nil {
+		log.Warningf("Failed to handle identitymind %s callback: %s; %s", evt.Type, evt.ID, err.Error())
+		http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+		return
+	}
+
+	if deduplicate {
+		h.deduplicator.MarkSeen(webhookSubject(evt.Type, evt.SubjectID), evt.State)
+	}
+	w.WriteHeader(http.StatusOK)
+}
+
+// claim marks the given subject as being handled, returning false if it is already being handled
+func (h *WebhookHandler) claim(subject string) bool {
+	h.inFlightMutex.Lock()
+	defer h.inFlightMutex.Unlock()
+	if h.inFlight[subject] {
+		return false
+	}
+	h.inFlight[subject] = true
+	return true
+}
+
+// release marks the given subject as no longer being handled
+func (h *WebhookHandler) release(subject string) {
+	h.inFlightMutex.Lock()
+	defer h.inFlightMutex.Unlock()
+	delete(h.inFlight, subject)
+}
+
+// parse decodes the callback payload into a typed event, identifies the transition it describes, and
+// returns a func which invokes the handler funcs registered at the time of parsing
+func (h *WebhookHandler) parse(evt *WebhookEvent) (func(context.Context) error, error) {
+	h.mutex.RLock()
+	defer h.mutex.RUnlock()
+
+	var handle func(context.Context) error
+	switch evt.Type {
+	case WebhookEventTypeKYC:
+		var application *KYCApplication
+		err := json.Unmarshal(evt.Payload, &application)
+		if err != nil {
+			return nil, err
+		}
+		if application == nil {
+			application = &KYCApplication{}
+		}
+		evt.SubjectID, evt.State = applicationTransition(application.MTID, application.TID, application.State)
+		kycEvt := &KYCEvent{Application: application}
+		handlers := append([]func(context.Context, *KYCEvent) error{}, h.kycHandlers...)
+		handle = func(ctx context.Context) error {
+			kycEvt.WebhookEvent = *evt
+			for _, fn := range handlers {
+				err := fn(ctx, kycEvt)
+				if err != nil {
+					return err
+				}
+			}
+			return nil
+		}
+	case WebhookEventTypeKYB:
+		var application *BusinessApplication
+		err := json.Unmarshal(evt.Payload, &application)
+		if err != nil {
+			return nil, err
+		}
+		if application == nil {
+			application = &BusinessApplication{}
+		}
+		evt.SubjectID, evt.State = applicationTransition(application.MTID, application.TID, application.State)
+		kybEvt := &KYBEvent{Application: application}
+		handlers := append([]func(context.Context, *KYBEvent) error{}, h.kybHandlers...)
+		handle = func(ctx context.Context) error {
+			kybEvt.WebhookEvent = *evt
+			for _, fn := range handlers {
+				err := fn(ctx, kybEvt)
+				if err != nil {
+					return err
+				}
+			}
+			return nil
+		}
+	case WebhookEventTypeMerchant:
+		var payload struct {
+			MerchantID string `json:"m"`
+		}
+		var application *KYCApplication
+		err := json.Unmarshal(evt.Payload, &application)
+		if err == nil {
+			err = json.Unmarshal(evt.Payload, &payload)
+		}
+		if err != nil {
+			return nil, err
+		}
+		if application == nil {
+			application = &KYCApplication{}
+		}
+		evt.SubjectID, evt.State = applicationTransition(application.MTID, application.TID, application.State)
+		if evt.SubjectID != "" && payload.MerchantID != "" {
+			evt.SubjectID = fmt.Sprintf("%s/%s", payload.MerchantID, evt.SubjectID)
+		}
+		merchantEvt := &MerchantEvent{MerchantID: payload.MerchantID, Application: application}
+		handlers := append([]func(context.Context, *MerchantEvent) error{}, h.merchantHandlers...)
+		handle = func(ctx context.Context) error {
+			merchantEvt.WebhookEvent = *evt
+			for _, fn := range handlers {
+				err := fn(ctx, merchantEvt)
+				if err != nil {
+					return err
+				}
+			}
+			return nil
+		}
+	case WebhookEventTypeCase:
+		var payload *Case
+		err := json.Unmarshal(evt.Payload, &payload)
+		if err != nil {
+			return nil, err
+		}
+		if payload == nil {
+			payload = &Case{}
+		}
+		caseEvt := &CaseEvent{Case: payload}
+		if payload.ID != nil {
+			caseEvt.CaseID = *payload.ID
+			evt.SubjectID = caseEvt.CaseID
+		}
+		handlers := append([]func(context.Context, *CaseEvent) error{}, h.caseHandlers...)
+		handle = func(ctx context.Context) error {
+			caseEvt.WebhookEvent = *evt
+			for _, fn := range handlers {
+				err := fn(ctx, caseEvt)
+				if err != nil {
+					return err
+				}
+			}
+			return nil
+		}
+	default:
+		return nil, fmt.Errorf("Unsupported identitymind callback type: %q", evt.Type)
+	}
+
+	evt.ID = webhookEventID(evt.Type, evt.SubjectID, evt.State)
+	return handle, nil
+}
+
+// applicationTransition returns the identifier of the application, i.e. its mtid or, failing that,
+// its tid, and the state to which it moved; both are empty unless the payload carries an identifier and a state
+func applicationTransition(mtid, tid *string, state *ApplicationState) (string, string) {
+	if state == nil || *state == "" {
+		return "", ""
+	}
+	if mtid != nil && *mtid != "" {
+		return *mtid, string(*state)
+	}
+	if tid != nil && *tid != "" {
+		return *tid, string(*state)
+	}
+	return "", ""
+}
+
+// DefaultWebhookEventTypeResolver resolves the type of a callback from its type query parameter
+// or, failing that, the final segment of the callback URL path, i.e. /identitymind/callbacks/kyb;
+// WebhookEventTypeUnknown is returned for callbacks of indeterminate type
+func DefaultWebhookEventTypeResolver(r *http.Request, body []byte) WebhookEventType {
+	candidates := []string{r.URL.Query().Get("type"), path.Base(r.URL.Path)}
+	for _, candidate := range candidates {
+		switch evtType := WebhookEventType(strings.ToLower(candidate)); evtType {
+		case WebhookEventTypeKYC, WebhookEventTypeKYB, WebhookEventTypeMerchant, WebhookEventTypeCase:
+			return evtType
+		}
+	}
+	return WebhookEventTypeUnknown
+}
+
+// BasicAuthWebhookAuthenticator authenticates callbacks using HTTP basic authentication,
+// as configured for the callback URL in the identitymind portal
+func BasicAuthWebhookAuthenticator(username, password string) WebhookAuthenticator {
+	return func(r *http.Request, body []byte) error {
+		user, pass, ok := r.BasicAuth()
+		if !ok {
+			return fmt.Errorf("Missing basic authorization")
+		}
+		userOk := subtle.ConstantTimeCompare([]byte(user), []byte(username)) == 1
+		passOk := subtle.ConstantTimeCompare([]byte(pass), []byte(password)) == 1
+		if !userOk || !passOk {
+			return fmt.Errorf("Invalid basic authorization")
+		}
+		return nil
+	}
+}
+
+// HMACWebhookAuthenticator authenticates callbacks carrying a hex-encoded HMAC-SHA256
+// signature of the request body, computed using the given secret, in the given header
+func HMACWebhookAuthenticator(secret []byte, header string) WebhookAuthenticator {
+	return func(r *http.Request, body []byte) error {
+		signature, err := hex.DecodeString(strings.TrimPrefix(r.Header.Get(header), "sha256="))
+		if err != nil || len(signature) == 0 {
+			return fmt.Errorf("Missing or malformed %s signature header", header)
+		}
+		mac := hmac.New(sha256.New, secret)
+		mac.Write(body)
+		if !hmac.Equal(signature, mac.Sum(nil)) {
+			return fmt.Errorf("Invalid %s signature", header)
+		}
+		return nil
+	}
+}
+
+// webhookEventID returns the identifier of the transition of the given subject to the given state, or
+// an empty identifier when the subject or state is unknown
+func webhookEventID(evtType WebhookEventType, subjectID, state string) string {
+	if subjectID == "" || state == "" {
+		return ""
+	}
+	return fmt.Sprintf("%s/%s/%s", evtType, subjectID, state)
+}
+
+// webhookSubject returns the key under which the state of the given subject is deduplicated
+func webhookSubject(evtType WebhookEventType, subjectID string) string {
+	return fmt.Sprintf("%s/%s", evtType, subjectID)
+}
+
+// MemoryWebhookDeduplicator is an in-memory WebhookDeduplicator which remembers the state in which
+// each subject was last handled for a fixed duration; it is suitable for single-instance deployments
+type MemoryWebhookDeduplicator struct {
+	ttl     time.Duration
+	mutex   sync.Mutex
+	handled map[string]*list.Element
+	expiry  *list.List // handled entries, ordered by expiration as the duration is fixed
+}
+
+type memoryWebhookDeduplicatorEntry struct {
+	subject   string
+	state     string
+	expiresAt time.Time
+}
+
+// NewMemoryWebhookDeduplicator initializes a MemoryWebhookDeduplicator which remembers handled states for the given duration
+func NewMemoryWebhookDeduplicator(ttl time.Duration) *MemoryWebhookDeduplicator {
+	return &MemoryWebhookDeduplicator{
+		ttl:     ttl,
+		handled: map[string]*list.Element{},
+		expiry:  list.New(),
+	}
+}
+
+// Seen implements WebhookDeduplicator
+func (d *MemoryWebhookDeduplicator) Seen(subject, state string) bool {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+
+	d.expire(time.Now())
+	elem, ok := d.handled[subject]
+	return ok && elem.Value.(*memoryWebhookDeduplicatorEntry).state == state
+}
+
+// MarkSeen implements WebhookDeduplicator
+func (d *MemoryWebhookDeduplicator) MarkSeen(subject, state string) {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+
+	now := time.Now()
+	d.expire(now)
+	if elem, ok := d.handled[subject]; ok {
+		entry := elem.Value.(*memoryWebhookDeduplicatorEntry)
+		entry.state = state
+		entry.expiresAt = now.Add(d.ttl)
+		d.expiry.MoveToBack(elem)
+		return
+	}
+	d.handled[subject] = d.expiry.PushBack(&memoryWebhookDeduplicatorEntry{
+		subject:   subject,
+		state:     state,
+		expiresAt: now.Add(d.ttl),
+	})
+}
+
+// expire forgets the entries which have expired as of the given time; only expired entries are visited
+func (d *MemoryWebhookDeduplicator) expire(now time.Time) {
+	for elem := d.expiry.Front(); elem != nil; elem = d.expiry.Front() {
+		entry := elem.Value.(*memoryWebhookDeduplicatorEntry)
+		if !now.After(entry.expiresAt) {
+			return
+		}
+		d.expiry.Remove(elem)
+		delete(d.handled, entry.subject)
+	}
+}
@@ -0,0 +1,265 @@
+package identitymind
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func newTestWebhookHandler(t *testing.T, opts ...WebhookOption) *WebhookHandler {
+	opts = append([]WebhookOption{WithWebhookAuthenticator(BasicAuthWebhookAuthenticator("identitymind", "secret"))}, opts...)
+	h, err := NewWebhookHandler(opts...)
+	if err != nil {
+		t.Fatalf("failed to initialize webhook handler; %s", err.Error())
+	}
+	return h
+}
+
+func deliverWebhook(h http.Handler, path, body string) int {
+	req := httptest.NewRequest(http.MethodPost, path, strings.NewReader(body))
+	req.SetBasicAuth("identitymind", "secret")
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+	return rec.Code
+}
+
+func TestNewWebhookHandlerRequiresAuthenticator(t *testing.T) {
+	_, err := NewWebhookHandler()
+	if err == nil {
+		t.Fatal("expected an error when no authenticator is configured")
+	}
+}
+
+func TestWebhookHandlerRejectsUnauthenticatedCallbacks(t *testing.T) {
+	h := newTestWebhookHandler(t)
+	req := httptest.NewRequest(http.MethodPost, "/callbacks/kyc", strings.NewReader(`{"mtid":"1","state":"A"}`))
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+	if rec.Code != http.StatusUnauthorized {
+		t.Fatalf("expected 401; got %d", rec.Code)
+	}
+}
+
+func TestWebhookEventID(t *testing.T) {
+	tests := []struct {
+		evtType   WebhookEventType
+		subjectID string
+		state     string
+		expected  string
+	}{
+		{WebhookEventTypeKYC, "mtid-1", "A", "kyc/mtid-1/A"},
+		{WebhookEventTypeKYB, "mtid-1", "R", "kyb/mtid-1/R"},
+		{WebhookEventTypeMerchant, "m-1/mtid-1", "D", "merchant/m-1/mtid-1/D"},
+		{WebhookEventTypeKYC, "", "A", ""},
+		{WebhookEventTypeKYC, "mtid-1", "", ""},
+	}
+	for _, test := range tests {
+		id := webhookEventID(test.evtType, test.subjectID, test.state)
+		if id != test.expected {
+			t.Errorf("webhookEventID(%s, %s, %s) = %q; expected %q", test.evtType, test.subjectID, test.state, id, test.expected)
+		}
+	}
+}
+
+func TestWebhookHandlerDeduplicatesTransitions(t *testing.T) {
+	h := newTestWebhookHandler(t)
+	var states []ApplicationState
+	h.OnKYC(func(ctx context.Context, evt *KYCEvent) error {
+		states = append(states, *evt.Application.State)
+		return nil
+	})
+
+	deliveries := []struct {
+		body     string
+		expected int
+	}{
+		{`{"mtid":"1","state":"A"}`, http.StatusOK},
+		{`{"mtid":"1","state":"A"}`, http.StatusOK}, // redelivery
+		{`{"mtid":"1","state":"D"}`, http.StatusOK},
+		{`{"mtid":"1","state":"A"}`, http.StatusOK}, // decision reversed with an identical payload
+		{`{"mtid":"2","state":"A"}`, http.StatusOK},
+		{`{"state":"A"}`, http.StatusOK}, // unidentified subjects are never deduplicated
+		{`{"state":"A"}`, http.StatusOK},
+	}
+	for i, delivery := range deliveries {
+		status := deliverWebhook(h, "/callbacks/kyc", delivery.body)
+		if status != delivery.expected {
+			t.Fatalf("delivery %d: expected %d; got %d", i, delivery.expected, status)
+		}
+	}
+
+	expected := "A,D,A,A,A,A"
+	actual := make([]string, len(states))
+	for i, state := range states {
+		actual[i] = string(state)
+	}
+	if strings.Join(actual, ",") != expected {
+		t.Fatalf("expected handled states %s; got %s", expected, strings.Join(actual, ","))
+	}
+}
+
+func TestWebhookHandlerRedeliversAfterFailure(t *testing.T) {
+	h := newTestWebhookHandler(t)
+	calls := 0
+	h.OnKYB(func(ctx context.Context, evt *KYBEvent) error {
+		calls++
+		if calls == 1 {
+			return fmt.Errorf("transient failure")
+		}
+		return nil
+	})
+
+	body := `{"mtid":"1","state":"R"}`
+	if status := deliverWebhook(h, "/callbacks/kyb", body); status != http.StatusInternalServerError {
+		t.Fatalf("expected 500; got %d", status)
+	}
+	if status := deliverWebhook(h, "/callbacks/kyb", body); status != http.StatusOK {
+		t.Fatalf("expected 200; got %d", status)
+	}
+	if status := deliverWebhook(h, "/callbacks/kyb", body); status != http.StatusOK {
+		t.Fatalf("expected 200; got %d", status)
+	}
+	if calls != 2 {
+		t.Fatalf("expected the handler to be invoked twice; got %d", calls)
+	}
+}
+
+func TestWebhookHandlerDefersConcurrentRedelivery(t *testing.T) {
+	h := newTestWebhookHandler(t)
+	started := make(chan struct{})
+	proceed := make(chan struct{})
+	h.OnKYC(func(ctx context.Context, evt *KYCEvent) error {
+		close(started)
+		<-proceed
+		return nil
+	})
+
+	body := `{"mtid":"1","state":"A"}`
+	var wg sync.WaitGroup
+	wg.Add(1)
+	var first int
+	go func() {
+		defer wg.Done()
+		first = deliverWebhook(h, "/callbacks/kyc", body)
+	}()
+
+	<-started
+	if status := deliverWebhook(h, "/callbacks/kyc", body); status != http.StatusConflict {
+		t.Fatalf("expected 409 for a redelivery while the first delivery is in flight; got %d", status)
+	}
+	close(proceed)
+	wg.Wait()
+	if first != http.StatusOK {
+		t.Fatalf("expected 200; got %d", first)
+	}
+}
+
+func TestWebhookHandlerRegistrationFromHandler(t *testing.T) {
+	h := newTestWebhookHandler(t)
+	h.OnCase(func(ctx context.Context, evt *CaseEvent) error {
+		h.OnCase(func(ctx context.Context, evt *CaseEvent) error {
+			return nil
+		})
+		return nil
+	})
+
+	done := make(chan int)
+	go func() {
+		done <- deliverWebhook(h, "/callbacks/case", `{}`)
+	}()
+	select {
+	case status := <-done:
+		if status != http.StatusOK {
+			t.Fatalf("expected 200; got %d", status)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("registering a handler func from within a handler func deadlocked")
+	}
+}
+
+func TestWebhookHandlerCaseEvent(t *testing.T) {
+	h := newTestWebhookHandler(t, WithWebhookDeduplicator(NewMemoryWebhookDeduplicator(time.Hour)))
+	var events []*CaseEvent
+	h.OnCase(func(ctx context.Context, evt *CaseEvent) error {
+		events = append(events, evt)
+		return nil
+	})
+
+	body := `{"caseId":"case-1","state":"CLOSED","owner":"analyst"}`
+	for idx := 0; idx < 2; idx++ {
+		if status := deliverWebhook(h, "/callbacks/case", body); status != http.StatusOK {
+			t.Fatalf("expected 200; got %d", status)
+		}
+	}
+	if len(events) != 2 {
+		t.Fatalf("expected case callbacks not to be deduplicated; got %d events", len(events))
+	}
+	evt := events[0]
+	if evt.CaseID != "case-1" || evt.SubjectID != "case-1" || evt.ID != "" {
+		t.Fatalf("expected the case id to identify the subject without a transition; got %+v", evt.WebhookEvent)
+	}
+	if evt.Case == nil || evt.Case.Raw["owner"] != "analyst" {
+		t.Fatalf("expected the case payload to be retained; got %v", evt.Case)
+	}
+}
+
+func TestWebhookHandlerRejectsUnknownType(t *testing.T) {
+	h := newTestWebhookHandler(t)
+	called := false
+	h.OnKYC(func(ctx context.Context, evt *KYCEvent) error {
+		called = true
+		return nil
+	})
+	if status := deliverWebhook(h, "/callbacks", `{"mtid":"1","state":"A"}`); status != http.StatusBadRequest {
+		t.Fatalf("expected 400; got %d", status)
+	}
+	if called {
+		t.Fatal("expected a callback of unknown type not to be dispatched")
+	}
+}
+
+func TestDefaultWebhookEventTypeResolver(t *testing.T) {
+	tests := []struct {
+		target   string
+		expected WebhookEventType
+	}{
+		{"/callbacks/kyc", WebhookEventTypeKYC},
+		{"/callbacks/KYB", WebhookEventTypeKYB},
+		{"/callbacks?type=merchant", WebhookEventTypeMerchant},
+		{"/callbacks/case", WebhookEventTypeCase},
+		{"/callbacks", WebhookEventTypeUnknown},
+	}
+	for _, test := range tests {
+		req := httptest.NewRequest(http.MethodPost, test.target, nil)
+		evtType := DefaultWebhookEventTypeResolver(req, nil)
+		if evtType != test.expected {
+			t.Errorf("DefaultWebhookEventTypeResolver(%s) = %q; expected %q", test.target, evtType, test.expected)
+		}
+	}
+}
+
+func TestMemoryWebhookDeduplicatorExpires(t *testing.T) {
+	d := NewMemoryWebhookDeduplicator(20 * time.Millisecond)
+	d.MarkSeen("kyc/mtid-1", "R")
+	d.MarkSeen("kyc/mtid-2", "A")
+	d.MarkSeen("kyc/mtid-1", "A")
+	if !d.Seen("kyc/mtid-1", "A") || d.Seen("kyc/mtid-1", "R") || !d.Seen("kyc/mtid-2", "A") {
+		t.Fatal("expected the last handled state of each subject to be seen")
+	}
+	if d.expiry.Len() != 2 || d.expiry.Back().Value.(*memoryWebhookDeduplicatorEntry).subject != "kyc/mtid-1" {
+		t.Fatalf("expected the re-marked subject to expire last")
+	}
+
+	time.Sleep(30 * time.Millisecond)
+	if d.Seen("kyc/mtid-1", "A") || d.Seen("kyc/mtid-2", "A") {
+		t.Fatal("expected handled states to expire")
+	}
+	if len(d.handled) != 0 || d.expiry.Len() != 0 {
+		t.Fatalf("expected expired entries to be forgotten; %d remain", len(d.handled))
+	}
+}